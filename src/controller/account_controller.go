@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// AuditUserErased is the action recorded in the audit log, when the data of
+// a user is erased.
+const AuditUserErased = "user.erased"
+
+// AuditRecord is an entry of the audit log. The details must not contain
+// personal data, because the records are kept, when the data of the user is
+// erased.
+type AuditRecord struct {
+	Id        int64                  `db:"id" json:"id"`
+	UserId    int                    `db:"user_id" json:"userId"`
+	Action    string                 `db:"action" json:"action"`
+	Details   map[string]interface{} `db:"details" json:"details"`
+	CreatedAt time.Time              `db:"created_at" json:"createdAt"`
+}
+
+// UserTable holds the rows of a table, which belong to a user. Every row is
+// a JSON object of the columns of the table.
+type UserTable struct {
+	Name string
+	Rows []json.RawMessage
+}
+
+// ErasedUser describes the erased data of a user. Rows counts the deleted
+// rows by table. BlobKeys are the keys of the files of the attachments,
+// document versions and job posting snapshots, which have to be deleted by
+// the caller.
+type ErasedUser struct {
+	Rows     map[string]int64
+	BlobKeys []string
+}
+
+// userTable describes how the rows of a user are found in a table.
+type userTable struct {
+	name  string
+	where string
+	order string
+}
+
+const ownedApplications = "application_id IN (SELECT id FROM application WHERE user_id = $1)"
+
+// userTables lists the tables, which contain data of the users. Rows are
+// deleted in the reverse order, so referenced rows are deleted last. The
+// default work types and statuses do not belong to a user.
+var userTables = []userTable{
+	{"application_status", "user_id = $1", "id"},
+	{"status_transition", "user_id = $1", "id"},
+	{"company", "user_id = $1", "id"},
+	{"contact", "user_id = $1", "id"},
+	{"document", "user_id = $1", "id"},
+	{"document_version", "document_id IN (SELECT id FROM document WHERE user_id = $1)", "id"},
+	{"tag", "user_id = $1", "id"},
+	{"custom_field", "user_id = $1", "id"},
+	{"application", "user_id = $1", "id"},
+	{"application_status_history", ownedApplications, "id"},
+	{"interview", ownedApplications, "id"},
+	{"application_contact", ownedApplications, "application_id, contact_id"},
+	{"attachment", "user_id = $1", "id"},
+	{"application_tag", ownedApplications, "application_id, tag_id"},
+	{"application_custom_field", ownedApplications, "application_id, field_id"},
+	{"reminder", ownedApplications, "id"},
+	{"notification_preference", "user_id = $1", "user_id"},
+	{"webhook", "user_id = $1", "id"},
+	{"outbox_event", "user_id = $1", "id"},
+	{"webhook_delivery", "webhook_id IN (SELECT id FROM webhook WHERE user_id = $1)", "id"},
+	{"calendar_token", "user_id = $1", "user_id"},
+	{"job_posting_snapshot", "user_id = $1", "id"},
+}
+
+// exportedTables are the userTables and the audit log, which is exported, but
+// kept, when the data of the user is erased.
+var exportedTables = append(append([]userTable{}, userTables...), userTable{"audit_log", "user_id = $1", "id"})
+
+type AccountController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+// ExportUserData returns the rows of the user in all tables. The tables are
+// read in a single transaction, so the export is consistent. The generated
+// search vector of the applications is left out.
+func (c AccountController) ExportUserData(userId int) ([]UserTable, error) {
+	var tables []UserTable
+
+	err := c.Database.BeginTxFunc(c.Context, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, func(tx pgx.Tx) error {
+		for _, table := range exportedTables {
+			rows, err := tx.Query(c.Context,
+				"SELECT to_jsonb(t) - 'search_vector' FROM "+table.name+" t WHERE "+table.where+" ORDER BY "+table.order, userId)
+			if err != nil {
+				return err
+			}
+
+			userTable := UserTable{Name: table.name, Rows: []json.RawMessage{}}
+			for rows.Next() {
+				var row json.RawMessage
+				if err := rows.Scan(&row); err != nil {
+					rows.Close()
+					return err
+				}
+
+				userTable.Rows = append(userTable.Rows, row)
+			}
+
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			tables = append(tables, userTable)
+		}
+
+		return nil
+	})
+
+	return tables, err
+}
+
+// EraseUserData deletes the rows of the user in all tables in a single
+// transaction and records the erasure along with the number of deleted rows
+// in the audit log.
+func (c AccountController) EraseUserData(userId int) (ErasedUser, error) {
+	erased := ErasedUser{Rows: map[string]int64{}}
+
+	err := c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		rows, err := tx.Query(c.Context,
+			`SELECT blob_key FROM attachment WHERE user_id = $1
+			 UNION ALL
+			 SELECT blob_key FROM document_version WHERE document_id IN (SELECT id FROM document WHERE user_id = $1)
+			 UNION ALL
+			 SELECT blob_key FROM job_posting_snapshot WHERE user_id = $1`, userId)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				rows.Close()
+				return err
+			}
+
+			erased.BlobKeys = append(erased.BlobKeys, key)
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for i := len(userTables) - 1; i >= 0; i-- {
+			result, err := tx.Exec(c.Context, "DELETE FROM "+userTables[i].name+" WHERE "+userTables[i].where, userId)
+			if err != nil {
+				return err
+			}
+
+			erased.Rows[userTables[i].name] = result.RowsAffected()
+		}
+
+		_, err = tx.Exec(c.Context, "INSERT INTO audit_log (user_id, action, details) VALUES ($1, $2, $3)",
+			userId, AuditUserErased, map[string]interface{}{"rows": erased.Rows})
+		return err
+	})
+
+	return erased, err
+}
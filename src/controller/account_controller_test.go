@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportAndEraseUserData(t *testing.T) {
+	controller.CreateScheme()
+	accounts := AccountController{Database: controller.Database, Context: controller.Context}
+	attachments := AttachmentController{Database: controller.Database, Context: controller.Context}
+	documents := DocumentController{Database: controller.Database, Context: controller.Context}
+	calendars := CalendarController{Database: controller.Database, Context: controller.Context}
+	webhooks := WebhookController{Database: controller.Database, Context: controller.Context}
+	postings := PostingController{Database: controller.Database, Context: controller.Context}
+
+	for _, userId := range []int{1, 2} {
+		documentId, err := documents.InsertDocument(Document{UserId: userId, Name: "CV", Kind: DocumentCV}, DocumentVersion{FileName: "cv.pdf", BlobKey: fmt.Sprintf("document-%d", userId)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		versions, _ := documents.GetDocumentVersions(documentId)
+		versionId := versions[0].Id
+
+		applicationId, err := controller.InsertApplication(Application{UserId: userId, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer", DocumentVersionId: &versionId})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attachments.InsertAttachment(Attachment{ApplicationId: applicationId, UserId: userId, FileName: "letter.pdf", BlobKey: fmt.Sprintf("attachment-%d", userId)})
+		calendars.SaveCalendarToken(userId, fmt.Sprintf("token-%d", userId))
+		webhooks.InsertWebhook(Webhook{UserId: userId, Url: "https://example.com/hook", Secret: "secret", Events: []string{EventApplicationCreated}, Active: true})
+		postings.InsertPostingSnapshot(PostingSnapshot{UserId: userId, Url: "https://example.com/jobs/1", ContentType: "text/html", BlobKey: fmt.Sprintf("posting-%d", userId)})
+	}
+
+	webhooks.DispatchEvents(time.Now(), 10)
+
+	tables, err := accounts.ExportUserData(1)
+	assert.Nil(t, err)
+	assert.Equal(t, len(exportedTables), len(tables))
+
+	rows := map[string][]json.RawMessage{}
+	for _, table := range tables {
+		rows[table.Name] = table.Rows
+	}
+
+	assert.Equal(t, 1, len(rows["application"]))
+	assert.NotContains(t, string(rows["application"][0]), "search_vector")
+	assert.Equal(t, 1, len(rows["application_status_history"]))
+	assert.Equal(t, 1, len(rows["document_version"]))
+	assert.Equal(t, 1, len(rows["outbox_event"]))
+	assert.Equal(t, 1, len(rows["webhook_delivery"]))
+	assert.Equal(t, 1, len(rows["calendar_token"]))
+	assert.Equal(t, 1, len(rows["job_posting_snapshot"]))
+	assert.Empty(t, rows["audit_log"])
+
+	erased, err := accounts.EraseUserData(1)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"attachment-1", "document-1", "posting-1"}, erased.BlobKeys)
+	assert.Equal(t, int64(1), erased.Rows["application"])
+	assert.Equal(t, int64(1), erased.Rows["webhook_delivery"])
+
+	tables, _ = accounts.ExportUserData(1)
+	for _, table := range tables {
+		if table.Name == "audit_log" {
+			assert.Equal(t, 1, len(table.Rows))
+			assert.Contains(t, string(table.Rows[0]), AuditUserErased)
+		} else {
+			assert.Empty(t, table.Rows, table.Name)
+		}
+	}
+
+	applications, _ := controller.GetApplications(2)
+	assert.Equal(t, 1, len(applications))
+}
@@ -2,8 +2,12 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -19,6 +23,38 @@ type Application struct {
 	AcceptedSalary float32   `db:"accepted_salary"`
 	StartDate      time.Time `db:"start_date"`
 	Commentary     string    `db:"commentary"`
+	CompanyId      *int      `db:"company_id" json:"companyId"`
+	JobPostingUrl  string    `db:"job_posting_url" json:"jobPostingUrl"`
+
+	// DocumentVersionId references the version of a document, which was sent
+	// with the application. The version itself is only filled in by the
+	// service.
+	DocumentVersionId *int             `db:"document_version_id" json:"documentVersionId"`
+	DocumentVersion   *DocumentVersion `db:"-" json:"documentVersion,omitempty"`
+
+	// CustomFields holds the values of the custom fields of the user by
+	// their keys. The values are stored along with the application, if the
+	// map is not nil, and filled in by the service.
+	CustomFields map[string]interface{} `db:"-" json:"customFields"`
+
+	// StatusChangedAt is the time, since which the application is in its
+	// current status. Stale is set by MarkStaleApplications. Both are
+	// maintained by the repository, DaysInStatus is filled in by the
+	// service.
+	StatusChangedAt time.Time `db:"status_changed_at" json:"statusChangedAt"`
+	Stale           bool      `db:"stale" json:"stale"`
+	DaysInStatus    int       `db:"-" json:"daysInStatus"`
+}
+
+// StatusChange records a transition of an application from one status to
+// another. The first change of every application has no old status.
+type StatusChange struct {
+	Id            int       `db:"id" json:"id"`
+	ApplicationId int       `db:"application_id" json:"applicationId"`
+	OldStatusId   *int      `db:"old_status_id" json:"oldStatusId"`
+	NewStatusId   int       `db:"new_status_id" json:"newStatusId"`
+	ChangedAt     time.Time `db:"changed_at" json:"changedAt"`
+	ChangedBy     int       `db:"changed_by" json:"changedBy"`
 }
 
 type ApplicationController struct {
@@ -38,59 +74,125 @@ func NewApplicationController(dbConfig DbConfig) (ApplicationController, error)
 	}, nil
 }
 
-func (c ApplicationController) CreateScheme() {
-	queries := []string{
-		`DROP TABLE IF EXISTS work_type CASCADE`,
-		`CREATE TABLE work_type (
-				id SERIAL PRIMARY KEY NOT NULL,
-				name VARCHAR(255) NOT NULL)`,
-		`DROP TABLE IF EXISTS application_status CASCADE`,
-		`CREATE TABLE application_status (
-				id SERIAL PRIMARY KEY NOT NULL,
-				name VARCHAR(255) NOT NULL)`,
-		`DROP TABLE IF EXISTS application CASCADE`,
-		`CREATE TABLE application (
-				id SERIAL PRIMARY KEY NOT NULL,
-				user_id INTEGER NOT NULL,
-				job_title VARCHAR(255) NOT NULL,
-				work_type_id INTEGER NOT NULL,
-				company_name VARCHAR(255) NOT NULL,
-				submission_date DATE,
-				status_id INTEGER NOT NULL,
-				wanted_salary REAL,
-				accepted_salary REAL,
-				start_date DATE,
-				commentary VARCHAR(500),
-
-				FOREIGN KEY (work_type_id) REFERENCES work_type (id)
-					ON DELETE SET DEFAULT,
-				FOREIGN KEY (status_id) REFERENCES application_status (id)
-					ON DELETE SET DEFAULT)`,
-		`INSERT INTO work_type (name) VALUES ('Remote')`,
-		`INSERT INTO work_type (name) VALUES ('OnSite')`,
-		`INSERT INTO work_type (name) VALUES ('Hybrid')`,
-		`INSERT INTO application_status (name) VALUES ('Accepted')`,
-		`INSERT INTO application_status (name) VALUES ('Pending')`,
-		`INSERT INTO application_status (name) VALUES ('Declined')`,
-	}
-
-	for _, query := range queries {
-		c.Database.Exec(c.Context, query)
+// CreateScheme resets the database to a clean schema by rolling back and
+// re-applying all migrations. All data is lost, so it is only meant for
+// tests. Production databases are migrated with migrations.Migrator.Up.
+func (c ApplicationController) CreateScheme() error {
+	return resetScheme(c.Database)
+}
+
+// initialStatusChange returns the time, at which a new application entered
+// its first status. This is its submission, unless it is unknown or lies in
+// the future.
+func initialStatusChange(application Application, now time.Time) time.Time {
+	if application.SubmissionDate.IsZero() || application.SubmissionDate.After(now) {
+		return now
 	}
+
+	return application.SubmissionDate
 }
 
-func (c ApplicationController) InsertApplication(application Application) (int, error) {
-	row := c.Database.QueryRow(c.Context,
-		"INSERT INTO application (user_id, job_title, work_type_id, company_name, submission_date, status_id, wanted_salary, accepted_salary, start_date, commentary) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id",
-		application.UserId, application.JobTitle, application.WorkTypeId, application.CompanyName, application.SubmissionDate, application.StatusId, application.WantedSalary, application.AcceptedSalary, application.StartDate, application.Commentary)
+// insertApplication inserts the application with its custom field values,
+// starts its status history and writes the created event to the outbox.
+// Applications without a company are assigned to the company matching their
+// company name, which is created if necessary.
+func insertApplication(ctx context.Context, tx pgx.Tx, application Application) (int, error) {
+	if err := assignCompany(ctx, tx, &application); err != nil {
+		return -1, err
+	}
+
+	row := tx.QueryRow(ctx,
+		"INSERT INTO application (user_id, job_title, work_type_id, company_name, submission_date, status_id, wanted_salary, accepted_salary, start_date, commentary, company_id, document_version_id, status_changed_at, job_posting_url) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id",
+		application.UserId, application.JobTitle, application.WorkTypeId, application.CompanyName, application.SubmissionDate, application.StatusId, application.WantedSalary, application.AcceptedSalary, application.StartDate, application.Commentary, application.CompanyId, application.DocumentVersionId, initialStatusChange(application, time.Now()), application.JobPostingUrl)
+
+	id := -1
+	if err := row.Scan(&id); err != nil {
+		return -1, err
+	}
+
+	_, err := tx.Exec(ctx,
+		"INSERT INTO application_status_history (application_id, old_status_id, new_status_id, changed_by) VALUES ($1, NULL, $2, $3)",
+		id, application.StatusId, application.UserId)
+	if err != nil {
+		return -1, err
+	}
+
+	application.Id = id
+	if err := storeCustomFields(ctx, tx, application); err != nil {
+		return -1, err
+	}
+
+	if err := enqueueApplicationEvent(ctx, tx, EventApplicationCreated, id, nil); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
 
+func (c ApplicationController) InsertApplication(application Application) (int, error) {
 	id := -1
-	err := row.Scan(&id)
-	return id, err
+	err := c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		var err error
+		id, err = insertApplication(c.Context, tx, application)
+		return err
+	})
+
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// InsertApplications inserts the applications in a single transaction like
+// InsertApplication does, so either all or none of them are inserted. The
+// ids are returned in the order of the applications.
+func (c ApplicationController) InsertApplications(applications []Application) ([]int, error) {
+	ids := make([]int, 0, len(applications))
+	err := c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		for _, application := range applications {
+			id, err := insertApplication(c.Context, tx, application)
+			if err != nil {
+				return err
+			}
+
+			ids = append(ids, id)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// applicationColumns lists the columns in the order expected by
+// scanApplication.
+const applicationColumns = `id, user_id, job_title, work_type_id, company_name, submission_date,
+	status_id, wanted_salary, accepted_salary, start_date, commentary, company_id, document_version_id,
+	status_changed_at, stale, job_posting_url`
+
+// applicationTargets returns the scan targets of the applicationColumns.
+func applicationTargets(a *Application) []interface{} {
+	return []interface{}{
+		&a.Id, &a.UserId, &a.JobTitle, &a.WorkTypeId, &a.CompanyName, &a.SubmissionDate, &a.StatusId,
+		&a.WantedSalary, &a.AcceptedSalary, &a.StartDate, &a.Commentary, &a.CompanyId, &a.DocumentVersionId,
+		&a.StatusChangedAt, &a.Stale, &a.JobPostingUrl,
+	}
+}
+
+func scanApplication(row pgx.Row) (Application, error) {
+	var application Application
+	err := row.Scan(applicationTargets(&application)...)
+
+	return application, err
 }
 
 func (c ApplicationController) GetApplications(userId int) ([]Application, error) {
-	rows, err := c.Database.Query(c.Context, "SELECT * FROM application WHERE user_id = $1", userId)
+	rows, err := c.Database.Query(c.Context, "SELECT "+applicationColumns+" FROM application WHERE user_id = $1", userId)
 	if err != nil {
 		return nil, err
 	}
@@ -99,40 +201,278 @@ func (c ApplicationController) GetApplications(userId int) ([]Application, error
 	var applications []Application
 
 	for rows.Next() {
-		var application Application
-		rows.Scan(
-			&application.Id, &application.UserId, &application.JobTitle, &application.WorkTypeId, &application.CompanyName,
-			&application.SubmissionDate, &application.StatusId, &application.WantedSalary, &application.AcceptedSalary,
-			&application.StartDate, &application.Commentary)
+		application, err := scanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
 
 		applications = append(applications, application)
 	}
 
-	return applications, nil
+	return applications, rows.Err()
+}
+
+// StreamApplications calls each for every application of the user ordered by
+// id, while the rows are read from the cursor, so the applications are not
+// held in memory. It stops at the first error returned by each.
+func (c ApplicationController) StreamApplications(userId int, each func(Application) error) error {
+	rows, err := c.Database.Query(c.Context, "SELECT "+applicationColumns+" FROM application WHERE user_id = $1 ORDER BY id", userId)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		application, err := scanApplication(rows)
+		if err != nil {
+			return err
+		}
+
+		if err := each(application); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// queryArgs collects the arguments of a dynamically built query.
+type queryArgs []interface{}
+
+// add appends an argument and returns its placeholder.
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func applicationFilterConditions(userId int, query ApplicationQuery, args *queryArgs) []string {
+	conditions := []string{"user_id = " + args.add(userId)}
+
+	if len(query.StatusIds) > 0 {
+		conditions = append(conditions, "status_id = ANY("+args.add(query.StatusIds)+")")
+	}
+
+	if len(query.WorkTypeIds) > 0 {
+		conditions = append(conditions, "work_type_id = ANY("+args.add(query.WorkTypeIds)+")")
+	}
+
+	if query.Company != "" {
+		conditions = append(conditions, "company_name ILIKE '%' || "+args.add(likeEscaper.Replace(query.Company))+" || '%'")
+	}
+
+	if !query.SubmissionDateFrom.IsZero() {
+		conditions = append(conditions, "submission_date >= "+args.add(truncateDate(query.SubmissionDateFrom)))
+	}
+
+	if !query.SubmissionDateTo.IsZero() {
+		conditions = append(conditions, "submission_date <= "+args.add(truncateDate(query.SubmissionDateTo)))
+	}
+
+	if query.Stale != nil {
+		conditions = append(conditions, "stale = "+args.add(*query.Stale))
+	}
+
+	for _, filter := range query.CustomFields {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM application_custom_field v JOIN custom_field f ON f.id = v.field_id"+
+			" WHERE v.application_id = application.id AND f.key = "+args.add(filter.Key)+" AND v.value = "+args.add(jsonValue{filter.Value})+"::jsonb)")
+	}
+
+	if len(query.Tags) > 0 {
+		tags := ParseTags(strings.Join(query.Tags, ","))
+		taggedWith := "SELECT count(DISTINCT lower(tag.name)) FROM application_tag JOIN tag ON tag.id = application_tag.tag_id" +
+			" WHERE application_tag.application_id = application.id AND lower(tag.name) = ANY(" + args.add(tags) + ")"
+
+		if query.MatchAllTags {
+			conditions = append(conditions, "("+taggedWith+") = "+args.add(len(tags)))
+		} else {
+			conditions = append(conditions, "("+taggedWith+") > 0")
+		}
+	}
+
+	return conditions
+}
+
+// keysetCondition selects the rows, which come after the given sort values.
+// For the fields a, b and c this is (a > x) OR (a = x AND b > y) OR
+// (a = x AND b = y AND c > z), with < for descending fields.
+func keysetCondition(fields []SortField, values []interface{}, args *queryArgs) string {
+	var alternatives []string
+
+	for i, field := range fields {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, lookupSortColumn(fields[j].Field).column+" = "+args.add(values[j]))
+		}
+
+		operator := " > "
+		if field.Descending {
+			operator = " < "
+		}
+
+		parts = append(parts, lookupSortColumn(field.Field).column+operator+args.add(values[i]))
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+func (c ApplicationController) QueryApplications(userId int, query ApplicationQuery) (ApplicationPage, error) {
+	var page ApplicationPage
+	var args queryArgs
+
+	conditions := applicationFilterConditions(userId, query, &args)
+	if err := c.Database.QueryRow(c.Context,
+		"SELECT count(*) FROM application WHERE "+strings.Join(conditions, " AND "), args...).Scan(&page.Total); err != nil {
+		return ApplicationPage{}, err
+	}
+
+	fields := query.sortFields()
+	if query.Cursor != "" {
+		values, err := decodeCursor(fields, query.Cursor)
+		if err != nil {
+			return ApplicationPage{}, err
+		}
+
+		conditions = append(conditions, keysetCondition(fields, values, &args))
+	}
+
+	// The sort values are selected along with the applications, because the
+	// values of custom fields are not part of the scanned applications.
+	var columns, order []string
+	for _, field := range fields {
+		direction := "ASC"
+		if field.Descending {
+			direction = "DESC"
+		}
+
+		column := lookupSortColumn(field.Field).column
+		columns = append(columns, column)
+		order = append(order, column+" "+direction)
+	}
+
+	sql := "SELECT " + applicationColumns + ", " + strings.Join(columns, ", ") + " FROM application WHERE " +
+		strings.Join(conditions, " AND ") + " ORDER BY " + strings.Join(order, ", ")
+
+	if query.Limit > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", query.Limit+1)
+	}
+
+	rows, err := c.Database.Query(c.Context, sql, args...)
+	if err != nil {
+		return ApplicationPage{}, err
+	}
+	defer rows.Close()
+
+	var keys [][]interface{}
+	for rows.Next() {
+		var application Application
+		key := make([]interface{}, len(fields))
+
+		targets := applicationTargets(&application)
+		for i := range key {
+			targets = append(targets, &key[i])
+		}
+
+		if err := rows.Scan(targets...); err != nil {
+			return ApplicationPage{}, err
+		}
+
+		page.Applications = append(page.Applications, application)
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return ApplicationPage{}, err
+	}
+
+	// One more row than requested was fetched to find out, if there is a
+	// next page.
+	if query.Limit > 0 && len(page.Applications) > query.Limit {
+		page.Applications = page.Applications[:query.Limit]
+		page.NextCursor = encodeCursor(fields, keys[query.Limit-1])
+	}
+
+	return page, nil
 }
 
 func (c ApplicationController) GetApplication(id int) (Application, error) {
-	row := c.Database.QueryRow(c.Context, "SELECT * FROM application WHERE id = $1", id)
+	row := c.Database.QueryRow(c.Context, "SELECT "+applicationColumns+" FROM application WHERE id = $1", id)
+
+	application, err := scanApplication(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Application{}, ErrNotFound
+		}
 
-	var application Application
-	if err := row.Scan(
-		&application.Id, &application.UserId, &application.JobTitle, &application.WorkTypeId, &application.CompanyName,
-		&application.SubmissionDate, &application.StatusId, &application.WantedSalary, &application.AcceptedSalary,
-		&application.StartDate, &application.Commentary); err != nil {
 		return Application{}, err
 	}
 
 	return application, nil
 }
 
-func (c ApplicationController) DeleteApplication(id int) {
-	c.Database.QueryRow(c.Context, "DELETE FROM application WHERE id = $1", id)
+// DeleteApplication deletes the application and writes the deleted event,
+// which contains the application, to the outbox.
+func (c ApplicationController) DeleteApplication(id int) error {
+	return c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		// The application is locked, so a concurrent deletion waits and
+		// reports ErrNotFound instead of writing a second deleted event.
+		var lockedId int
+		err := tx.QueryRow(c.Context, "SELECT id FROM application WHERE id = $1 FOR UPDATE", id).Scan(&lockedId)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := enqueueApplicationEvent(c.Context, tx, EventApplicationDeleted, id, nil); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(c.Context, "DELETE FROM application WHERE id = $1", id)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
 }
 
-func (c ApplicationController) UpdateApplication(application Application) {
-	c.Database.QueryRow(c.Context,
+// updateApplication overwrites the application and records a status change
+// in the history. Only the owner may update an application, so the owner is
+// recorded as the acting user. The updated event and, if the status changed,
+// the status changed event are written to the outbox.
+func updateApplication(ctx context.Context, tx pgx.Tx, application Application, fromStatusId *int) error {
+	var oldStatusId int
+	if err := tx.QueryRow(ctx, "SELECT status_id FROM application WHERE id = $1 FOR UPDATE", application.Id).Scan(&oldStatusId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		return err
+	}
+
+	if fromStatusId != nil && *fromStatusId != oldStatusId {
+		return ErrStatusChanged
+	}
+
+	if err := assignCompany(ctx, tx, &application); err != nil {
+		return err
+	}
+
+	// Entering another status resets the stale detection.
+	_, err := tx.Exec(ctx,
 		`UPDATE application
-		 SET user_id = $2,
+		 SET status_changed_at = CASE WHEN status_id = $7 THEN status_changed_at ELSE now() END,
+		     stale = stale AND status_id = $7,
+		     user_id = $2,
 		     job_title = $3,
 			 work_type_id = $4,
 			 company_name = $5,
@@ -141,9 +481,124 @@ func (c ApplicationController) UpdateApplication(application Application) {
 			 wanted_salary = $8,
 			 accepted_salary = $9,
 			 start_date = $10,
-			 commentary = $11
+			 commentary = $11,
+			 company_id = $12,
+			 document_version_id = $13,
+			 job_posting_url = $14
 		WHERE id = $1`, application.Id, application.UserId, application.JobTitle, application.WorkTypeId,
 		application.CompanyName, application.SubmissionDate, application.StatusId,
 		application.WantedSalary, application.AcceptedSalary, application.StartDate,
-		application.Commentary)
+		application.Commentary, application.CompanyId, application.DocumentVersionId, application.JobPostingUrl)
+	if err != nil {
+		return err
+	}
+
+	if err := storeCustomFields(ctx, tx, application); err != nil {
+		return err
+	}
+
+	if err := enqueueApplicationEvent(ctx, tx, EventApplicationUpdated, application.Id, nil); err != nil {
+		return err
+	}
+
+	if oldStatusId == application.StatusId {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO application_status_history (application_id, old_status_id, new_status_id, changed_by) VALUES ($1, $2, $3, $4)",
+		application.Id, oldStatusId, application.StatusId, application.UserId)
+	if err != nil {
+		return err
+	}
+
+	return enqueueApplicationEvent(ctx, tx, EventApplicationStatusChanged, application.Id, &oldStatusId)
+}
+
+func (c ApplicationController) UpdateApplication(application Application) error {
+	return c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		return updateApplication(c.Context, tx, application, nil)
+	})
+}
+
+// UpdateApplicationFromStatus updates the application like
+// UpdateApplication, if it is still in the status, from which the transition
+// to its new status was checked. The status is compared, while the
+// application is locked, so concurrent transitions cannot bypass the
+// workflow.
+func (c ApplicationController) UpdateApplicationFromStatus(application Application, fromStatusId int) error {
+	return c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		return updateApplication(c.Context, tx, application, &fromStatusId)
+	})
+}
+
+func (c ApplicationController) GetStatusHistory(applicationId int) ([]StatusChange, error) {
+	rows, err := c.Database.Query(c.Context,
+		`SELECT id, application_id, old_status_id, new_status_id, changed_at, changed_by
+		 FROM application_status_history
+		 WHERE application_id = $1
+		 ORDER BY changed_at, id`, applicationId)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []StatusChange
+	for rows.Next() {
+		var change StatusChange
+		if err := rows.Scan(&change.Id, &change.ApplicationId, &change.OldStatusId, &change.NewStatusId, &change.ChangedAt, &change.ChangedBy); err != nil {
+			return nil, err
+		}
+
+		history = append(history, change)
+	}
+
+	return history, rows.Err()
+}
+
+// SearchApplications searches the job title, company name and commentary of
+// the applications of a user. The query supports the syntax of
+// websearch_to_tsquery, e.g. quoted phrases and -excluded words.
+func (c ApplicationController) SearchApplications(userId int, query string, limit int) ([]SearchResult, error) {
+	headlineOptions := "StartSel=" + highlightStart + ", StopSel=" + highlightStop
+	rows, err := c.Database.Query(c.Context,
+		`SELECT `+applicationColumns+`,
+			ts_rank(search_vector, query) AS rank,
+			ts_headline('simple', job_title, query, $3),
+			ts_headline('simple', company_name, query, $3),
+			ts_headline('simple', coalesce(commentary, ''), query, $3 || ', MaxWords=20, MinWords=5')
+		FROM application, websearch_to_tsquery('simple', $2) query
+		WHERE user_id = $1 AND search_vector @@ query
+		ORDER BY rank DESC, id
+		LIMIT $4`, userId, query, headlineOptions, limit)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		var snippets [3]string
+
+		targets := append(applicationTargets(&result.Application), &result.Rank, &snippets[0], &snippets[1], &snippets[2])
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+
+		// ts_headline returns the beginning of the text, even if nothing
+		// matched. Only snippets with highlighted words are relevant.
+		result.Highlights = map[string]string{}
+		for i, field := range searchFields {
+			if strings.Contains(snippets[i], highlightStart) {
+				result.Highlights[field.name] = markHighlights(snippets[i])
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
 }
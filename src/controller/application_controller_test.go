@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -87,6 +88,25 @@ func TestInsertApplication(t *testing.T) {
 	}
 }
 
+func TestApplicationJobPostingUrl(t *testing.T) {
+	controller.CreateScheme()
+
+	id, err := controller.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, JobPostingUrl: "https://jobs.example.com/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application, err := controller.GetApplication(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://jobs.example.com/1", application.JobPostingUrl)
+
+	application.JobPostingUrl = ""
+	assert.Nil(t, controller.UpdateApplication(application))
+
+	application, _ = controller.GetApplication(id)
+	assert.Equal(t, "", application.JobPostingUrl)
+}
+
 func TestGetApplications(t *testing.T) {
 	controller.CreateScheme()
 	userId := 1
@@ -110,6 +130,56 @@ func TestGetApplications(t *testing.T) {
 	assert.Equal(t, numberApplications, len(applications))
 }
 
+func TestStreamApplications(t *testing.T) {
+	controller.CreateScheme()
+
+	first, _ := controller.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	second, _ := controller.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	controller.InsertApplication(Application{UserId: 2, WorkTypeId: 1, StatusId: 1})
+
+	var ids []int
+	err := controller.StreamApplications(1, func(application Application) error {
+		ids = append(ids, application.Id)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{first, second}, ids)
+
+	stop := errors.New("stop")
+	err = controller.StreamApplications(1, func(application Application) error {
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestInsertApplications(t *testing.T) {
+	controller.CreateScheme()
+
+	_, err := controller.InsertApplications([]Application{
+		{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer", CompanyName: "ACME"},
+		{UserId: 1, WorkTypeId: 42, StatusId: 1, JobTitle: "Rust Developer", CompanyName: "ACME"},
+	})
+	assert.NotNil(t, err)
+
+	applications, _ := controller.GetApplications(1)
+	assert.Empty(t, applications)
+
+	ids, err := controller.InsertApplications([]Application{
+		{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer", CompanyName: "ACME"},
+		{UserId: 1, WorkTypeId: 2, StatusId: 2, JobTitle: "Rust Developer", CompanyName: "ACME"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(ids))
+
+	// Both applications are assigned to the same company.
+	first, _ := controller.GetApplication(ids[0])
+	second, _ := controller.GetApplication(ids[1])
+	assert.NotNil(t, first.CompanyId)
+	assert.Equal(t, first.CompanyId, second.CompanyId)
+}
+
 func TestGetApplicationsError(t *testing.T) {
 	controller.CreateScheme()
 
@@ -156,10 +226,17 @@ func TestDeleteApplication(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	controller.DeleteApplication(id)
+	assert.Nil(t, controller.DeleteApplication(id))
 	_, err = controller.GetApplication(id)
 
 	assert.NotNil(t, err)
+	assert.ErrorIs(t, controller.DeleteApplication(id), ErrNotFound)
+
+	var events int
+	err = controller.Database.QueryRow(controller.Context,
+		"SELECT count(*) FROM outbox_event WHERE event = $1", EventApplicationDeleted).Scan(&events)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, events)
 }
 
 func TestUpdateApplication(t *testing.T) {
@@ -180,3 +257,97 @@ func TestUpdateApplication(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "another job title", application.JobTitle)
 }
+
+func TestUpdateApplicationFromStatus(t *testing.T) {
+	controller.CreateScheme()
+
+	application := testApplication
+	application.StatusId = 2
+	id, err := controller.InsertApplication(application)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application.Id = id
+	application.StatusId = 3
+	assert.Nil(t, controller.UpdateApplicationFromStatus(application, 2))
+
+	// A second transition checked against the previous status is rejected.
+	application.StatusId = 1
+	assert.ErrorIs(t, controller.UpdateApplicationFromStatus(application, 2), ErrStatusChanged)
+
+	stored, _ := controller.GetApplication(id)
+	assert.Equal(t, 3, stored.StatusId)
+}
+
+func TestQueryApplications(t *testing.T) {
+	controller.CreateScheme()
+
+	for _, salary := range []float32{3000, 5000, 4000} {
+		application := testApplication
+		application.WantedSalary = salary
+		if _, err := controller.InsertApplication(application); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := ApplicationQuery{Limit: 2, Sort: []SortField{{Field: "wantedSalary", Descending: true}}}
+	page, err := controller.QueryApplications(testApplication.UserId, query)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 2, len(page.Applications))
+	assert.Equal(t, float32(5000), page.Applications[0].WantedSalary)
+	assert.NotEqual(t, "", page.NextCursor)
+
+	query.Cursor = page.NextCursor
+	page, err = controller.QueryApplications(testApplication.UserId, query)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(page.Applications))
+	assert.Equal(t, float32(3000), page.Applications[0].WantedSalary)
+	assert.Equal(t, "", page.NextCursor)
+}
+
+func TestSearchApplications(t *testing.T) {
+	controller.CreateScheme()
+
+	for _, title := range []string{"Go Developer", "Accountant"} {
+		application := testApplication
+		application.JobTitle = title
+		if _, err := controller.InsertApplication(application); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := controller.SearchApplications(testApplication.UserId, "developer", 10)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "Go Developer", results[0].Application.JobTitle)
+	assert.Equal(t, "Go <mark>Developer</mark>", results[0].Highlights["jobTitle"])
+}
+
+func TestGetStatusHistory(t *testing.T) {
+	controller.CreateScheme()
+
+	id, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updatedApplication := testApplication
+	updatedApplication.Id = id
+	updatedApplication.StatusId = 3
+	if err := controller.UpdateApplication(updatedApplication); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := controller.GetStatusHistory(id)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(history))
+	assert.Nil(t, history[0].OldStatusId)
+	assert.Equal(t, testApplication.StatusId, *history[1].OldStatusId)
+	assert.Equal(t, 3, history[1].NewStatusId)
+}
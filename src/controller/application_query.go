@@ -0,0 +1,443 @@
+package controller
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidQuery is returned, if an application query contains an unknown
+// sort field or a cursor, which does not belong to the query.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// MaxPageSize limits the number of applications returned by a single query.
+const MaxPageSize = 200
+
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ApplicationQuery filters, sorts and pages the applications of a user. Zero
+// values disable the respective filter. A zero Limit returns all matching
+// applications at once.
+//
+// Tags filters by tag names, which are compared regardless of case. An
+// application matches, if it has any of the tags, or all of them, if
+// MatchAllTags is set.
+type ApplicationQuery struct {
+	Limit              int
+	Cursor             string
+	Sort               []SortField
+	StatusIds          []int
+	WorkTypeIds        []int
+	Company            string
+	SubmissionDateFrom time.Time
+	SubmissionDateTo   time.Time
+	Tags               []string
+	MatchAllTags       bool
+	CustomFields       []CustomFieldFilter
+	Stale              *bool
+}
+
+// CustomFieldFilter selects the applications, of which the custom field has
+// the given value. The value must be normalized by CustomField.ParseValue.
+type CustomFieldFilter struct {
+	Key   string
+	Value interface{}
+}
+
+type ApplicationPage struct {
+	Applications []Application
+	NextCursor   string
+	Total        int
+}
+
+type valueKind int
+
+const (
+	kindInt valueKind = iota
+	kindFloat
+	kindString
+	kindDate
+	kindJSON
+)
+
+type sortColumn struct {
+	column string
+	kind   valueKind
+	value  func(application Application) interface{}
+}
+
+var sortColumns = map[string]sortColumn{
+	"id":             {"id", kindInt, func(a Application) interface{} { return a.Id }},
+	"jobTitle":       {"job_title", kindString, func(a Application) interface{} { return a.JobTitle }},
+	"companyName":    {"company_name", kindString, func(a Application) interface{} { return a.CompanyName }},
+	"workTypeId":     {"work_type_id", kindInt, func(a Application) interface{} { return a.WorkTypeId }},
+	"statusId":       {"status_id", kindInt, func(a Application) interface{} { return a.StatusId }},
+	"submissionDate": {"submission_date", kindDate, func(a Application) interface{} { return a.SubmissionDate }},
+	"startDate":      {"start_date", kindDate, func(a Application) interface{} { return a.StartDate }},
+	"wantedSalary":   {"wanted_salary", kindFloat, func(a Application) interface{} { return a.WantedSalary }},
+	"acceptedSalary": {"accepted_salary", kindFloat, func(a Application) interface{} { return a.AcceptedSalary }},
+}
+
+// CustomFieldPrefix prefixes the sort fields, which refer to custom fields,
+// e.g. customFields.teamSize.
+const CustomFieldPrefix = "customFields."
+
+// findSortColumn returns the column of a sort field. Custom fields are sorted
+// by their JSON values, missing values come first.
+func findSortColumn(name string) (sortColumn, bool) {
+	if column, ok := sortColumns[name]; ok {
+		return column, true
+	}
+
+	key := strings.TrimPrefix(name, CustomFieldPrefix)
+	if key == name || !customFieldKeyPattern.MatchString(key) {
+		return sortColumn{}, false
+	}
+
+	// The key is embedded into the statement. This is safe, because it
+	// consists of letters, digits and underscores only.
+	column := "COALESCE((SELECT v.value FROM application_custom_field v JOIN custom_field f ON f.id = v.field_id" +
+		" WHERE v.application_id = application.id AND f.key = '" + key + "'), 'null'::jsonb)"
+
+	return sortColumn{column, kindJSON, func(a Application) interface{} { return jsonValue{a.CustomFields[key]} }}, true
+}
+
+// lookupSortColumn returns the column of a sort field, which has already been
+// validated by ParseSort.
+func lookupSortColumn(name string) sortColumn {
+	column, _ := findSortColumn(name)
+	return column
+}
+
+// ParseSort parses a sort specification like "submissionDate,-wantedSalary".
+// A leading minus sorts the field in descending order.
+func ParseSort(spec string) ([]SortField, error) {
+	var fields []SortField
+
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		field := SortField{Field: strings.TrimPrefix(name, "-"), Descending: strings.HasPrefix(name, "-")}
+		if _, ok := findSortColumn(field.Field); !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %s", ErrInvalidQuery, field.Field)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// sortFields returns the requested order with the id as final tie breaker,
+// so the order is total and can be used for keyset pagination.
+func (q ApplicationQuery) sortFields() []SortField {
+	fields := append([]SortField{}, q.Sort...)
+
+	for _, field := range fields {
+		if field.Field == "id" {
+			return fields
+		}
+	}
+
+	return append(fields, SortField{Field: "id"})
+}
+
+func sortSignature(fields []SortField) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Field
+		if field.Descending {
+			names[i] = "-" + field.Field
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+type cursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// encodeCursor creates an opaque cursor pointing behind the application with
+// the given sort key. The key holds the values of the sort fields, e.g. as
+// returned by sortKey.
+func encodeCursor(fields []SortField, key []interface{}) string {
+	c := cursor{Sort: sortSignature(fields)}
+
+	for _, value := range key {
+		if date, ok := value.(time.Time); ok {
+			value = date.Format(time.RFC3339Nano)
+		}
+
+		c.Values = append(c.Values, value)
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor restores the sort values stored in a cursor. They have the
+// same types as the values returned by sortColumn.value.
+func decodeCursor(fields []SortField, encoded string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sortSignature(fields) || len(c.Values) != len(fields) {
+		return nil, fmt.Errorf("%w: cursor does not match the query", ErrInvalidQuery)
+	}
+
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		var ok bool
+
+		switch lookupSortColumn(field.Field).kind {
+		case kindInt:
+			var number float64
+			number, ok = c.Values[i].(float64)
+			values[i] = int(number)
+		case kindFloat:
+			var number float64
+			number, ok = c.Values[i].(float64)
+			values[i] = float32(number)
+		case kindString:
+			values[i], ok = c.Values[i].(string)
+		case kindJSON:
+			values[i], ok = jsonValue{c.Values[i]}, true
+		case kindDate:
+			var text string
+			if text, ok = c.Values[i].(string); ok {
+				values[i], err = time.Parse(time.RFC3339Nano, text)
+				ok = err == nil
+			}
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+		}
+	}
+
+	return values, nil
+}
+
+// compareValues compares two sort values of the same kind.
+func compareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case int:
+		return compareOrdered(a, b.(int))
+	case float32:
+		return compareOrdered(a, b.(float32))
+	case string:
+		return strings.Compare(a, b.(string))
+	case time.Time:
+		return compareOrdered(a.UnixNano(), b.(time.Time).UnixNano())
+	case jsonValue:
+		return compareJSON(a.value, b.(jsonValue).value)
+	}
+
+	return 0
+}
+
+func compareOrdered[T int | int64 | float32 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// jsonValue wraps a value decoded from JSON, which is stored in a JSONB
+// column.
+type jsonValue struct {
+	value interface{}
+}
+
+func (v jsonValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.value)
+}
+
+// Value passes the value as JSON text to the database.
+func (v jsonValue) Value() (driver.Value, error) {
+	data, err := json.Marshal(v.value)
+	return string(data), err
+}
+
+// jsonRank orders the types of JSON values like PostgreSQL orders JSONB
+// values.
+func jsonRank(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case string:
+		return 1
+	case float64:
+		return 2
+	case bool:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// compareJSON compares two values decoded from JSON. Values of different
+// types are ordered by jsonRank.
+func compareJSON(a, b interface{}) int {
+	if result := compareOrdered(jsonRank(a), jsonRank(b)); result != 0 {
+		return result
+	}
+
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case float64:
+		return compareOrdered(a, b.(float64))
+	case bool:
+		return compareOrdered(boolRank(a), boolRank(b.(bool)))
+	}
+
+	return 0
+}
+
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+// compareKeys compares the sort values of two applications, taking the sort
+// direction of each field into account.
+func compareKeys(fields []SortField, a, b []interface{}) int {
+	for i, field := range fields {
+		result := compareValues(a[i], b[i])
+		if field.Descending {
+			result = -result
+		}
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+func sortKey(fields []SortField, application Application) []interface{} {
+	key := make([]interface{}, len(fields))
+	for i, field := range fields {
+		key[i] = lookupSortColumn(field.Field).value(application)
+	}
+
+	return key
+}
+
+// matches evaluates the filters of the query against an application.
+func (q ApplicationQuery) matches(application Application) bool {
+	if len(q.StatusIds) > 0 && !containsInt(q.StatusIds, application.StatusId) {
+		return false
+	}
+
+	if len(q.WorkTypeIds) > 0 && !containsInt(q.WorkTypeIds, application.WorkTypeId) {
+		return false
+	}
+
+	if q.Company != "" && !strings.Contains(strings.ToLower(application.CompanyName), strings.ToLower(q.Company)) {
+		return false
+	}
+
+	if !q.SubmissionDateFrom.IsZero() && application.SubmissionDate.Before(truncateDate(q.SubmissionDateFrom)) {
+		return false
+	}
+
+	if !q.SubmissionDateTo.IsZero() && application.SubmissionDate.After(truncateDate(q.SubmissionDateTo)) {
+		return false
+	}
+
+	if q.Stale != nil && application.Stale != *q.Stale {
+		return false
+	}
+
+	for _, filter := range q.CustomFields {
+		value, ok := application.CustomFields[filter.Key]
+		if !ok || compareJSON(value, filter.Value) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseTags parses a comma separated list of tag names. The names are
+// lowercased and duplicates are dropped.
+func ParseTags(spec string) []string {
+	var tags []string
+
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !containsString(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+
+	return tags
+}
+
+// matchesTags evaluates the tag filter of the query against the lowercased
+// tag names of an application.
+func (q ApplicationQuery) matchesTags(names []string) bool {
+	if len(q.Tags) == 0 {
+		return true
+	}
+
+	tags := ParseTags(strings.Join(q.Tags, ","))
+
+	found := 0
+	for _, tag := range tags {
+		if containsString(names, tag) {
+			found++
+		}
+	}
+
+	if q.MatchAllTags {
+		return found == len(tags)
+	}
+
+	return found > 0
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
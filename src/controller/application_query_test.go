@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	fields, err := ParseSort("submissionDate,-wantedSalary")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []SortField{
+		{Field: "submissionDate"},
+		{Field: "wantedSalary", Descending: true},
+	}, fields)
+}
+
+func TestParseSortUnknownField(t *testing.T) {
+	_, err := ParseSort("password")
+
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestSortFieldsAppendsId(t *testing.T) {
+	query := ApplicationQuery{Sort: []SortField{{Field: "jobTitle"}}}
+
+	assert.Equal(t, []SortField{{Field: "jobTitle"}, {Field: "id"}}, query.sortFields())
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	fields := []SortField{{Field: "submissionDate"}, {Field: "wantedSalary", Descending: true}, {Field: "id"}}
+	application := Application{Id: 7, SubmissionDate: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), WantedSalary: 4200.5}
+
+	values, err := decodeCursor(fields, encodeCursor(fields, sortKey(fields, application)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, compareKeys(fields, values, sortKey(fields, application)))
+}
+
+func TestDecodeCursorSortMismatch(t *testing.T) {
+	encoded := encodeCursor([]SortField{{Field: "id"}}, []interface{}{1})
+
+	_, err := decodeCursor([]SortField{{Field: "jobTitle"}, {Field: "id"}}, encoded)
+
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestDecodeCursorMalformed(t *testing.T) {
+	_, err := decodeCursor([]SortField{{Field: "id"}}, "not a cursor")
+
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestQueryMatches(t *testing.T) {
+	application := Application{
+		CompanyName:    "ACME Inc.",
+		StatusId:       2,
+		WorkTypeId:     1,
+		SubmissionDate: time.Date(2022, 6, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	assert.True(t, ApplicationQuery{Company: "acme"}.matches(application))
+	assert.False(t, ApplicationQuery{Company: "initech"}.matches(application))
+	assert.True(t, ApplicationQuery{StatusIds: []int{1, 2}}.matches(application))
+	assert.False(t, ApplicationQuery{WorkTypeIds: []int{2, 3}}.matches(application))
+	assert.True(t, ApplicationQuery{
+		SubmissionDateFrom: time.Date(2022, 6, 15, 0, 0, 0, 0, time.UTC),
+		SubmissionDateTo:   time.Date(2022, 6, 15, 0, 0, 0, 0, time.UTC),
+	}.matches(application))
+	assert.False(t, ApplicationQuery{SubmissionDateFrom: time.Date(2022, 6, 16, 0, 0, 0, 0, time.UTC)}.matches(application))
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Delimiters marking the highlighted words in the output of ts_headline.
+// They are replaced after the text was escaped, so the snippets are safe to
+// embed into HTML.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+type SearchResult struct {
+	Application Application       `json:"application"`
+	Rank        float32           `json:"rank"`
+	Highlights  map[string]string `json:"highlights"`
+}
+
+// markHighlights escapes a snippet and turns the highlight delimiters into
+// <mark> elements.
+func markHighlights(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, highlightStart, "<mark>")
+	return strings.ReplaceAll(snippet, highlightStop, "</mark>")
+}
+
+// tokenize splits a text into lower case words.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlightTokens wraps all words of the text, which are contained in the
+// given set, in highlight delimiters.
+func highlightTokens(text string, tokens map[string]bool) (string, bool) {
+	var builder strings.Builder
+	found := false
+	start := -1
+
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+
+		word := text[start:end]
+		if tokens[strings.ToLower(word)] {
+			builder.WriteString(highlightStart + word + highlightStop)
+			found = true
+		} else {
+			builder.WriteString(word)
+		}
+
+		start = -1
+	}
+
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+
+			continue
+		}
+
+		flush(i)
+		builder.WriteRune(r)
+	}
+
+	flush(len(text))
+	return builder.String(), found
+}
+
+// searchField is a searchable column together with the weight, which
+// ts_rank uses for it by default.
+type searchField struct {
+	name   string
+	weight float32
+	value  func(application Application) string
+}
+
+var searchFields = []searchField{
+	{"jobTitle", 1.0, func(a Application) string { return a.JobTitle }},
+	{"companyName", 0.4, func(a Application) string { return a.CompanyName }},
+	{"commentary", 0.2, func(a Application) string { return a.Commentary }},
+}
+
+// matchApplication is the fallback for PostgreSQL full-text search. All
+// words of the query have to occur in one of the searchable fields. The rank
+// sums up the weights of the fields for every matching word.
+func matchApplication(application Application, query []string) (SearchResult, bool) {
+	result := SearchResult{Application: application, Highlights: map[string]string{}}
+	queryTokens := map[string]bool{}
+	for _, token := range query {
+		queryTokens[token] = true
+	}
+
+	found := map[string]bool{}
+	for _, field := range searchFields {
+		for _, token := range tokenize(field.value(application)) {
+			if queryTokens[token] {
+				found[token] = true
+				result.Rank += field.weight
+			}
+		}
+
+		if snippet, ok := highlightTokens(field.value(application), queryTokens); ok {
+			result.Highlights[field.name] = markHighlights(snippet)
+		}
+	}
+
+	return result, len(query) > 0 && len(found) == len(queryTokens)
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"senior", "go", "developer", "m", "w", "d"}, tokenize("Senior Go-Developer (m/w/d)"))
+}
+
+func TestHighlightTokens(t *testing.T) {
+	snippet, found := highlightTokens("Senior Go-Developer", map[string]bool{"go": true})
+
+	assert.True(t, found)
+	assert.Equal(t, "Senior <mark>Go</mark>-Developer", markHighlights(snippet))
+}
+
+func TestMarkHighlightsEscapesHtml(t *testing.T) {
+	snippet := markHighlights("<script>" + highlightStart + "go" + highlightStop)
+
+	assert.Equal(t, "&lt;script&gt;<mark>go</mark>", snippet)
+}
+
+func TestMatchApplication(t *testing.T) {
+	application := Application{JobTitle: "Go Developer", CompanyName: "ACME", Commentary: "Uses Go and Postgres"}
+
+	result, ok := matchApplication(application, tokenize("go postgres"))
+
+	assert.True(t, ok)
+	assert.InDelta(t, 1.4, result.Rank, 0.001)
+	assert.Equal(t, "<mark>Go</mark> Developer", result.Highlights["jobTitle"])
+	assert.Equal(t, "Uses <mark>Go</mark> and <mark>Postgres</mark>", result.Highlights["commentary"])
+	assert.NotContains(t, result.Highlights, "companyName")
+
+	_, ok = matchApplication(application, tokenize("go rust"))
+	assert.False(t, ok)
+}
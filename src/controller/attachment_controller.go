@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Attachment is a file attached to an application, e.g. the CV sent with it.
+// The content is stored in a blob store under the blob key.
+type Attachment struct {
+	Id            int       `db:"id" json:"id"`
+	ApplicationId int       `db:"application_id" json:"applicationId"`
+	UserId        int       `db:"user_id" json:"userId"`
+	FileName      string    `db:"file_name" json:"fileName"`
+	ContentType   string    `db:"content_type" json:"contentType"`
+	Size          int64     `db:"size" json:"size"`
+	BlobKey       string    `db:"blob_key" json:"-"`
+	UploadedAt    time.Time `db:"uploaded_at" json:"uploadedAt"`
+}
+
+type AttachmentController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const attachmentColumns = "id, application_id, user_id, file_name, content_type, size, blob_key, uploaded_at"
+
+func scanAttachment(row pgx.Row) (Attachment, error) {
+	var attachment Attachment
+	err := row.Scan(&attachment.Id, &attachment.ApplicationId, &attachment.UserId, &attachment.FileName,
+		&attachment.ContentType, &attachment.Size, &attachment.BlobKey, &attachment.UploadedAt)
+
+	return attachment, err
+}
+
+func (c AttachmentController) InsertAttachment(attachment Attachment) (int, error) {
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		`INSERT INTO attachment (application_id, user_id, file_name, content_type, size, blob_key)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		attachment.ApplicationId, attachment.UserId, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.BlobKey).Scan(&id)
+
+	return id, err
+}
+
+func (c AttachmentController) GetAttachments(applicationId int) ([]Attachment, error) {
+	rows, err := c.Database.Query(c.Context,
+		"SELECT "+attachmentColumns+" FROM attachment WHERE application_id = $1 ORDER BY uploaded_at, id", applicationId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (c AttachmentController) GetAttachment(id int) (Attachment, error) {
+	attachment, err := scanAttachment(c.Database.QueryRow(c.Context, "SELECT "+attachmentColumns+" FROM attachment WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Attachment{}, ErrNotFound
+	}
+
+	return attachment, err
+}
+
+func (c AttachmentController) DeleteAttachment(id int) error {
+	tag, err := c.Database.Exec(c.Context, "DELETE FROM attachment WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetAttachmentUsage sums up the sizes of all attachments of the user.
+func (c AttachmentController) GetAttachmentUsage(userId int) (int64, error) {
+	var usage int64
+	err := c.Database.QueryRow(c.Context, "SELECT coalesce(sum(size), 0) FROM attachment WHERE user_id = $1", userId).Scan(&usage)
+
+	return usage, err
+}
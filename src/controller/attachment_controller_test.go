@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachments(t *testing.T) {
+	controller.CreateScheme()
+	attachments := AttachmentController{Database: controller.Database, Context: controller.Context}
+
+	applicationId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := attachments.InsertAttachment(Attachment{ApplicationId: applicationId, UserId: 1, FileName: "cv.pdf", ContentType: "application/pdf", Size: 100, BlobKey: "a"})
+	assert.Nil(t, err)
+
+	attachment, err := attachments.GetAttachment(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "cv.pdf", attachment.FileName)
+	assert.False(t, attachment.UploadedAt.IsZero())
+
+	usage, err := attachments.GetAttachmentUsage(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), usage)
+
+	assert.Nil(t, controller.DeleteApplication(applicationId))
+
+	_, err = attachments.GetAttachment(id)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type CalendarController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+// GetCalendarToken returns the token of the user or ErrNotFound, if the
+// user has none.
+func (c CalendarController) GetCalendarToken(userId int) (string, error) {
+	var token string
+	err := c.Database.QueryRow(c.Context, "SELECT token FROM calendar_token WHERE user_id = $1", userId).Scan(&token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotFound
+	}
+
+	return token, err
+}
+
+// GetCalendarTokenUser returns the id of the user, who owns the token, or
+// ErrNotFound, if the token does not exist.
+func (c CalendarController) GetCalendarTokenUser(token string) (int, error) {
+	var userId int
+	err := c.Database.QueryRow(c.Context, "SELECT user_id FROM calendar_token WHERE token = $1", token).Scan(&userId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return -1, ErrNotFound
+	}
+
+	return userId, err
+}
+
+// SaveCalendarToken creates or replaces the token of the user.
+func (c CalendarController) SaveCalendarToken(userId int, token string) error {
+	_, err := c.Database.Exec(c.Context,
+		`INSERT INTO calendar_token (user_id, token) VALUES ($1, $2)
+		 ON CONFLICT (user_id) DO UPDATE SET token = excluded.token, created_at = now()`,
+		userId, token)
+
+	return err
+}
+
+func (c CalendarController) DeleteCalendarToken(userId int) error {
+	result, err := c.Database.Exec(c.Context, "DELETE FROM calendar_token WHERE user_id = $1", userId)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
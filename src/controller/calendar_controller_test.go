@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarTokens(t *testing.T) {
+	controller.CreateScheme()
+	calendars := CalendarController{Database: controller.Database, Context: controller.Context}
+
+	_, err := calendars.GetCalendarToken(1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Nil(t, calendars.SaveCalendarToken(1, "first"))
+	assert.NotNil(t, calendars.SaveCalendarToken(2, "first"))
+
+	userId, err := calendars.GetCalendarTokenUser("first")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, userId)
+
+	assert.Nil(t, calendars.SaveCalendarToken(1, "second"))
+	token, _ := calendars.GetCalendarToken(1)
+	assert.Equal(t, "second", token)
+
+	_, err = calendars.GetCalendarTokenUser("first")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Nil(t, calendars.DeleteCalendarToken(1))
+	assert.ErrorIs(t, calendars.DeleteCalendarToken(1), ErrNotFound)
+}
+
+func TestUserCalendarEntries(t *testing.T) {
+	controller.CreateScheme()
+	interviews := InterviewController{Database: controller.Database, Context: controller.Context}
+	reminders := ReminderController{Database: controller.Database, Context: controller.Context}
+
+	applicationId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	interview := testInterview
+	interview.ApplicationId = applicationId
+	interviewId, err := interviews.InsertInterview(interview)
+	assert.Nil(t, err)
+
+	reminderId, err := reminders.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: time.Now(), Message: "Follow up"})
+	assert.Nil(t, err)
+
+	userInterviews, err := interviews.GetUserInterviews(testApplication.UserId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(userInterviews))
+	assert.Equal(t, interviewId, userInterviews[0].Id)
+
+	userReminders, err := reminders.GetUserReminders(testApplication.UserId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(userReminders))
+	assert.Equal(t, reminderId, userReminders[0].Id)
+
+	userInterviews, _ = interviews.GetUserInterviews(testApplication.UserId + 1)
+	assert.Empty(t, userInterviews)
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Company is referenced by the applications of a user. Its name is unique
+// per user after normalisation.
+type Company struct {
+	Id       int    `db:"id" json:"id"`
+	UserId   int    `db:"user_id" json:"userId"`
+	Name     string `db:"name" json:"name"`
+	Website  string `db:"website" json:"website"`
+	Industry string `db:"industry" json:"industry"`
+	Size     string `db:"size" json:"size"`
+	Location string `db:"location" json:"location"`
+	Notes    string `db:"notes" json:"notes"`
+}
+
+// legalForms are dropped from the end of company names, so "Acme Inc." and
+// "ACME" are the same company. The migration of the company table uses the
+// same list.
+var legalForms = map[string]bool{
+	"inc": true, "incorporated": true, "ltd": true, "limited": true, "llc": true, "gmbh": true,
+	"corp": true, "corporation": true, "co": true, "company": true, "ag": true, "plc": true,
+	"sa": true, "se": true, "bv": true,
+}
+
+// NormalizeCompanyName lower cases the name, replaces punctuation by single
+// spaces and drops trailing legal forms. The first word is always kept.
+func NormalizeCompanyName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for len(words) > 1 && legalForms[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// assignCompany assigns applications without a company to the company
+// matching their company name. Unknown companies are created.
+func assignCompany(ctx context.Context, tx pgx.Tx, application *Application) error {
+	normalizedName := NormalizeCompanyName(application.CompanyName)
+	if application.CompanyId != nil || normalizedName == "" {
+		return nil
+	}
+
+	// The no-op update makes the existing row available to RETURNING.
+	var companyId int
+	err := tx.QueryRow(ctx,
+		`INSERT INTO company (user_id, name, normalized_name) VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id, normalized_name) DO UPDATE SET name = company.name
+		 RETURNING id`,
+		application.UserId, application.CompanyName, normalizedName).Scan(&companyId)
+
+	if err != nil {
+		return err
+	}
+
+	application.CompanyId = &companyId
+	return nil
+}
+
+// mapUniqueViolation turns violations of unique constraints into
+// ErrAlreadyExists.
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ErrAlreadyExists
+	}
+
+	return err
+}
+
+type CompanyController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const companyColumns = "id, user_id, name, website, industry, size, location, notes"
+
+func scanCompany(row pgx.Row) (Company, error) {
+	var company Company
+	err := row.Scan(&company.Id, &company.UserId, &company.Name, &company.Website, &company.Industry,
+		&company.Size, &company.Location, &company.Notes)
+
+	return company, err
+}
+
+func (c CompanyController) InsertCompany(company Company) (int, error) {
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		`INSERT INTO company (user_id, name, normalized_name, website, industry, size, location, notes)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		company.UserId, company.Name, NormalizeCompanyName(company.Name), company.Website, company.Industry,
+		company.Size, company.Location, company.Notes).Scan(&id)
+
+	if err != nil {
+		return -1, mapUniqueViolation(err)
+	}
+
+	return id, nil
+}
+
+func (c CompanyController) GetCompanies(userId int) ([]Company, error) {
+	rows, err := c.Database.Query(c.Context, "SELECT "+companyColumns+" FROM company WHERE user_id = $1 ORDER BY normalized_name, id", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var companies []Company
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		companies = append(companies, company)
+	}
+
+	return companies, rows.Err()
+}
+
+func (c CompanyController) GetCompany(id int) (Company, error) {
+	company, err := scanCompany(c.Database.QueryRow(c.Context, "SELECT "+companyColumns+" FROM company WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Company{}, ErrNotFound
+	}
+
+	return company, err
+}
+
+// UpdateCompany overwrites the company. The company name of its applications
+// is renamed as well. The owner cannot be changed.
+func (c CompanyController) UpdateCompany(company Company) error {
+	err := c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(c.Context,
+			`UPDATE company
+			 SET name = $2,
+			     normalized_name = $3,
+			     website = $4,
+			     industry = $5,
+			     size = $6,
+			     location = $7,
+			     notes = $8
+			 WHERE id = $1`,
+			company.Id, company.Name, NormalizeCompanyName(company.Name), company.Website, company.Industry,
+			company.Size, company.Location, company.Notes)
+
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+
+		_, err = tx.Exec(c.Context, "UPDATE application SET company_name = $2 WHERE company_id = $1", company.Id, company.Name)
+		return err
+	})
+
+	return mapUniqueViolation(err)
+}
+
+// DeleteCompany deletes the company. Its applications keep their company
+// name.
+func (c CompanyController) DeleteCompany(id int) error {
+	tag, err := c.Database.Exec(c.Context, "DELETE FROM company WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCompanyName(t *testing.T) {
+	assert.Equal(t, "acme", NormalizeCompanyName("ACME"))
+	assert.Equal(t, "acme", NormalizeCompanyName("Acme Inc."))
+	assert.Equal(t, "acme", NormalizeCompanyName("  acme  "))
+	assert.Equal(t, "acme widgets", NormalizeCompanyName("Acme-Widgets Co. Ltd"))
+	assert.Equal(t, "müller", NormalizeCompanyName("Müller GmbH"))
+	assert.Equal(t, "co", NormalizeCompanyName("Co"))
+	assert.Equal(t, "", NormalizeCompanyName(" - "))
+}
+
+func TestInsertApplicationAssignsCompany(t *testing.T) {
+	controller.CreateScheme()
+	companies := CompanyController{Database: controller.Database, Context: controller.Context}
+
+	first := testApplication
+	first.CompanyName = "ACME"
+	firstId, err := controller.InsertApplication(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := testApplication
+	second.CompanyName = "Acme Inc."
+	secondId, err := controller.InsertApplication(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstApplication, _ := controller.GetApplication(firstId)
+	secondApplication, _ := controller.GetApplication(secondId)
+	assert.NotNil(t, firstApplication.CompanyId)
+	assert.Equal(t, firstApplication.CompanyId, secondApplication.CompanyId)
+
+	_, err = companies.InsertCompany(Company{UserId: testApplication.UserId, Name: "acme"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+
+	company, err := companies.GetCompany(*firstApplication.CompanyId)
+	assert.Nil(t, err)
+	company.Name = "ACME Corporation"
+	assert.Nil(t, companies.UpdateCompany(company))
+
+	secondApplication, _ = controller.GetApplication(secondId)
+	assert.Equal(t, "ACME Corporation", secondApplication.CompanyName)
+
+	assert.Nil(t, companies.DeleteCompany(company.Id))
+	secondApplication, _ = controller.GetApplication(secondId)
+	assert.Nil(t, secondApplication.CompanyId)
+}
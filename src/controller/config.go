@@ -1,7 +1,12 @@
 package controller
 
 import (
+	"errors"
+	"flhansen/application-manager/application-service/src/migrations"
 	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 type DbConfig struct {
@@ -15,3 +20,31 @@ type DbConfig struct {
 func (conf DbConfig) ConnectionString() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s", conf.Username, conf.Password, conf.Host, conf.Port, conf.Database)
 }
+
+// resetScheme drops and recreates every table created by the migrations.
+func resetScheme(db *pgxpool.Pool) error {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	return migrator.Reset()
+}
+
+// SQLSTATE codes of the constraint violations handled by the controllers.
+const (
+	notNullViolation    = "23502"
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// mapConstraintError turns violations of foreign key and not null constraints
+// into ErrInUse. They occur, if a referenced row is deleted.
+func mapConstraintError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == foreignKeyViolation || pgErr.Code == notNullViolation) {
+		return ErrInUse
+	}
+
+	return err
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Contact is a person, e.g. a recruiter, who can be involved in several
+// applications of the user.
+type Contact struct {
+	Id          int    `db:"id" json:"id"`
+	UserId      int    `db:"user_id" json:"userId"`
+	Name        string `db:"name" json:"name"`
+	Email       string `db:"email" json:"email"`
+	Phone       string `db:"phone" json:"phone"`
+	Role        string `db:"role" json:"role"`
+	LinkedInUrl string `db:"linkedin_url" json:"linkedInUrl"`
+	Notes       string `db:"notes" json:"notes"`
+}
+
+type ContactController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const contactColumns = "id, user_id, name, email, phone, role, linkedin_url, notes"
+
+func scanContact(row pgx.Row) (Contact, error) {
+	var contact Contact
+	err := row.Scan(&contact.Id, &contact.UserId, &contact.Name, &contact.Email, &contact.Phone,
+		&contact.Role, &contact.LinkedInUrl, &contact.Notes)
+
+	return contact, err
+}
+
+func (c ContactController) queryContacts(sql string, args ...interface{}) ([]Contact, error) {
+	rows, err := c.Database.Query(c.Context, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []Contact
+	for rows.Next() {
+		contact, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, rows.Err()
+}
+
+func (c ContactController) InsertContact(contact Contact) (int, error) {
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		`INSERT INTO contact (user_id, name, email, phone, role, linkedin_url, notes)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		contact.UserId, contact.Name, contact.Email, contact.Phone, contact.Role, contact.LinkedInUrl, contact.Notes).Scan(&id)
+
+	return id, err
+}
+
+func (c ContactController) GetContacts(userId int) ([]Contact, error) {
+	return c.queryContacts("SELECT "+contactColumns+" FROM contact WHERE user_id = $1 ORDER BY name, id", userId)
+}
+
+func (c ContactController) GetContact(id int) (Contact, error) {
+	contact, err := scanContact(c.Database.QueryRow(c.Context, "SELECT "+contactColumns+" FROM contact WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Contact{}, ErrNotFound
+	}
+
+	return contact, err
+}
+
+// UpdateContact overwrites the contact. The owner cannot be changed.
+func (c ContactController) UpdateContact(contact Contact) error {
+	tag, err := c.Database.Exec(c.Context,
+		`UPDATE contact
+		 SET name = $2,
+		     email = $3,
+		     phone = $4,
+		     role = $5,
+		     linkedin_url = $6,
+		     notes = $7
+		 WHERE id = $1`,
+		contact.Id, contact.Name, contact.Email, contact.Phone, contact.Role, contact.LinkedInUrl, contact.Notes)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteContact deletes the contact and unlinks it from all applications.
+func (c ContactController) DeleteContact(id int) error {
+	tag, err := c.Database.Exec(c.Context, "DELETE FROM contact WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// LinkContact involves the contact in the application. Linking a contact
+// twice has no effect.
+func (c ContactController) LinkContact(applicationId int, contactId int) error {
+	_, err := c.Database.Exec(c.Context,
+		`INSERT INTO application_contact (application_id, contact_id) VALUES ($1, $2)
+		 ON CONFLICT DO NOTHING`,
+		applicationId, contactId)
+
+	return err
+}
+
+func (c ContactController) UnlinkContact(applicationId int, contactId int) error {
+	tag, err := c.Database.Exec(c.Context,
+		"DELETE FROM application_contact WHERE application_id = $1 AND contact_id = $2",
+		applicationId, contactId)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (c ContactController) GetApplicationContacts(applicationId int) ([]Contact, error) {
+	return c.queryContacts(
+		`SELECT `+contactColumns+` FROM contact
+		 WHERE id IN (SELECT contact_id FROM application_contact WHERE application_id = $1)
+		 ORDER BY name, id`,
+		applicationId)
+}
+
+func (c ContactController) GetContactApplications(contactId int) ([]Application, error) {
+	rows, err := c.Database.Query(c.Context,
+		`SELECT `+applicationColumns+` FROM application
+		 WHERE id IN (SELECT application_id FROM application_contact WHERE contact_id = $1)
+		 ORDER BY id`,
+		contactId)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applications []Application
+	for rows.Next() {
+		application, err := scanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		applications = append(applications, application)
+	}
+
+	return applications, rows.Err()
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContacts(t *testing.T) {
+	controller.CreateScheme()
+	contacts := ContactController{Database: controller.Database, Context: controller.Context}
+
+	applicationId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contactId, err := contacts.InsertContact(Contact{UserId: 1, Name: "Rita Recruiter", Email: "rita@example.com"})
+	assert.Nil(t, err)
+	assert.Nil(t, contacts.LinkContact(applicationId, contactId))
+	assert.Nil(t, contacts.LinkContact(applicationId, contactId))
+
+	linked, err := contacts.GetApplicationContacts(applicationId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(linked))
+	assert.Equal(t, "rita@example.com", linked[0].Email)
+
+	applications, err := contacts.GetContactApplications(contactId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(applications))
+
+	assert.Nil(t, contacts.DeleteContact(contactId))
+
+	linked, err = contacts.GetApplicationContacts(applicationId)
+	assert.Nil(t, err)
+	assert.Empty(t, linked)
+}
@@ -0,0 +1,291 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	FieldText    = "text"
+	FieldNumber  = "number"
+	FieldDate    = "date"
+	FieldEnum    = "enum"
+	FieldBoolean = "boolean"
+)
+
+// fieldDateLayout is the format of the values of date fields. Dates in this
+// format sort correctly as strings.
+const fieldDateLayout = "2006-01-02"
+
+// customFieldKeyPattern restricts the keys of custom fields. Keys are used in
+// query parameters and sort specifications.
+var customFieldKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,63}$`)
+
+// CustomField defines an attribute, which a user can set on all of their
+// applications. The values are stored by key in Application.CustomFields.
+type CustomField struct {
+	Id      int      `db:"id" json:"id"`
+	UserId  int      `db:"user_id" json:"userId"`
+	Key     string   `db:"key" json:"key"`
+	Label   string   `db:"label" json:"label"`
+	Type    string   `db:"type" json:"type"`
+	Options []string `db:"options" json:"options"`
+}
+
+// Validate checks the values, which are restricted by the custom_field table.
+func (f CustomField) Validate() error {
+	if !customFieldKeyPattern.MatchString(f.Key) {
+		return errors.New("the key of a custom field must start with a letter and contain up to 64 letters, digits or underscores")
+	}
+
+	if len(f.Label) > 255 {
+		return errors.New("the label of a custom field must not be longer than 255 characters")
+	}
+
+	switch f.Type {
+	case FieldText, FieldNumber, FieldDate, FieldBoolean:
+		if len(f.Options) > 0 {
+			return errors.New("only enum fields have options")
+		}
+	case FieldEnum:
+		if len(f.Options) == 0 {
+			return errors.New("an enum field needs at least one option")
+		}
+	default:
+		return errors.New("the type of a custom field must be text, number, date, enum or boolean")
+	}
+
+	return nil
+}
+
+// ParseValue checks, that a value decoded from JSON fits the type of the
+// field, and normalizes it. Dates are accepted as strings like 2022-06-30.
+func (f CustomField) ParseValue(value interface{}) (interface{}, error) {
+	switch f.Type {
+	case FieldText:
+		if text, ok := value.(string); ok && len(text) <= 1000 {
+			return text, nil
+		}
+
+		return nil, fmt.Errorf("%s must be a text of up to 1000 characters", f.Key)
+	case FieldNumber:
+		if number, ok := value.(float64); ok {
+			return number, nil
+		}
+
+		return nil, fmt.Errorf("%s must be a number", f.Key)
+	case FieldBoolean:
+		if boolean, ok := value.(bool); ok {
+			return boolean, nil
+		}
+
+		return nil, fmt.Errorf("%s must be true or false", f.Key)
+	case FieldDate:
+		if text, ok := value.(string); ok {
+			if date, err := time.Parse(fieldDateLayout, text); err == nil {
+				return date.Format(fieldDateLayout), nil
+			}
+		}
+
+		return nil, fmt.Errorf("%s must be a date like 2022-06-30", f.Key)
+	case FieldEnum:
+		if text, ok := value.(string); ok && containsString(f.Options, text) {
+			return text, nil
+		}
+
+		return nil, fmt.Errorf("%s must be one of %s", f.Key, strings.Join(f.Options, ", "))
+	}
+
+	return nil, fmt.Errorf("%s has an unknown type", f.Key)
+}
+
+// ParseFilterValue parses the value of a filter given as text, e.g. in a
+// query parameter.
+func (f CustomField) ParseFilterValue(text string) (interface{}, error) {
+	switch f.Type {
+	case FieldNumber:
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", f.Key)
+		}
+
+		return number, nil
+	case FieldBoolean:
+		boolean, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", f.Key)
+		}
+
+		return boolean, nil
+	}
+
+	return f.ParseValue(text)
+}
+
+type CustomFieldController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const customFieldColumns = "id, user_id, key, label, type, options"
+
+func scanCustomField(row pgx.Row) (CustomField, error) {
+	var field CustomField
+	err := row.Scan(&field.Id, &field.UserId, &field.Key, &field.Label, &field.Type, &field.Options)
+
+	return field, err
+}
+
+func (c CustomFieldController) InsertCustomField(field CustomField) (int, error) {
+	if field.Options == nil {
+		field.Options = []string{}
+	}
+
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		"INSERT INTO custom_field (user_id, key, label, type, options) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		field.UserId, field.Key, field.Label, field.Type, field.Options).Scan(&id)
+
+	if err != nil {
+		return -1, mapUniqueViolation(err)
+	}
+
+	return id, nil
+}
+
+func (c CustomFieldController) GetCustomFields(userId int) ([]CustomField, error) {
+	rows, err := c.Database.Query(c.Context, "SELECT "+customFieldColumns+" FROM custom_field WHERE user_id = $1 ORDER BY key", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fields []CustomField
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, rows.Err()
+}
+
+func (c CustomFieldController) GetCustomField(id int) (CustomField, error) {
+	field, err := scanCustomField(c.Database.QueryRow(c.Context, "SELECT "+customFieldColumns+" FROM custom_field WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return CustomField{}, ErrNotFound
+	}
+
+	return field, err
+}
+
+// UpdateCustomField changes the label and the options of the field. The key
+// and the type cannot be changed, because the stored values depend on them.
+func (c CustomFieldController) UpdateCustomField(field CustomField) error {
+	if field.Options == nil {
+		field.Options = []string{}
+	}
+
+	result, err := c.Database.Exec(c.Context, "UPDATE custom_field SET label = $2, options = $3 WHERE id = $1",
+		field.Id, field.Label, field.Options)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteCustomField deletes the field and its values.
+func (c CustomFieldController) DeleteCustomField(id int) error {
+	result, err := c.Database.Exec(c.Context, "DELETE FROM custom_field WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetCustomFieldValues returns the custom field values of the applications by
+// application id and field key.
+func (c CustomFieldController) GetCustomFieldValues(applicationIds []int) (map[int]map[string]interface{}, error) {
+	rows, err := c.Database.Query(c.Context,
+		`SELECT v.application_id, f.key, v.value
+		 FROM application_custom_field v JOIN custom_field f ON f.id = v.field_id
+		 WHERE v.application_id = ANY($1)`, applicationIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := map[int]map[string]interface{}{}
+	for rows.Next() {
+		var applicationId int
+		var key string
+		var raw []byte
+
+		if err := rows.Scan(&applicationId, &key, &raw); err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+
+		if values[applicationId] == nil {
+			values[applicationId] = map[string]interface{}{}
+		}
+
+		values[applicationId][key] = value
+	}
+
+	return values, rows.Err()
+}
+
+// storeCustomFields replaces the custom field values of the application. If
+// the application has no custom fields map, the values are kept.
+func storeCustomFields(ctx context.Context, tx pgx.Tx, application Application) error {
+	if application.CustomFields == nil {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM application_custom_field WHERE application_id = $1", application.Id); err != nil {
+		return err
+	}
+
+	for key, value := range application.CustomFields {
+		result, err := tx.Exec(ctx,
+			`INSERT INTO application_custom_field (application_id, field_id, value)
+			 SELECT $1, id, $3::jsonb FROM custom_field WHERE user_id = $2 AND key = $4`,
+			application.Id, application.UserId, jsonValue{value}, key)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("%w: custom field %s", ErrNotFound, key)
+		}
+	}
+
+	return nil
+}
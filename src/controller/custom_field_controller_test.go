@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomFieldParseValue(t *testing.T) {
+	date := CustomField{Key: "deadline", Type: FieldDate}
+	value, err := date.ParseValue("2022-07-01")
+	assert.Nil(t, err)
+	assert.Equal(t, "2022-07-01", value)
+
+	_, err = date.ParseValue("01.07.2022")
+	assert.NotNil(t, err)
+
+	enum := CustomField{Key: "stack", Type: FieldEnum, Options: []string{"go", "java"}}
+	_, err = enum.ParseValue("go")
+	assert.Nil(t, err)
+
+	_, err = enum.ParseValue("rust")
+	assert.NotNil(t, err)
+
+	number := CustomField{Key: "teamSize", Type: FieldNumber}
+	_, err = number.ParseValue("12")
+	assert.NotNil(t, err)
+
+	value, err = number.ParseFilterValue("12")
+	assert.Nil(t, err)
+	assert.Equal(t, 12.0, value)
+
+	value, err = CustomField{Key: "visa", Type: FieldBoolean}.ParseFilterValue("true")
+	assert.Nil(t, err)
+	assert.Equal(t, true, value)
+}
+
+func TestCustomFieldValidate(t *testing.T) {
+	assert.Nil(t, CustomField{Key: "visa_sponsorship", Type: FieldBoolean}.Validate())
+	assert.NotNil(t, CustomField{Key: "1st", Type: FieldText}.Validate())
+	assert.NotNil(t, CustomField{Key: "team size", Type: FieldNumber}.Validate())
+	assert.NotNil(t, CustomField{Key: "stack", Type: FieldEnum}.Validate())
+	assert.NotNil(t, CustomField{Key: "stack", Type: FieldText, Options: []string{"go"}}.Validate())
+	assert.NotNil(t, CustomField{Key: "stack", Type: "list"}.Validate())
+}
+
+func TestCustomFieldValues(t *testing.T) {
+	controller.CreateScheme()
+	fields := CustomFieldController{Database: controller.Database, Context: controller.Context}
+
+	_, err := fields.InsertCustomField(CustomField{UserId: testApplication.UserId, Key: "teamSize", Type: FieldNumber})
+	assert.Nil(t, err)
+
+	_, err = fields.InsertCustomField(CustomField{UserId: testApplication.UserId, Key: "teamSize", Type: FieldText})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+
+	stackId, err := fields.InsertCustomField(CustomField{UserId: testApplication.UserId, Key: "stack", Type: FieldEnum, Options: []string{"go", "java"}})
+	assert.Nil(t, err)
+
+	first := testApplication
+	first.CustomFields = map[string]interface{}{"teamSize": 12.0, "stack": "go"}
+	firstId, err := controller.InsertApplication(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := testApplication
+	second.CustomFields = map[string]interface{}{"teamSize": 5.0}
+	secondId, err := controller.InsertApplication(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thirdId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := fields.GetCustomFieldValues([]int{firstId, secondId, thirdId})
+	assert.Nil(t, err)
+	assert.Equal(t, 12.0, values[firstId]["teamSize"])
+	assert.Equal(t, "go", values[firstId]["stack"])
+	assert.Nil(t, values[thirdId])
+
+	page, err := controller.QueryApplications(testApplication.UserId, ApplicationQuery{CustomFields: []CustomFieldFilter{{Key: "stack", Value: "go"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.Total)
+	assert.Equal(t, firstId, page.Applications[0].Id)
+
+	sort, _ := ParseSort("customFields.teamSize")
+	query := ApplicationQuery{Limit: 2, Sort: sort}
+	page, err = controller.QueryApplications(testApplication.UserId, query)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{thirdId, secondId}, []int{page.Applications[0].Id, page.Applications[1].Id})
+
+	query.Cursor = page.NextCursor
+	page, err = controller.QueryApplications(testApplication.UserId, query)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(page.Applications))
+	assert.Equal(t, firstId, page.Applications[0].Id)
+
+	// Updates without custom fields keep the values.
+	application, _ := controller.GetApplication(firstId)
+	assert.Nil(t, controller.UpdateApplication(application))
+
+	application.CustomFields = map[string]interface{}{"teamSize": 20.0}
+	assert.Nil(t, controller.UpdateApplication(application))
+
+	values, _ = fields.GetCustomFieldValues([]int{firstId})
+	assert.Equal(t, map[string]interface{}{"teamSize": 20.0}, values[firstId])
+
+	assert.Nil(t, fields.DeleteCustomField(stackId))
+	assert.ErrorIs(t, fields.DeleteCustomField(stackId), ErrNotFound)
+}
@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// The kinds of documents in the library.
+const (
+	DocumentCV          = "cv"
+	DocumentCoverLetter = "cover_letter"
+	DocumentOther       = "other"
+)
+
+// Document is a file in the document library of a user, e.g. a CV. Every
+// upload creates a new version of it.
+type Document struct {
+	Id            int       `db:"id" json:"id"`
+	UserId        int       `db:"user_id" json:"userId"`
+	Name          string    `db:"name" json:"name"`
+	Kind          string    `db:"kind" json:"kind"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+	LatestVersion int       `db:"-" json:"latestVersion"`
+}
+
+// DocumentVersion is an uploaded version of a document. Versions are numbered
+// per document starting at 1. The content is stored in a blob store under
+// the blob key.
+type DocumentVersion struct {
+	Id          int       `db:"id" json:"id"`
+	DocumentId  int       `db:"document_id" json:"documentId"`
+	Version     int       `db:"version" json:"version"`
+	FileName    string    `db:"file_name" json:"fileName"`
+	ContentType string    `db:"content_type" json:"contentType"`
+	Size        int64     `db:"size" json:"size"`
+	BlobKey     string    `db:"blob_key" json:"-"`
+	UploadedAt  time.Time `db:"uploaded_at" json:"uploadedAt"`
+}
+
+type DocumentController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const documentColumns = `id, user_id, name, kind, created_at,
+	(SELECT coalesce(max(version), 0) FROM document_version WHERE document_id = document.id)`
+
+const documentVersionColumns = "id, document_id, version, file_name, content_type, size, blob_key, uploaded_at"
+
+func scanDocument(row pgx.Row) (Document, error) {
+	var document Document
+	err := row.Scan(&document.Id, &document.UserId, &document.Name, &document.Kind, &document.CreatedAt, &document.LatestVersion)
+
+	return document, err
+}
+
+func scanDocumentVersion(row pgx.Row) (DocumentVersion, error) {
+	var version DocumentVersion
+	err := row.Scan(&version.Id, &version.DocumentId, &version.Version, &version.FileName, &version.ContentType,
+		&version.Size, &version.BlobKey, &version.UploadedAt)
+
+	return version, err
+}
+
+func (c DocumentController) queryDocumentVersions(sql string, args ...interface{}) ([]DocumentVersion, error) {
+	rows, err := c.Database.Query(c.Context, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []DocumentVersion
+	for rows.Next() {
+		version, err := scanDocumentVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+// insertDocumentVersion adds the next version to a document. The document is
+// locked, so concurrent uploads get distinct version numbers.
+func insertDocumentVersion(ctx context.Context, tx pgx.Tx, version DocumentVersion) (int, error) {
+	var documentId int
+	if err := tx.QueryRow(ctx, "SELECT id FROM document WHERE id = $1 FOR UPDATE", version.DocumentId).Scan(&documentId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return -1, ErrNotFound
+		}
+
+		return -1, err
+	}
+
+	id := -1
+	err := tx.QueryRow(ctx,
+		`INSERT INTO document_version (document_id, version, file_name, content_type, size, blob_key)
+		 SELECT $1, coalesce(max(version), 0) + 1, $2, $3, $4, $5 FROM document_version WHERE document_id = $1
+		 RETURNING id`,
+		version.DocumentId, version.FileName, version.ContentType, version.Size, version.BlobKey).Scan(&id)
+
+	return id, err
+}
+
+// InsertDocument creates the document together with its first version.
+func (c DocumentController) InsertDocument(document Document, version DocumentVersion) (int, error) {
+	id := -1
+	err := c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(c.Context,
+			"INSERT INTO document (user_id, name, kind) VALUES ($1, $2, $3) RETURNING id",
+			document.UserId, document.Name, document.Kind).Scan(&id); err != nil {
+			return err
+		}
+
+		version.DocumentId = id
+		_, err := insertDocumentVersion(c.Context, tx, version)
+		return err
+	})
+
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// InsertDocumentVersion adds a version to the document and returns the id of
+// the version.
+func (c DocumentController) InsertDocumentVersion(version DocumentVersion) (int, error) {
+	id := -1
+	err := c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		var err error
+		id, err = insertDocumentVersion(c.Context, tx, version)
+		return err
+	})
+
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (c DocumentController) GetDocuments(userId int) ([]Document, error) {
+	rows, err := c.Database.Query(c.Context, "SELECT "+documentColumns+" FROM document WHERE user_id = $1 ORDER BY name, id", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var documents []Document
+	for rows.Next() {
+		document, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, rows.Err()
+}
+
+func (c DocumentController) GetDocument(id int) (Document, error) {
+	document, err := scanDocument(c.Database.QueryRow(c.Context, "SELECT "+documentColumns+" FROM document WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Document{}, ErrNotFound
+	}
+
+	return document, err
+}
+
+// DeleteDocument deletes the document with all its versions. Documents, of
+// which a version was sent with an application, cannot be deleted.
+func (c DocumentController) DeleteDocument(id int) error {
+	tag, err := c.Database.Exec(c.Context, "DELETE FROM document WHERE id = $1", id)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (c DocumentController) GetDocumentVersions(documentId int) ([]DocumentVersion, error) {
+	return c.queryDocumentVersions("SELECT "+documentVersionColumns+" FROM document_version WHERE document_id = $1 ORDER BY version", documentId)
+}
+
+func (c DocumentController) GetDocumentVersion(id int) (DocumentVersion, error) {
+	version, err := scanDocumentVersion(c.Database.QueryRow(c.Context, "SELECT "+documentVersionColumns+" FROM document_version WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DocumentVersion{}, ErrNotFound
+	}
+
+	return version, err
+}
+
+// GetDocumentVersionsByIds fetches several versions at once. Unknown ids are
+// left out.
+func (c DocumentController) GetDocumentVersionsByIds(ids []int) (map[int]DocumentVersion, error) {
+	versions, err := c.queryDocumentVersions("SELECT "+documentVersionColumns+" FROM document_version WHERE id = ANY($1)", ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := map[int]DocumentVersion{}
+	for _, version := range versions {
+		byId[version.Id] = version
+	}
+
+	return byId, nil
+}
+
+// GetVersionApplications returns the applications, which the version was
+// sent with.
+func (c DocumentController) GetVersionApplications(versionId int) ([]Application, error) {
+	rows, err := c.Database.Query(c.Context, "SELECT "+applicationColumns+" FROM application WHERE document_version_id = $1 ORDER BY id", versionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applications []Application
+	for rows.Next() {
+		application, err := scanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		applications = append(applications, application)
+	}
+
+	return applications, rows.Err()
+}
+
+// GetDocumentUsage sums up the sizes of all document versions of the user.
+func (c DocumentController) GetDocumentUsage(userId int) (int64, error) {
+	var usage int64
+	err := c.Database.QueryRow(c.Context,
+		`SELECT coalesce(sum(size), 0) FROM document_version
+		 WHERE document_id IN (SELECT id FROM document WHERE user_id = $1)`, userId).Scan(&usage)
+
+	return usage, err
+}
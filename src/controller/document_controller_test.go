@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentVersions(t *testing.T) {
+	controller.CreateScheme()
+	documents := DocumentController{Database: controller.Database, Context: controller.Context}
+
+	documentId, err := documents.InsertDocument(Document{UserId: 1, Name: "CV", Kind: DocumentCV}, DocumentVersion{FileName: "cv.pdf", Size: 10, BlobKey: "v1"})
+	assert.Nil(t, err)
+
+	secondId, err := documents.InsertDocumentVersion(DocumentVersion{DocumentId: documentId, FileName: "cv.pdf", Size: 20, BlobKey: "v2"})
+	assert.Nil(t, err)
+
+	document, err := documents.GetDocument(documentId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, document.LatestVersion)
+
+	application := testApplication
+	application.DocumentVersionId = &secondId
+	applicationId, err := controller.InsertApplication(application)
+	assert.Nil(t, err)
+
+	applications, err := documents.GetVersionApplications(secondId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(applications))
+	assert.Equal(t, applicationId, applications[0].Id)
+
+	assert.ErrorIs(t, documents.DeleteDocument(documentId), ErrInUse)
+
+	usage, err := documents.GetDocumentUsage(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30), usage)
+}
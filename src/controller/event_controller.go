@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// eventChannel is the channel, on which the database notifies about events
+// written to the outbox. The payload is the id of the user.
+const eventChannel = "outbox_event"
+
+// ListenFunc is called with the id of the user, whenever an event of the user
+// was written to the outbox. It must not block.
+type ListenFunc func(userId int)
+
+type EventController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const outboxEventColumns = "id, position, user_id, event, application_id, payload, created_at, dispatched_at"
+
+func outboxEventTargets(e *OutboxEvent) []interface{} {
+	return []interface{}{&e.Id, &e.Position, &e.UserId, &e.Event, &e.ApplicationId, &e.Payload, &e.CreatedAt, &e.DispatchedAt}
+}
+
+// GetEventsAfter returns up to limit events of the user with a position
+// greater than afterPosition, in the order they were committed. Events of
+// transactions, which have not committed yet, have no position, so they
+// cannot be skipped by a reader, which continues after the last position it
+// has seen.
+func (c EventController) GetEventsAfter(userId int, afterPosition int64, limit int) ([]OutboxEvent, error) {
+	rows, err := c.Database.Query(c.Context,
+		"SELECT "+outboxEventColumns+" FROM outbox_event WHERE user_id = $1 AND position > $2 ORDER BY position LIMIT $3",
+		userId, afterPosition, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []OutboxEvent{}
+	for rows.Next() {
+		var event OutboxEvent
+		if err := rows.Scan(outboxEventTargets(&event)...); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// GetLatestEventPosition returns the position of the latest event in the
+// outbox or 0, if it is empty. Events committed later have a greater
+// position.
+func (c EventController) GetLatestEventPosition() (int64, error) {
+	var position int64
+	err := c.Database.QueryRow(c.Context, "SELECT COALESCE(MAX(position), 0) FROM outbox_event").Scan(&position)
+	return position, err
+}
+
+// PruneEvents deletes the dispatched events written before the given time,
+// which have no pending webhook deliveries, along with their deliveries. It
+// returns the number of deleted events.
+func (c EventController) PruneEvents(before time.Time) (int, error) {
+	result, err := c.Database.Exec(c.Context,
+		`DELETE FROM outbox_event e WHERE e.created_at < $1 AND e.dispatched_at IS NOT NULL
+		 AND NOT EXISTS (SELECT 1 FROM webhook_delivery d WHERE d.event_id = e.id AND d.status = $2)`,
+		before, DeliveryPending)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// ListenEvents calls listen for every event written to the outbox by any
+// replica, until the context is cancelled or the connection fails. It holds a
+// connection of the pool while listening.
+func (c EventController) ListenEvents(ctx context.Context, listen ListenFunc) error {
+	conn, err := c.Database.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			// The connection may still be subscribed to the channel, so it
+			// is closed instead of being returned to the pool.
+			conn.Conn().Close(context.Background())
+
+			if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		userId, err := strconv.Atoi(notification.Payload)
+		if err != nil {
+			continue
+		}
+
+		listen(userId)
+	}
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventLog(t *testing.T) {
+	controller.CreateScheme()
+	events := EventController{Database: controller.Database, Context: controller.Context}
+	webhooks := WebhookController{Database: controller.Database, Context: controller.Context}
+
+	latest, err := events.GetLatestEventPosition()
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listened := make(chan int, 10)
+	done := make(chan error)
+	go func() {
+		done <- events.ListenEvents(ctx, func(userId int) { listened <- userId })
+	}()
+
+	// Events written before the listener subscribed are not notified, so the
+	// application is inserted until a notification arrives.
+	var received bool
+	for i := 0; i < 50 && !received; i++ {
+		if _, err := controller.InsertApplication(testApplication); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case userId := <-listened:
+			assert.Equal(t, testApplication.UserId, userId)
+			received = true
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	assert.True(t, received)
+	cancel()
+	assert.Nil(t, <-done)
+
+	written, err := events.GetEventsAfter(testApplication.UserId, latest, 100)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, written)
+	for _, event := range written {
+		assert.Equal(t, EventApplicationCreated, event.Event)
+		assert.Greater(t, event.Position, latest)
+	}
+
+	other, err := events.GetEventsAfter(testApplication.UserId+1, latest, 100)
+	assert.Nil(t, err)
+	assert.Empty(t, other)
+
+	// Events are pruned once they are dispatched.
+	pruned, err := events.PruneEvents(time.Now().Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, pruned)
+
+	_, err = webhooks.DispatchEvents(time.Now(), 100)
+	assert.Nil(t, err)
+
+	pruned, err = events.PruneEvents(time.Now().Add(time.Hour))
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, pruned, len(written))
+
+	written, _ = events.GetEventsAfter(testApplication.UserId, latest, 100)
+	assert.Empty(t, written)
+}
+
+func TestEventLogOrdersEventsByCommit(t *testing.T) {
+	controller.CreateScheme()
+	events := EventController{Database: controller.Database, Context: controller.Context}
+
+	latest, err := events.GetLatestEventPosition()
+	assert.Nil(t, err)
+
+	insert := "INSERT INTO outbox_event (user_id, event, application_id, payload) VALUES (1, $1, 1, '{}'::jsonb)"
+
+	// The first event takes the smaller id, but commits after the second.
+	tx, err := controller.Database.Begin(controller.Context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(controller.Context)
+
+	if _, err := tx.Exec(controller.Context, insert, EventApplicationUpdated); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := controller.Database.Exec(controller.Context, insert, EventApplicationCreated); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := events.GetEventsAfter(1, latest, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(written))
+	assert.Equal(t, EventApplicationCreated, written[0].Event)
+
+	assert.Nil(t, tx.Commit(controller.Context))
+
+	late, err := events.GetEventsAfter(1, written[0].Position, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(late))
+	assert.Equal(t, EventApplicationUpdated, late[0].Event)
+	assert.Less(t, late[0].Id, written[0].Id)
+}
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// The types of interviews.
+const (
+	InterviewPhone  = "phone"
+	InterviewVideo  = "video"
+	InterviewOnsite = "onsite"
+)
+
+// The outcomes of interviews.
+const (
+	OutcomePending   = "pending"
+	OutcomePassed    = "passed"
+	OutcomeFailed    = "failed"
+	OutcomeCancelled = "cancelled"
+)
+
+// Interview belongs to an application. Location is either an address or a
+// link to a video call.
+type Interview struct {
+	Id              int       `db:"id" json:"id"`
+	ApplicationId   int       `db:"application_id" json:"applicationId"`
+	ScheduledAt     time.Time `db:"scheduled_at" json:"scheduledAt"`
+	DurationMinutes int       `db:"duration_minutes" json:"durationMinutes"`
+	Type            string    `db:"type" json:"type"`
+	Interviewers    []string  `db:"interviewers" json:"interviewers"`
+	Location        string    `db:"location" json:"location"`
+	Outcome         string    `db:"outcome" json:"outcome"`
+	Notes           string    `db:"notes" json:"notes"`
+}
+
+// Validate checks the values, which are restricted by the interview table.
+func (i Interview) Validate() error {
+	if i.Type != InterviewPhone && i.Type != InterviewVideo && i.Type != InterviewOnsite {
+		return errors.New("the type of an interview must be phone, video or onsite")
+	}
+
+	if i.Outcome != OutcomePending && i.Outcome != OutcomePassed && i.Outcome != OutcomeFailed && i.Outcome != OutcomeCancelled {
+		return errors.New("the outcome of an interview must be pending, passed, failed or cancelled")
+	}
+
+	if i.DurationMinutes < 0 {
+		return errors.New("the duration of an interview must not be negative")
+	}
+
+	if i.ScheduledAt.IsZero() {
+		return errors.New("an interview must be scheduled")
+	}
+
+	return nil
+}
+
+type InterviewController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const interviewColumns = "id, application_id, scheduled_at, duration_minutes, type, interviewers, location, outcome, notes"
+
+func scanInterview(row pgx.Row) (Interview, error) {
+	var interview Interview
+	err := row.Scan(&interview.Id, &interview.ApplicationId, &interview.ScheduledAt, &interview.DurationMinutes,
+		&interview.Type, &interview.Interviewers, &interview.Location, &interview.Outcome, &interview.Notes)
+
+	return interview, err
+}
+
+func (c InterviewController) InsertInterview(interview Interview) (int, error) {
+	if interview.Interviewers == nil {
+		interview.Interviewers = []string{}
+	}
+
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		`INSERT INTO interview (application_id, scheduled_at, duration_minutes, type, interviewers, location, outcome, notes)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		interview.ApplicationId, interview.ScheduledAt, interview.DurationMinutes, interview.Type,
+		interview.Interviewers, interview.Location, interview.Outcome, interview.Notes).Scan(&id)
+
+	return id, err
+}
+
+func (c InterviewController) GetInterviews(applicationId int) ([]Interview, error) {
+	rows, err := c.Database.Query(c.Context,
+		"SELECT "+interviewColumns+" FROM interview WHERE application_id = $1 ORDER BY scheduled_at, id", applicationId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var interviews []Interview
+	for rows.Next() {
+		interview, err := scanInterview(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		interviews = append(interviews, interview)
+	}
+
+	return interviews, rows.Err()
+}
+
+// GetUserInterviews returns the interviews of all applications of the user.
+func (c InterviewController) GetUserInterviews(userId int) ([]Interview, error) {
+	rows, err := c.Database.Query(c.Context,
+		`SELECT `+interviewColumns+` FROM interview
+		 WHERE application_id IN (SELECT id FROM application WHERE user_id = $1)
+		 ORDER BY scheduled_at, id`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var interviews []Interview
+	for rows.Next() {
+		interview, err := scanInterview(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		interviews = append(interviews, interview)
+	}
+
+	return interviews, rows.Err()
+}
+
+func (c InterviewController) GetInterview(id int) (Interview, error) {
+	interview, err := scanInterview(c.Database.QueryRow(c.Context, "SELECT "+interviewColumns+" FROM interview WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Interview{}, ErrNotFound
+	}
+
+	return interview, err
+}
+
+// UpdateInterview overwrites the interview. It cannot be moved to another
+// application.
+func (c InterviewController) UpdateInterview(interview Interview) error {
+	if interview.Interviewers == nil {
+		interview.Interviewers = []string{}
+	}
+
+	tag, err := c.Database.Exec(c.Context,
+		`UPDATE interview
+		 SET scheduled_at = $2,
+		     duration_minutes = $3,
+		     type = $4,
+		     interviewers = $5,
+		     location = $6,
+		     outcome = $7,
+		     notes = $8
+		 WHERE id = $1`,
+		interview.Id, interview.ScheduledAt, interview.DurationMinutes, interview.Type,
+		interview.Interviewers, interview.Location, interview.Outcome, interview.Notes)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (c InterviewController) DeleteInterview(id int) error {
+	tag, err := c.Database.Exec(c.Context, "DELETE FROM interview WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
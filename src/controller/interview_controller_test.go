@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testInterview = Interview{
+	ScheduledAt:     time.Date(2022, 7, 1, 10, 0, 0, 0, time.UTC),
+	DurationMinutes: 45,
+	Type:            InterviewVideo,
+	Interviewers:    []string{"Jane Doe"},
+	Location:        "https://meet.example.com/abc",
+	Outcome:         OutcomePending,
+}
+
+func TestInterviewValidate(t *testing.T) {
+	assert.Nil(t, testInterview.Validate())
+
+	interview := testInterview
+	interview.Type = "carrier pigeon"
+	assert.NotNil(t, interview.Validate())
+
+	interview = testInterview
+	interview.Outcome = "maybe"
+	assert.NotNil(t, interview.Validate())
+
+	interview = testInterview
+	interview.DurationMinutes = -1
+	assert.NotNil(t, interview.Validate())
+}
+
+func TestInterviews(t *testing.T) {
+	controller.CreateScheme()
+	interviews := InterviewController{Database: controller.Database, Context: controller.Context}
+
+	applicationId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	interview := testInterview
+	interview.ApplicationId = applicationId
+	id, err := interviews.InsertInterview(interview)
+	assert.Nil(t, err)
+
+	interview.Id = id
+	interview.Outcome = OutcomePassed
+	assert.Nil(t, interviews.UpdateInterview(interview))
+
+	stored, err := interviews.GetInterview(id)
+	assert.Nil(t, err)
+	assert.Equal(t, OutcomePassed, stored.Outcome)
+	assert.Equal(t, []string{"Jane Doe"}, stored.Interviewers)
+
+	assert.Nil(t, controller.DeleteApplication(applicationId))
+
+	_, err = interviews.GetInterview(id)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, interviews.DeleteInterview(id), ErrNotFound)
+}
@@ -0,0 +1,377 @@
+package controller
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// tableRow returns the fields of an entity by the columns in their db tags,
+// so the rows look like the rows of the PostgreSQL implementation.
+func tableRow(entity interface{}) map[string]interface{} {
+	value := reflect.ValueOf(entity)
+	row := map[string]interface{}{}
+
+	for i := 0; i < value.NumField(); i++ {
+		if column := value.Type().Field(i).Tag.Get("db"); column != "" && column != "-" {
+			row[column] = value.Field(i).Interface()
+		}
+	}
+
+	return row
+}
+
+// sortRows orders the rows by the values of the integer columns.
+func sortRows(rows []map[string]interface{}, columns ...string) {
+	key := func(row map[string]interface{}, column string) int64 {
+		switch value := row[column].(type) {
+		case int:
+			return int64(value)
+		case int64:
+			return value
+		}
+
+		return 0
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, column := range columns {
+			if a, b := key(rows[i], column), key(rows[j], column); a != b {
+				return a < b
+			}
+		}
+
+		return false
+	})
+}
+
+// userRows collects the rows of the user by the names of the exportedTables.
+// The store must be locked.
+func (s *MemoryStore) userRows(userId int) map[string][]map[string]interface{} {
+	tables := map[string][]map[string]interface{}{}
+	add := func(table string, row map[string]interface{}) {
+		tables[table] = append(tables[table], row)
+	}
+
+	owned := func(applicationId int) bool {
+		application, ok := s.applications[applicationId]
+		return ok && application.UserId == userId
+	}
+
+	for _, status := range s.statuses {
+		if status.UserId != nil && *status.UserId == userId {
+			add("application_status", tableRow(status))
+		}
+	}
+
+	for _, transition := range s.transitions[userId] {
+		row := tableRow(transition)
+		row["user_id"] = userId
+		add("status_transition", row)
+	}
+
+	for _, company := range s.companies {
+		if company.UserId == userId {
+			add("company", tableRow(company))
+		}
+	}
+
+	for _, contact := range s.contacts {
+		if contact.UserId == userId {
+			add("contact", tableRow(contact))
+		}
+	}
+
+	for _, document := range s.documents {
+		if document.UserId == userId {
+			add("document", tableRow(document))
+		}
+	}
+
+	for _, version := range s.documentVersions {
+		if document, ok := s.documents[version.DocumentId]; ok && document.UserId == userId {
+			add("document_version", tableRow(version))
+		}
+	}
+
+	for _, tag := range s.tags {
+		if tag.UserId == userId {
+			add("tag", tableRow(tag))
+		}
+	}
+
+	for _, field := range s.customFields {
+		if field.UserId == userId {
+			add("custom_field", tableRow(field))
+		}
+	}
+
+	for _, application := range s.applications {
+		if application.UserId == userId {
+			add("application", tableRow(application))
+		}
+	}
+
+	for _, change := range s.statusHistory {
+		if owned(change.ApplicationId) {
+			add("application_status_history", tableRow(change))
+		}
+	}
+
+	for _, interview := range s.interviews {
+		if owned(interview.ApplicationId) {
+			add("interview", tableRow(interview))
+		}
+	}
+
+	for applicationId, contacts := range s.applicationContacts {
+		for contactId := range contacts {
+			if owned(applicationId) {
+				add("application_contact", map[string]interface{}{"application_id": applicationId, "contact_id": contactId})
+			}
+		}
+	}
+
+	for _, attachment := range s.attachments {
+		if attachment.UserId == userId {
+			add("attachment", tableRow(attachment))
+		}
+	}
+
+	for applicationId, tags := range s.applicationTags {
+		for tagId := range tags {
+			if owned(applicationId) {
+				add("application_tag", map[string]interface{}{"application_id": applicationId, "tag_id": tagId})
+			}
+		}
+	}
+
+	for applicationId, values := range s.customFieldValues {
+		for fieldId, value := range values {
+			if owned(applicationId) {
+				add("application_custom_field", map[string]interface{}{"application_id": applicationId, "field_id": fieldId, "value": value})
+			}
+		}
+	}
+
+	for _, reminder := range s.reminders {
+		if owned(reminder.ApplicationId) {
+			add("reminder", tableRow(reminder))
+		}
+	}
+
+	if preferences, ok := s.notificationPreferences[userId]; ok {
+		add("notification_preference", tableRow(preferences))
+	}
+
+	for _, webhook := range s.webhooks {
+		if webhook.UserId == userId {
+			add("webhook", tableRow(webhook))
+		}
+	}
+
+	for _, event := range s.outboxEvents {
+		if event.UserId == userId {
+			add("outbox_event", tableRow(event))
+		}
+	}
+
+	for _, delivery := range s.deliveries {
+		if webhook, ok := s.webhooks[delivery.WebhookId]; ok && webhook.UserId == userId {
+			add("webhook_delivery", tableRow(delivery))
+		}
+	}
+
+	if token, ok := s.calendarTokens[userId]; ok {
+		add("calendar_token", map[string]interface{}{"user_id": userId, "token": token})
+	}
+
+	for _, snapshot := range s.postingSnapshots {
+		if snapshot.UserId == userId {
+			add("job_posting_snapshot", tableRow(snapshot))
+		}
+	}
+
+	for _, record := range s.auditLog {
+		if record.UserId == userId {
+			add("audit_log", tableRow(record))
+		}
+	}
+
+	for _, table := range exportedTables {
+		sortRows(tables[table.name], "id", "user_id", "application_id", "contact_id", "tag_id", "field_id", "from_status_id", "to_status_id")
+	}
+
+	return tables
+}
+
+func (s *MemoryStore) ExportUserData(userId int) ([]UserTable, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := s.userRows(userId)
+	var tables []UserTable
+
+	for _, table := range exportedTables {
+		userTable := UserTable{Name: table.name, Rows: []json.RawMessage{}}
+		for _, row := range rows[table.name] {
+			data, err := json.Marshal(row)
+			if err != nil {
+				return nil, err
+			}
+
+			userTable.Rows = append(userTable.Rows, data)
+		}
+
+		tables = append(tables, userTable)
+	}
+
+	return tables, nil
+}
+
+func (s *MemoryStore) EraseUserData(userId int) (ErasedUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	erased := ErasedUser{Rows: map[string]int64{}}
+	rows := s.userRows(userId)
+	for _, table := range userTables {
+		erased.Rows[table.name] = int64(len(rows[table.name]))
+	}
+
+	for _, row := range rows["attachment"] {
+		erased.BlobKeys = append(erased.BlobKeys, row["blob_key"].(string))
+	}
+
+	for _, row := range rows["document_version"] {
+		erased.BlobKeys = append(erased.BlobKeys, row["blob_key"].(string))
+	}
+
+	for _, row := range rows["job_posting_snapshot"] {
+		erased.BlobKeys = append(erased.BlobKeys, row["blob_key"].(string))
+	}
+
+	statuses := s.statuses[:0]
+	for _, status := range s.statuses {
+		if status.UserId == nil || *status.UserId != userId {
+			statuses = append(statuses, status)
+		}
+	}
+	s.statuses = statuses
+	delete(s.transitions, userId)
+
+	for id, application := range s.applications {
+		if application.UserId != userId {
+			continue
+		}
+
+		history := s.statusHistory[:0]
+		for _, change := range s.statusHistory {
+			if change.ApplicationId != id {
+				history = append(history, change)
+			}
+		}
+		s.statusHistory = history
+
+		delete(s.applications, id)
+		delete(s.applicationContacts, id)
+		delete(s.applicationTags, id)
+		delete(s.customFieldValues, id)
+
+		for interviewId, interview := range s.interviews {
+			if interview.ApplicationId == id {
+				delete(s.interviews, interviewId)
+			}
+		}
+
+		for reminderId, reminder := range s.reminders {
+			if reminder.ApplicationId == id {
+				delete(s.reminders, reminderId)
+			}
+		}
+	}
+
+	for id, attachment := range s.attachments {
+		if attachment.UserId == userId {
+			delete(s.attachments, id)
+		}
+	}
+
+	for id, document := range s.documents {
+		if document.UserId != userId {
+			continue
+		}
+
+		for versionId, version := range s.documentVersions {
+			if version.DocumentId == id {
+				delete(s.documentVersions, versionId)
+			}
+		}
+
+		delete(s.documents, id)
+	}
+
+	for id, company := range s.companies {
+		if company.UserId == userId {
+			delete(s.companies, id)
+		}
+	}
+
+	for id, contact := range s.contacts {
+		if contact.UserId == userId {
+			delete(s.contacts, id)
+		}
+	}
+
+	for id, tag := range s.tags {
+		if tag.UserId == userId {
+			delete(s.tags, id)
+		}
+	}
+
+	for id, field := range s.customFields {
+		if field.UserId == userId {
+			delete(s.customFields, id)
+		}
+	}
+
+	for id, webhook := range s.webhooks {
+		if webhook.UserId != userId {
+			continue
+		}
+
+		for deliveryId, delivery := range s.deliveries {
+			if delivery.WebhookId == id {
+				delete(s.deliveries, deliveryId)
+			}
+		}
+
+		delete(s.webhooks, id)
+	}
+
+	for id, event := range s.outboxEvents {
+		if event.UserId == userId {
+			delete(s.outboxEvents, id)
+		}
+	}
+
+	delete(s.notificationPreferences, userId)
+	delete(s.calendarTokens, userId)
+
+	for id, snapshot := range s.postingSnapshots {
+		if snapshot.UserId == userId {
+			delete(s.postingSnapshots, id)
+		}
+	}
+
+	s.auditLog = append(s.auditLog, AuditRecord{
+		Id:        s.nextAuditRecordId,
+		UserId:    userId,
+		Action:    AuditUserErased,
+		Details:   map[string]interface{}{"rows": erased.Rows},
+		CreatedAt: s.now(),
+	})
+	s.nextAuditRecordId++
+
+	return erased, nil
+}
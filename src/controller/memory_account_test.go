@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fillTestAccount stores data of the user in every repository.
+func fillTestAccount(t *testing.T, store *MemoryStore, userId int) {
+	statusId, _ := store.InsertStatus(ApplicationStatus{Name: "Applied", UserId: &userId, Position: 1})
+	assert.Nil(t, store.SetWorkflow(Workflow{UserId: userId, Transitions: []StatusTransition{{FromStatusId: statusId, ToStatusId: 1}}}))
+	companyId, _ := store.InsertCompany(Company{UserId: userId, Name: "ACME"})
+	contactId, _ := store.InsertContact(Contact{UserId: userId, Name: "Jane"})
+	store.InsertDocument(Document{UserId: userId, Name: "CV", Kind: DocumentCV}, DocumentVersion{FileName: "cv.pdf", Size: 10, BlobKey: fmt.Sprintf("document-%d", userId)})
+	tagId, _ := store.InsertTag(Tag{UserId: userId, Name: "Go"})
+	store.InsertCustomField(CustomField{UserId: userId, Key: "teamSize", Type: FieldNumber})
+
+	applicationId, err := store.InsertApplication(Application{
+		UserId: userId, WorkTypeId: 1, StatusId: statusId, CompanyId: &companyId,
+		CustomFields: map[string]interface{}{"teamSize": 8.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.InsertInterview(Interview{ApplicationId: applicationId, ScheduledAt: time.Now(), Type: InterviewPhone, Outcome: OutcomePending})
+	store.LinkContact(applicationId, contactId)
+	store.InsertAttachment(Attachment{ApplicationId: applicationId, UserId: userId, FileName: "letter.pdf", BlobKey: fmt.Sprintf("attachment-%d", userId)})
+	store.TagApplications(tagId, []int{applicationId})
+	store.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: time.Now(), Message: "Call back"})
+	store.SaveNotificationPreferences(NotificationPreferences{UserId: userId, Email: "jane@example.com"})
+	store.InsertWebhook(Webhook{UserId: userId, Url: "https://example.com/hook", Secret: "secret", Events: []string{EventApplicationCreated}, Active: true})
+	store.DispatchEvents(time.Now(), 10)
+	store.SaveCalendarToken(userId, fmt.Sprintf("token-%d", userId))
+	store.InsertPostingSnapshot(PostingSnapshot{UserId: userId, Url: "https://example.com/jobs/1", BlobKey: fmt.Sprintf("posting-%d", userId)})
+}
+
+func TestMemoryExportUserData(t *testing.T) {
+	store := NewMemoryStore()
+	fillTestAccount(t, store, 1)
+	fillTestAccount(t, store, 2)
+
+	tables, err := store.ExportUserData(1)
+	assert.Nil(t, err)
+	assert.Equal(t, len(userTables)+1, len(tables))
+
+	for _, table := range tables {
+		if table.Name == "audit_log" {
+			assert.Empty(t, table.Rows)
+			continue
+		}
+
+		assert.Equal(t, 1, len(table.Rows), table.Name)
+
+		var row map[string]interface{}
+		assert.Nil(t, json.Unmarshal(table.Rows[0], &row))
+		if userId, ok := row["user_id"]; ok {
+			assert.Equal(t, float64(1), userId, table.Name)
+		}
+	}
+
+	assert.Equal(t, "application", tables[8].Name)
+	var application map[string]interface{}
+	json.Unmarshal(tables[8].Rows[0], &application)
+	assert.Equal(t, float64(1), application["id"])
+	assert.Equal(t, float64(1), application["company_id"])
+}
+
+func TestMemoryEraseUserData(t *testing.T) {
+	store := NewMemoryStore()
+	fillTestAccount(t, store, 1)
+	fillTestAccount(t, store, 2)
+
+	erased, err := store.EraseUserData(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"attachment-1", "document-1", "posting-1"}, erased.BlobKeys)
+	assert.Equal(t, len(userTables), len(erased.Rows))
+	assert.Equal(t, int64(1), erased.Rows["application"])
+	assert.Equal(t, int64(1), erased.Rows["webhook_delivery"])
+
+	tables, _ := store.ExportUserData(1)
+	for _, table := range tables {
+		if table.Name != "audit_log" {
+			assert.Empty(t, table.Rows, table.Name)
+		}
+	}
+
+	var record map[string]interface{}
+	assert.Equal(t, 1, len(tables[len(tables)-1].Rows))
+	json.Unmarshal(tables[len(tables)-1].Rows[0], &record)
+	assert.Equal(t, AuditUserErased, record["action"])
+	assert.Equal(t, float64(1), record["details"].(map[string]interface{})["rows"].(map[string]interface{})["application"])
+
+	// The data of the other user and the default statuses are kept.
+	tables, _ = store.ExportUserData(2)
+	for _, table := range tables[:len(tables)-1] {
+		assert.Equal(t, 1, len(table.Rows), table.Name)
+	}
+
+	statuses, _ := store.GetStatuses()
+	assert.Equal(t, 3, len(statuses))
+}
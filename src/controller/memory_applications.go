@@ -0,0 +1,407 @@
+package controller
+
+import (
+	"sort"
+	"time"
+)
+
+func (s *MemoryStore) hasWorkType(id int) bool {
+	for _, workType := range s.workTypes {
+		if workType.Id == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *MemoryStore) hasStatus(id int) bool {
+	for _, status := range s.statuses {
+		if status.Id == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkApplication enforces the same constraints as the application table.
+func (s *MemoryStore) checkApplication(application Application) error {
+	if !s.hasWorkType(application.WorkTypeId) || !s.hasStatus(application.StatusId) {
+		return ErrConstraintViolation
+	}
+
+	if len(application.JobTitle) > 255 || len(application.CompanyName) > 255 || len(application.Commentary) > 500 || len(application.JobPostingUrl) > 2000 {
+		return ErrConstraintViolation
+	}
+
+	if application.DocumentVersionId != nil {
+		if _, ok := s.documentVersions[*application.DocumentVersionId]; !ok {
+			return ErrConstraintViolation
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) InsertApplication(application Application) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkApplication(application); err != nil {
+		return -1, err
+	}
+
+	return s.insertApplication(application)
+}
+
+// InsertApplications checks all applications before the first one is
+// inserted, so either all or none of them are inserted.
+func (s *MemoryStore) InsertApplications(applications []Application) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, application := range applications {
+		if err := s.checkApplication(application); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]int, 0, len(applications))
+	for _, application := range applications {
+		id, err := s.insertApplication(application)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// insertApplication inserts a checked application. The store must be locked.
+func (s *MemoryStore) insertApplication(application Application) (int, error) {
+	if err := s.assignCompany(&application); err != nil {
+		return -1, err
+	}
+
+	application.Id = s.nextApplicationId
+	application.SubmissionDate = truncateDate(application.SubmissionDate)
+	application.StartDate = truncateDate(application.StartDate)
+	application.StatusChangedAt = initialStatusChange(application, s.now().UTC())
+	application.Stale = false
+
+	if err := s.storeCustomFields(application); err != nil {
+		return -1, err
+	}
+
+	// Like in the database, the custom field values are stored separately.
+	application.CustomFields = nil
+	s.applications[application.Id] = application
+	s.nextApplicationId++
+	s.recordStatusChange(application.Id, nil, application.StatusId, application.UserId)
+
+	if err := s.enqueueApplicationEvent(EventApplicationCreated, application, nil); err != nil {
+		return -1, err
+	}
+
+	return application.Id, nil
+}
+
+func (s *MemoryStore) GetApplications(userId int) ([]Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var applications []Application
+	for _, application := range s.applications {
+		if application.UserId == userId {
+			applications = append(applications, application)
+		}
+	}
+
+	sort.Slice(applications, func(i, j int) bool {
+		return applications[i].Id < applications[j].Id
+	})
+
+	return applications, nil
+}
+
+// StreamApplications calls each without holding the lock, so each may use
+// the store.
+func (s *MemoryStore) StreamApplications(userId int, each func(Application) error) error {
+	applications, _ := s.GetApplications(userId)
+
+	for _, application := range applications {
+		if err := each(application); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) QueryApplications(userId int, query ApplicationQuery) (ApplicationPage, error) {
+	fields := query.sortFields()
+
+	var after []interface{}
+	if query.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(fields, query.Cursor); err != nil {
+			return ApplicationPage{}, err
+		}
+	}
+
+	applications, _ := s.GetApplications(userId)
+
+	var page ApplicationPage
+	var matching []Application
+	for _, application := range applications {
+		application.CustomFields = s.applicationCustomFields(application.Id)
+		if !query.matches(application) || !query.matchesTags(s.tagNames(application.Id)) {
+			continue
+		}
+
+		page.Total++
+		if after == nil || compareKeys(fields, sortKey(fields, application), after) > 0 {
+			matching = append(matching, application)
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return compareKeys(fields, sortKey(fields, matching[i]), sortKey(fields, matching[j])) < 0
+	})
+
+	if query.Limit > 0 && len(matching) > query.Limit {
+		matching = matching[:query.Limit]
+		page.NextCursor = encodeCursor(fields, sortKey(fields, matching[query.Limit-1]))
+	}
+
+	page.Applications = matching
+	return page, nil
+}
+
+func (s *MemoryStore) SearchApplications(userId int, query string, limit int) ([]SearchResult, error) {
+	applications, _ := s.GetApplications(userId)
+	tokens := tokenize(query)
+
+	var results []SearchResult
+	for _, application := range applications {
+		if result, ok := matchApplication(application, tokens); ok {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+func (s *MemoryStore) GetApplication(id int) (Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	application, ok := s.applications[id]
+	if !ok {
+		return Application{}, ErrNotFound
+	}
+
+	return application, nil
+}
+
+func (s *MemoryStore) DeleteApplication(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	application, ok := s.applications[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if err := s.enqueueApplicationEvent(EventApplicationDeleted, application, nil); err != nil {
+		return err
+	}
+
+	delete(s.applications, id)
+
+	history := s.statusHistory[:0]
+	for _, change := range s.statusHistory {
+		if change.ApplicationId != id {
+			history = append(history, change)
+		}
+	}
+	s.statusHistory = history
+
+	for interviewId, interview := range s.interviews {
+		if interview.ApplicationId == id {
+			delete(s.interviews, interviewId)
+		}
+	}
+
+	delete(s.applicationContacts, id)
+	delete(s.applicationTags, id)
+	delete(s.customFieldValues, id)
+
+	for reminderId, reminder := range s.reminders {
+		if reminder.ApplicationId == id {
+			delete(s.reminders, reminderId)
+		}
+	}
+
+	for attachmentId, attachment := range s.attachments {
+		if attachment.ApplicationId == id {
+			delete(s.attachments, attachmentId)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) UpdateApplication(application Application) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateApplication(application, nil)
+}
+
+func (s *MemoryStore) UpdateApplicationFromStatus(application Application, fromStatusId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateApplication(application, &fromStatusId)
+}
+
+// updateApplication stores the application, if it is still in fromStatusId
+// or fromStatusId is nil. The store must be locked.
+func (s *MemoryStore) updateApplication(application Application, fromStatusId *int) error {
+	old, ok := s.applications[application.Id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if fromStatusId != nil && *fromStatusId != old.StatusId {
+		return ErrStatusChanged
+	}
+
+	if err := s.checkApplication(application); err != nil {
+		return err
+	}
+
+	if err := s.assignCompany(&application); err != nil {
+		return err
+	}
+
+	if err := s.storeCustomFields(application); err != nil {
+		return err
+	}
+
+	application.SubmissionDate = truncateDate(application.SubmissionDate)
+	application.StartDate = truncateDate(application.StartDate)
+	application.CustomFields = nil
+
+	// Entering another status resets the stale detection.
+	application.StatusChangedAt, application.Stale = old.StatusChangedAt, old.Stale
+	if old.StatusId != application.StatusId {
+		application.StatusChangedAt, application.Stale = s.now().UTC(), false
+	}
+
+	s.applications[application.Id] = application
+
+	if err := s.enqueueApplicationEvent(EventApplicationUpdated, application, nil); err != nil {
+		return err
+	}
+
+	if old.StatusId != application.StatusId {
+		s.recordStatusChange(application.Id, &old.StatusId, application.StatusId, application.UserId)
+		return s.enqueueApplicationEvent(EventApplicationStatusChanged, application, &old.StatusId)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) MarkStaleApplications(now time.Time) ([]Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []int
+	for id := range s.applications {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var marked []Application
+	for _, id := range ids {
+		application := s.applications[id]
+		status := s.statuses[s.statusIndex(application.StatusId)]
+
+		due := !status.Terminal && status.StaleAfterDays != nil &&
+			!application.StatusChangedAt.After(now.AddDate(0, 0, -*status.StaleAfterDays))
+
+		switch {
+		case !due:
+			application.Stale = false
+		case application.Stale:
+			continue
+		case status.StaleStatusId != nil && s.isTransitionAllowed(application.UserId, status.Id, *status.StaleStatusId):
+			application.StatusId = *status.StaleStatusId
+			application.StatusChangedAt = now.UTC()
+			s.recordStatusChange(id, &status.Id, application.StatusId, application.UserId)
+			if err := s.enqueueApplicationEvent(EventApplicationUpdated, application, nil); err != nil {
+				return nil, err
+			}
+
+			if err := s.enqueueApplicationEvent(EventApplicationStatusChanged, application, &status.Id); err != nil {
+				return nil, err
+			}
+
+			marked = append(marked, application)
+		default:
+			application.Stale = true
+			marked = append(marked, application)
+		}
+
+		s.applications[id] = application
+	}
+
+	return marked, nil
+}
+
+// isTransitionAllowed checks the transition against the workflow of the user
+// without reopening the application. The store must be locked.
+func (s *MemoryStore) isTransitionAllowed(userId int, fromStatusId int, toStatusId int) bool {
+	workflow := Workflow{UserId: userId, Transitions: s.transitions[userId]}
+	return workflow.CheckTransition(fromStatusId, toStatusId, false) == nil
+}
+
+func (s *MemoryStore) recordStatusChange(applicationId int, oldStatusId *int, newStatusId int, userId int) {
+	s.statusHistory = append(s.statusHistory, StatusChange{
+		Id:            s.nextStatusChangeId,
+		ApplicationId: applicationId,
+		OldStatusId:   oldStatusId,
+		NewStatusId:   newStatusId,
+		ChangedAt:     s.now().UTC(),
+		ChangedBy:     userId,
+	})
+
+	s.nextStatusChangeId++
+}
+
+func (s *MemoryStore) GetStatusHistory(applicationId int) ([]StatusChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var history []StatusChange
+	for _, change := range s.statusHistory {
+		if change.ApplicationId == applicationId {
+			history = append(history, change)
+		}
+	}
+
+	return history, nil
+}
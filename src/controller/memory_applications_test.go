@@ -0,0 +1,350 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryInsertApplication(t *testing.T) {
+	store := NewMemoryStore()
+
+	firstId, err := store.InsertApplication(testApplication)
+	assert.Nil(t, err)
+
+	secondId, err := store.InsertApplication(testApplication)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, firstId)
+	assert.Equal(t, 2, secondId)
+}
+
+func TestMemoryInsertApplications(t *testing.T) {
+	store := NewMemoryStore()
+
+	// Nothing is inserted, if an application violates a constraint.
+	_, err := store.InsertApplications([]Application{
+		{UserId: 1, WorkTypeId: 1, StatusId: 1},
+		{UserId: 1, WorkTypeId: 42, StatusId: 1},
+	})
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+
+	applications, _ := store.GetApplications(1)
+	assert.Empty(t, applications)
+
+	ids, err := store.InsertApplications([]Application{
+		{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer"},
+		{UserId: 1, WorkTypeId: 2, StatusId: 2, JobTitle: "Rust Developer"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(ids))
+
+	application, _ := store.GetApplication(ids[1])
+	assert.Equal(t, "Rust Developer", application.JobTitle)
+	assert.Equal(t, 2, len(store.GetOutboxEvents()))
+}
+
+func TestMemoryInsertApplicationConstraintViolation(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.InsertApplication(Application{UserId: 1, JobTitle: "test"})
+
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+}
+
+func TestMemoryGetApplications(t *testing.T) {
+	store := NewMemoryStore()
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	store.InsertApplication(Application{UserId: 2, WorkTypeId: 1, StatusId: 1})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+
+	applications, err := store.GetApplications(1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(applications))
+	assert.Equal(t, 1, applications[0].Id)
+	assert.Equal(t, 3, applications[1].Id)
+}
+
+func TestMemoryStreamApplications(t *testing.T) {
+	store := NewMemoryStore()
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	store.InsertApplication(Application{UserId: 2, WorkTypeId: 1, StatusId: 1})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+
+	var ids []int
+	err := store.StreamApplications(1, func(application Application) error {
+		ids = append(ids, application.Id)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 3}, ids)
+
+	err = store.StreamApplications(1, func(application Application) error {
+		return ErrNotFound
+	})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryGetApplication(t *testing.T) {
+	store := NewMemoryStore()
+
+	id, err := store.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application, err := store.GetApplication(id)
+
+	assert.Nil(t, err)
+	assert.Equal(t, testApplication.JobTitle, application.JobTitle)
+	assert.Equal(t, time.UTC, application.SubmissionDate.Location())
+	assert.Equal(t, 0, application.SubmissionDate.Hour())
+}
+
+func TestMemoryGetApplicationDoesNotExist(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.GetApplication(1)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryDeleteApplication(t *testing.T) {
+	store := NewMemoryStore()
+
+	id, err := store.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, store.DeleteApplication(id))
+	assert.ErrorIs(t, store.DeleteApplication(id), ErrNotFound)
+
+	_, err = store.GetApplication(id)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryUpdateApplication(t *testing.T) {
+	store := NewMemoryStore()
+
+	id, err := store.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updatedApplication := testApplication
+	updatedApplication.Id = id
+	updatedApplication.JobTitle = "another job title"
+
+	assert.Nil(t, store.UpdateApplication(updatedApplication))
+
+	application, err := store.GetApplication(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "another job title", application.JobTitle)
+}
+
+func TestMemoryUpdateApplicationFromStatus(t *testing.T) {
+	store := NewMemoryStore()
+	application := Application{UserId: 1, WorkTypeId: 1, StatusId: 2}
+	application.Id, _ = store.InsertApplication(application)
+
+	application.StatusId = 3
+	assert.Nil(t, store.UpdateApplicationFromStatus(application, 2))
+
+	// A second transition checked against the previous status is rejected.
+	application.StatusId = 1
+	assert.ErrorIs(t, store.UpdateApplicationFromStatus(application, 2), ErrStatusChanged)
+
+	stored, _ := store.GetApplication(application.Id)
+	assert.Equal(t, 3, stored.StatusId)
+}
+
+func TestMemoryUpdateApplicationDoesNotExist(t *testing.T) {
+	store := NewMemoryStore()
+
+	updatedApplication := testApplication
+	updatedApplication.Id = 42
+
+	assert.ErrorIs(t, store.UpdateApplication(updatedApplication), ErrNotFound)
+}
+
+func TestMemoryGetTypes(t *testing.T) {
+	store := NewMemoryStore()
+
+	workTypes, err := store.GetWorkTypes()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(workTypes))
+
+	statuses, err := store.GetStatuses()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(statuses))
+}
+
+func TestMemoryQueryApplicationsPaging(t *testing.T) {
+	store := NewMemoryStore()
+	salaries := []float32{3000, 5000, 4000, 5000, 1000}
+	for _, salary := range salaries {
+		store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, WantedSalary: salary})
+	}
+	store.InsertApplication(Application{UserId: 2, WorkTypeId: 1, StatusId: 1})
+
+	query := ApplicationQuery{Limit: 2, Sort: []SortField{{Field: "wantedSalary", Descending: true}}}
+
+	var ids []int
+	for {
+		page, err := store.QueryApplications(1, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 5, page.Total)
+		for _, application := range page.Applications {
+			ids = append(ids, application.Id)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		query.Cursor = page.NextCursor
+	}
+
+	assert.Equal(t, []int{2, 4, 3, 1, 5}, ids)
+}
+
+func TestMemoryQueryApplicationsFilter(t *testing.T) {
+	store := NewMemoryStore()
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, CompanyName: "ACME"})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2, CompanyName: "ACME"})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2, CompanyName: "Initech"})
+
+	page, err := store.QueryApplications(1, ApplicationQuery{StatusIds: []int{2}, Company: "acm"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.Total)
+	assert.Equal(t, 2, page.Applications[0].Id)
+	assert.Equal(t, "", page.NextCursor)
+}
+
+func TestMemorySearchApplications(t *testing.T) {
+	store := NewMemoryStore()
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Java Developer", Commentary: "Not Go"})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer"})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Accountant"})
+	store.InsertApplication(Application{UserId: 2, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer"})
+
+	results, err := store.SearchApplications(1, "go", 10)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.Equal(t, 2, results[0].Application.Id)
+	assert.Equal(t, 1, results[1].Application.Id)
+
+	results, err = store.SearchApplications(1, "go", 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+}
+
+func TestMemoryStatusHistory(t *testing.T) {
+	store := NewMemoryStore()
+
+	id, err := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application, _ := store.GetApplication(id)
+	application.Commentary = "no status change"
+	store.UpdateApplication(application)
+	application.StatusId = 3
+	store.UpdateApplication(application)
+
+	history, err := store.GetStatusHistory(id)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(history))
+	assert.Nil(t, history[0].OldStatusId)
+	assert.Equal(t, 2, history[0].NewStatusId)
+	assert.Equal(t, 2, *history[1].OldStatusId)
+	assert.Equal(t, 3, history[1].NewStatusId)
+	assert.Equal(t, 1, history[1].ChangedBy)
+
+	store.DeleteApplication(id)
+	history, _ = store.GetStatusHistory(id)
+
+	assert.Empty(t, history)
+}
+
+func TestMemoryMarkStaleApplications(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	ghostedId, _ := store.InsertStatus(ApplicationStatus{Name: "Ghosted", Terminal: true})
+	days := 10
+	waitingId, _ := store.InsertStatus(ApplicationStatus{Name: "Waiting", StaleAfterDays: &days, StaleStatusId: &ghostedId})
+
+	staleId, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2, SubmissionDate: now.AddDate(0, 0, -40)})
+	freshId, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2, SubmissionDate: now.AddDate(0, 0, -5)})
+	movedId, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: waitingId, SubmissionDate: now.AddDate(0, 0, -12)})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, SubmissionDate: now.AddDate(0, 0, -40)})
+
+	marked, err := store.MarkStaleApplications(now)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(marked))
+	assert.Equal(t, staleId, marked[0].Id)
+	assert.True(t, marked[0].Stale)
+	assert.Equal(t, movedId, marked[1].Id)
+	assert.Equal(t, ghostedId, marked[1].StatusId)
+	assert.False(t, marked[1].Stale)
+
+	fresh, _ := store.GetApplication(freshId)
+	assert.False(t, fresh.Stale)
+
+	history, _ := store.GetStatusHistory(movedId)
+	assert.Equal(t, 2, len(history))
+	assert.Equal(t, ghostedId, history[1].NewStatusId)
+
+	// Applications are marked only once.
+	marked, _ = store.MarkStaleApplications(now)
+	assert.Empty(t, marked)
+
+	// Entering another status resets the flag.
+	stale, _ := store.GetApplication(staleId)
+	stale.StatusId = 3
+	store.UpdateApplication(stale)
+	stale, _ = store.GetApplication(staleId)
+	assert.False(t, stale.Stale)
+	assert.Equal(t, now, stale.StatusChangedAt)
+}
+
+func TestMemoryMarkStaleApplicationsRespectsWorkflow(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	userId := 1
+	ghostedId, _ := store.InsertStatus(ApplicationStatus{Name: "Ghosted", UserId: &userId, Terminal: true})
+	days := 10
+	waitingId, _ := store.InsertStatus(ApplicationStatus{Name: "Waiting", UserId: &userId, StaleAfterDays: &days, StaleStatusId: &ghostedId})
+
+	// The workflow only allows to move waiting applications to declined.
+	assert.Nil(t, store.SetWorkflow(Workflow{UserId: userId, Transitions: []StatusTransition{{FromStatusId: waitingId, ToStatusId: 3}}}))
+
+	id, _ := store.InsertApplication(Application{UserId: userId, WorkTypeId: 1, StatusId: waitingId, SubmissionDate: now.AddDate(0, 0, -12)})
+
+	marked, err := store.MarkStaleApplications(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(marked))
+	assert.Equal(t, waitingId, marked[0].StatusId)
+	assert.True(t, marked[0].Stale)
+
+	history, _ := store.GetStatusHistory(id)
+	assert.Equal(t, 1, len(history))
+}
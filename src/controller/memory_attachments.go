@@ -0,0 +1,85 @@
+package controller
+
+import "sort"
+
+func (s *MemoryStore) InsertAttachment(attachment Attachment) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.applications[attachment.ApplicationId]; !ok {
+		return -1, ErrConstraintViolation
+	}
+
+	if len(attachment.FileName) > 255 || len(attachment.ContentType) > 255 || len(attachment.BlobKey) > 500 || attachment.Size < 0 {
+		return -1, ErrConstraintViolation
+	}
+
+	for _, other := range s.attachments {
+		if other.BlobKey == attachment.BlobKey {
+			return -1, ErrConstraintViolation
+		}
+	}
+
+	attachment.Id = s.nextAttachmentId
+	attachment.UploadedAt = s.now().UTC()
+	s.nextAttachmentId++
+	s.attachments[attachment.Id] = attachment
+
+	return attachment.Id, nil
+}
+
+func (s *MemoryStore) GetAttachments(applicationId int) ([]Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var attachments []Attachment
+	for _, attachment := range s.attachments {
+		if attachment.ApplicationId == applicationId {
+			attachments = append(attachments, attachment)
+		}
+	}
+
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].Id < attachments[j].Id
+	})
+
+	return attachments, nil
+}
+
+func (s *MemoryStore) GetAttachment(id int) (Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.attachments[id]
+	if !ok {
+		return Attachment{}, ErrNotFound
+	}
+
+	return attachment, nil
+}
+
+func (s *MemoryStore) DeleteAttachment(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.attachments[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.attachments, id)
+	return nil
+}
+
+func (s *MemoryStore) GetAttachmentUsage(userId int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var usage int64
+	for _, attachment := range s.attachments {
+		if attachment.UserId == userId {
+			usage += attachment.Size
+		}
+	}
+
+	return usage, nil
+}
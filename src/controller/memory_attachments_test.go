@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryAttachments(t *testing.T) {
+	store := NewMemoryStore()
+	applicationId, _ := store.InsertApplication(testApplication)
+
+	id, err := store.InsertAttachment(Attachment{ApplicationId: applicationId, UserId: 1, FileName: "cv.pdf", Size: 100, BlobKey: "a"})
+	assert.Nil(t, err)
+	_, err = store.InsertAttachment(Attachment{ApplicationId: applicationId, UserId: 1, FileName: "letter.pdf", Size: 50, BlobKey: "b"})
+	assert.Nil(t, err)
+
+	_, err = store.InsertAttachment(Attachment{ApplicationId: applicationId, UserId: 1, BlobKey: "a"})
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+
+	usage, err := store.GetAttachmentUsage(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(150), usage)
+
+	assert.Nil(t, store.DeleteAttachment(id))
+	assert.ErrorIs(t, store.DeleteAttachment(id), ErrNotFound)
+
+	store.DeleteApplication(applicationId)
+	attachments, _ := store.GetAttachments(applicationId)
+	assert.Empty(t, attachments)
+}
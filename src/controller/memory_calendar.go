@@ -0,0 +1,56 @@
+package controller
+
+func (s *MemoryStore) GetCalendarToken(userId int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.calendarTokens[userId]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return token, nil
+}
+
+func (s *MemoryStore) GetCalendarTokenUser(token string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for userId, other := range s.calendarTokens {
+		if other == token {
+			return userId, nil
+		}
+	}
+
+	return -1, ErrNotFound
+}
+
+func (s *MemoryStore) SaveCalendarToken(userId int, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(token) > 64 {
+		return ErrConstraintViolation
+	}
+
+	for other, existing := range s.calendarTokens {
+		if other != userId && existing == token {
+			return ErrConstraintViolation
+		}
+	}
+
+	s.calendarTokens[userId] = token
+	return nil
+}
+
+func (s *MemoryStore) DeleteCalendarToken(userId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.calendarTokens[userId]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.calendarTokens, userId)
+	return nil
+}
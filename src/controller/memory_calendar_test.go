@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCalendarTokens(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.GetCalendarToken(1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Nil(t, store.SaveCalendarToken(1, "first"))
+	assert.ErrorIs(t, store.SaveCalendarToken(2, "first"), ErrConstraintViolation)
+
+	userId, err := store.GetCalendarTokenUser("first")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, userId)
+
+	// Saving another token rotates it.
+	assert.Nil(t, store.SaveCalendarToken(1, "second"))
+	token, _ := store.GetCalendarToken(1)
+	assert.Equal(t, "second", token)
+
+	_, err = store.GetCalendarTokenUser("first")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Nil(t, store.DeleteCalendarToken(1))
+	assert.ErrorIs(t, store.DeleteCalendarToken(1), ErrNotFound)
+}
@@ -0,0 +1,149 @@
+package controller
+
+import "sort"
+
+// checkCompany enforces the same constraints as the company table. The
+// company is compared to the other companies of the same user.
+func (s *MemoryStore) checkCompany(company Company) error {
+	if len(company.Name) > 255 || len(company.Website) > 500 || len(company.Industry) > 255 ||
+		len(company.Size) > 64 || len(company.Location) > 255 {
+		return ErrConstraintViolation
+	}
+
+	normalizedName := NormalizeCompanyName(company.Name)
+	for _, other := range s.companies {
+		if other.Id != company.Id && other.UserId == company.UserId && NormalizeCompanyName(other.Name) == normalizedName {
+			return ErrAlreadyExists
+		}
+	}
+
+	return nil
+}
+
+// assignCompany assigns the application to the company matching its company
+// name, like the PostgreSQL implementation does.
+func (s *MemoryStore) assignCompany(application *Application) error {
+	normalizedName := NormalizeCompanyName(application.CompanyName)
+	if application.CompanyId != nil {
+		if _, ok := s.companies[*application.CompanyId]; !ok {
+			return ErrConstraintViolation
+		}
+
+		return nil
+	}
+
+	if normalizedName == "" {
+		return nil
+	}
+
+	for _, company := range s.companies {
+		if company.UserId == application.UserId && NormalizeCompanyName(company.Name) == normalizedName {
+			companyId := company.Id
+			application.CompanyId = &companyId
+			return nil
+		}
+	}
+
+	company := Company{Id: s.nextCompanyId, UserId: application.UserId, Name: application.CompanyName}
+	s.nextCompanyId++
+	s.companies[company.Id] = company
+
+	application.CompanyId = &company.Id
+	return nil
+}
+
+func (s *MemoryStore) InsertCompany(company Company) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	company.Id = s.nextCompanyId
+	if err := s.checkCompany(company); err != nil {
+		return -1, err
+	}
+
+	s.nextCompanyId++
+	s.companies[company.Id] = company
+
+	return company.Id, nil
+}
+
+func (s *MemoryStore) GetCompanies(userId int) ([]Company, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var companies []Company
+	for _, company := range s.companies {
+		if company.UserId == userId {
+			companies = append(companies, company)
+		}
+	}
+
+	sort.Slice(companies, func(i, j int) bool {
+		first, second := NormalizeCompanyName(companies[i].Name), NormalizeCompanyName(companies[j].Name)
+		if first != second {
+			return first < second
+		}
+
+		return companies[i].Id < companies[j].Id
+	})
+
+	return companies, nil
+}
+
+func (s *MemoryStore) GetCompany(id int) (Company, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	company, ok := s.companies[id]
+	if !ok {
+		return Company{}, ErrNotFound
+	}
+
+	return company, nil
+}
+
+func (s *MemoryStore) UpdateCompany(company Company) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.companies[company.Id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	company.UserId = old.UserId
+	if err := s.checkCompany(company); err != nil {
+		return err
+	}
+
+	s.companies[company.Id] = company
+
+	for id, application := range s.applications {
+		if application.CompanyId != nil && *application.CompanyId == company.Id {
+			application.CompanyName = company.Name
+			s.applications[id] = application
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteCompany(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.companies[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.companies, id)
+
+	for applicationId, application := range s.applications {
+		if application.CompanyId != nil && *application.CompanyId == id {
+			application.CompanyId = nil
+			s.applications[applicationId] = application
+		}
+	}
+
+	return nil
+}
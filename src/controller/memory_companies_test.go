@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryInsertApplicationAssignsCompany(t *testing.T) {
+	store := NewMemoryStore()
+
+	var companyIds []int
+	for _, name := range []string{"ACME", "Acme Inc.", "acme", "Initech"} {
+		application := testApplication
+		application.CompanyName = name
+
+		id, err := store.InsertApplication(application)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		application, _ = store.GetApplication(id)
+		companyIds = append(companyIds, *application.CompanyId)
+	}
+
+	assert.Equal(t, companyIds[0], companyIds[1])
+	assert.Equal(t, companyIds[0], companyIds[2])
+	assert.NotEqual(t, companyIds[0], companyIds[3])
+
+	companies, err := store.GetCompanies(testApplication.UserId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(companies))
+	assert.Equal(t, "ACME", companies[0].Name)
+}
+
+func TestMemoryInsertCompanyDuplicate(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.InsertCompany(Company{UserId: 1, Name: "Acme Inc."})
+	assert.Nil(t, err)
+
+	_, err = store.InsertCompany(Company{UserId: 1, Name: "ACME"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+
+	_, err = store.InsertCompany(Company{UserId: 2, Name: "ACME"})
+	assert.Nil(t, err)
+}
+
+func TestMemoryUpdateAndDeleteCompany(t *testing.T) {
+	store := NewMemoryStore()
+
+	application := testApplication
+	application.CompanyName = "ACME"
+	id, _ := store.InsertApplication(application)
+	application, _ = store.GetApplication(id)
+
+	company, _ := store.GetCompany(*application.CompanyId)
+	company.Name = "ACME Corporation"
+	assert.Nil(t, store.UpdateCompany(company))
+
+	application, _ = store.GetApplication(id)
+	assert.Equal(t, "ACME Corporation", application.CompanyName)
+
+	assert.Nil(t, store.DeleteCompany(company.Id))
+
+	application, _ = store.GetApplication(id)
+	assert.Nil(t, application.CompanyId)
+	assert.Equal(t, "ACME Corporation", application.CompanyName)
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+)
+
+// checkContact enforces the same constraints as the contact table.
+func checkContact(contact Contact) error {
+	if len(contact.Name) > 255 || len(contact.Email) > 255 || len(contact.Phone) > 64 ||
+		len(contact.Role) > 255 || len(contact.LinkedInUrl) > 500 {
+		return ErrConstraintViolation
+	}
+
+	return nil
+}
+
+// sortContacts orders the contacts by name like the database does.
+func sortContacts(contacts []Contact) {
+	sort.Slice(contacts, func(i, j int) bool {
+		if contacts[i].Name != contacts[j].Name {
+			return strings.Compare(contacts[i].Name, contacts[j].Name) < 0
+		}
+
+		return contacts[i].Id < contacts[j].Id
+	})
+}
+
+func (s *MemoryStore) InsertContact(contact Contact) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkContact(contact); err != nil {
+		return -1, err
+	}
+
+	contact.Id = s.nextContactId
+	s.nextContactId++
+	s.contacts[contact.Id] = contact
+
+	return contact.Id, nil
+}
+
+func (s *MemoryStore) GetContacts(userId int) ([]Contact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var contacts []Contact
+	for _, contact := range s.contacts {
+		if contact.UserId == userId {
+			contacts = append(contacts, contact)
+		}
+	}
+
+	sortContacts(contacts)
+	return contacts, nil
+}
+
+func (s *MemoryStore) GetContact(id int) (Contact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contact, ok := s.contacts[id]
+	if !ok {
+		return Contact{}, ErrNotFound
+	}
+
+	return contact, nil
+}
+
+func (s *MemoryStore) UpdateContact(contact Contact) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.contacts[contact.Id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if err := checkContact(contact); err != nil {
+		return err
+	}
+
+	contact.UserId = old.UserId
+	s.contacts[contact.Id] = contact
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteContact(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.contacts[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.contacts, id)
+	for _, contactIds := range s.applicationContacts {
+		delete(contactIds, id)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) LinkContact(applicationId int, contactId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, applicationExists := s.applications[applicationId]
+	_, contactExists := s.contacts[contactId]
+	if !applicationExists || !contactExists {
+		return ErrConstraintViolation
+	}
+
+	if s.applicationContacts[applicationId] == nil {
+		s.applicationContacts[applicationId] = map[int]bool{}
+	}
+
+	s.applicationContacts[applicationId][contactId] = true
+	return nil
+}
+
+func (s *MemoryStore) UnlinkContact(applicationId int, contactId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.applicationContacts[applicationId][contactId] {
+		return ErrNotFound
+	}
+
+	delete(s.applicationContacts[applicationId], contactId)
+	return nil
+}
+
+func (s *MemoryStore) GetApplicationContacts(applicationId int) ([]Contact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var contacts []Contact
+	for contactId := range s.applicationContacts[applicationId] {
+		contacts = append(contacts, s.contacts[contactId])
+	}
+
+	sortContacts(contacts)
+	return contacts, nil
+}
+
+func (s *MemoryStore) GetContactApplications(contactId int) ([]Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var applications []Application
+	for applicationId, contactIds := range s.applicationContacts {
+		if contactIds[contactId] {
+			applications = append(applications, s.applications[applicationId])
+		}
+	}
+
+	sort.Slice(applications, func(i, j int) bool {
+		return applications[i].Id < applications[j].Id
+	})
+
+	return applications, nil
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryContacts(t *testing.T) {
+	store := NewMemoryStore()
+	firstId, _ := store.InsertApplication(testApplication)
+	secondId, _ := store.InsertApplication(testApplication)
+
+	recruiterId, err := store.InsertContact(Contact{UserId: 1, Name: "Rita Recruiter"})
+	assert.Nil(t, err)
+	managerId, err := store.InsertContact(Contact{UserId: 1, Name: "Hank Manager"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, store.LinkContact(firstId, recruiterId))
+	assert.Nil(t, store.LinkContact(firstId, recruiterId))
+	assert.Nil(t, store.LinkContact(secondId, recruiterId))
+	assert.Nil(t, store.LinkContact(firstId, managerId))
+
+	contacts, err := store.GetApplicationContacts(firstId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(contacts))
+	assert.Equal(t, "Hank Manager", contacts[0].Name)
+
+	applications, err := store.GetContactApplications(recruiterId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(applications))
+
+	assert.Nil(t, store.DeleteApplication(secondId))
+	applications, _ = store.GetContactApplications(recruiterId)
+	assert.Equal(t, 1, len(applications))
+
+	assert.Nil(t, store.DeleteContact(managerId))
+	contacts, _ = store.GetApplicationContacts(firstId)
+	assert.Equal(t, 1, len(contacts))
+
+	assert.Nil(t, store.UnlinkContact(firstId, recruiterId))
+	assert.ErrorIs(t, store.UnlinkContact(firstId, recruiterId), ErrNotFound)
+}
+
+func TestMemoryLinkContactConstraintViolation(t *testing.T) {
+	store := NewMemoryStore()
+	applicationId, _ := store.InsertApplication(testApplication)
+
+	assert.ErrorIs(t, store.LinkContact(applicationId, 42), ErrConstraintViolation)
+}
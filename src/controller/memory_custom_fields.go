@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+)
+
+// checkCustomField enforces the same constraints as the custom_field table.
+func (s *MemoryStore) checkCustomField(field CustomField) error {
+	if err := field.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrConstraintViolation, err)
+	}
+
+	for _, other := range s.customFields {
+		if other.Id != field.Id && other.UserId == field.UserId && other.Key == field.Key {
+			return ErrAlreadyExists
+		}
+	}
+
+	return nil
+}
+
+// storeCustomFields replaces the custom field values of the application like
+// the PostgreSQL implementation does. A nil map keeps the current values.
+func (s *MemoryStore) storeCustomFields(application Application) error {
+	if application.CustomFields == nil {
+		return nil
+	}
+
+	values := map[int]interface{}{}
+	for key, value := range application.CustomFields {
+		field, ok := s.customFieldByKey(application.UserId, key)
+		if !ok {
+			return fmt.Errorf("%w: custom field %s", ErrNotFound, key)
+		}
+
+		values[field.Id] = value
+	}
+
+	s.customFieldValues[application.Id] = values
+	return nil
+}
+
+func (s *MemoryStore) customFieldByKey(userId int, key string) (CustomField, bool) {
+	for _, field := range s.customFields {
+		if field.UserId == userId && field.Key == key {
+			return field, true
+		}
+	}
+
+	return CustomField{}, false
+}
+
+// customFieldsOf returns the custom field values of an application by key.
+func (s *MemoryStore) customFieldsOf(applicationId int) map[string]interface{} {
+	values := map[string]interface{}{}
+	for fieldId, value := range s.customFieldValues[applicationId] {
+		values[s.customFields[fieldId].Key] = value
+	}
+
+	return values
+}
+
+func (s *MemoryStore) InsertCustomField(field CustomField) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	field.Id = s.nextCustomFieldId
+	if err := s.checkCustomField(field); err != nil {
+		return -1, err
+	}
+
+	s.nextCustomFieldId++
+	s.customFields[field.Id] = field
+
+	return field.Id, nil
+}
+
+func (s *MemoryStore) GetCustomFields(userId int) ([]CustomField, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var fields []CustomField
+	for _, field := range s.customFields {
+		if field.UserId == userId {
+			fields = append(fields, field)
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+
+	return fields, nil
+}
+
+func (s *MemoryStore) GetCustomField(id int) (CustomField, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	field, ok := s.customFields[id]
+	if !ok {
+		return CustomField{}, ErrNotFound
+	}
+
+	return field, nil
+}
+
+func (s *MemoryStore) UpdateCustomField(field CustomField) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.customFields[field.Id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	old.Label = field.Label
+	old.Options = field.Options
+	if err := s.checkCustomField(old); err != nil {
+		return err
+	}
+
+	s.customFields[field.Id] = old
+	return nil
+}
+
+func (s *MemoryStore) DeleteCustomField(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.customFields[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.customFields, id)
+	for _, values := range s.customFieldValues {
+		delete(values, id)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) GetCustomFieldValues(applicationIds []int) (map[int]map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := map[int]map[string]interface{}{}
+	for _, applicationId := range applicationIds {
+		if len(s.customFieldValues[applicationId]) > 0 {
+			values[applicationId] = s.customFieldsOf(applicationId)
+		}
+	}
+
+	return values, nil
+}
+
+// applicationCustomFields returns the custom field values of an application,
+// which are needed to filter and sort the applications.
+func (s *MemoryStore) applicationCustomFields(applicationId int) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.customFieldsOf(applicationId)
+}
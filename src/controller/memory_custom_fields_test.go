@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCustomFields(t *testing.T) {
+	store := NewMemoryStore()
+
+	fieldId, err := store.InsertCustomField(CustomField{UserId: 1, Key: "teamSize", Type: FieldNumber})
+	assert.Nil(t, err)
+
+	_, err = store.InsertCustomField(CustomField{UserId: 1, Key: "teamSize", Type: FieldText})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+
+	_, err = store.InsertCustomField(CustomField{UserId: 1, Key: "stack", Type: FieldEnum})
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+
+	application := Application{UserId: 1, WorkTypeId: 1, StatusId: 1, CustomFields: map[string]interface{}{"teamSize": 8.0}}
+	applicationId, err := store.InsertApplication(application)
+	assert.Nil(t, err)
+
+	application.CustomFields = map[string]interface{}{"unknown": "value"}
+	_, err = store.InsertApplication(application)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	values, err := store.GetCustomFieldValues([]int{applicationId})
+	assert.Nil(t, err)
+	assert.Equal(t, 8.0, values[applicationId]["teamSize"])
+
+	// Updates without custom fields keep the values.
+	stored, _ := store.GetApplication(applicationId)
+	assert.Nil(t, stored.CustomFields)
+	assert.Nil(t, store.UpdateApplication(stored))
+
+	values, _ = store.GetCustomFieldValues([]int{applicationId})
+	assert.Equal(t, 8.0, values[applicationId]["teamSize"])
+
+	assert.Nil(t, store.DeleteCustomField(fieldId))
+	values, _ = store.GetCustomFieldValues([]int{applicationId})
+	assert.Empty(t, values)
+}
+
+func TestMemoryQueryApplicationsByCustomFields(t *testing.T) {
+	store := NewMemoryStore()
+	store.InsertCustomField(CustomField{UserId: 1, Key: "teamSize", Type: FieldNumber})
+	store.InsertCustomField(CustomField{UserId: 1, Key: "visa", Type: FieldBoolean})
+
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, CustomFields: map[string]interface{}{"teamSize": 12.0, "visa": true}})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, CustomFields: map[string]interface{}{"teamSize": 5.0, "visa": true}})
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1, CustomFields: map[string]interface{}{"teamSize": 30.0}})
+
+	page, err := store.QueryApplications(1, ApplicationQuery{CustomFields: []CustomFieldFilter{{Key: "visa", Value: true}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, page.Total)
+
+	sort, err := ParseSort("-customFields.teamSize")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := ApplicationQuery{Limit: 3, Sort: sort}
+
+	var ids []int
+	for {
+		page, err := store.QueryApplications(1, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, application := range page.Applications {
+			ids = append(ids, application.Id)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		query.Cursor = page.NextCursor
+	}
+
+	assert.Equal(t, []int{4, 1, 3, 2}, ids)
+}
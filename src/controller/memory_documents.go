@@ -0,0 +1,226 @@
+package controller
+
+import "sort"
+
+func checkDocument(document Document) error {
+	if len(document.Name) > 255 {
+		return ErrConstraintViolation
+	}
+
+	if document.Kind != DocumentCV && document.Kind != DocumentCoverLetter && document.Kind != DocumentOther {
+		return ErrConstraintViolation
+	}
+
+	return nil
+}
+
+// insertDocumentVersion adds the next version to the document. The caller
+// has to hold the lock.
+func (s *MemoryStore) insertDocumentVersion(version DocumentVersion) (int, error) {
+	if _, ok := s.documents[version.DocumentId]; !ok {
+		return -1, ErrNotFound
+	}
+
+	if len(version.FileName) > 255 || len(version.ContentType) > 255 || len(version.BlobKey) > 500 || version.Size < 0 {
+		return -1, ErrConstraintViolation
+	}
+
+	version.Version = 1
+	for _, other := range s.documentVersions {
+		if other.BlobKey == version.BlobKey {
+			return -1, ErrConstraintViolation
+		}
+
+		if other.DocumentId == version.DocumentId && other.Version >= version.Version {
+			version.Version = other.Version + 1
+		}
+	}
+
+	version.Id = s.nextDocumentVersionId
+	version.UploadedAt = s.now().UTC()
+	s.nextDocumentVersionId++
+	s.documentVersions[version.Id] = version
+
+	return version.Id, nil
+}
+
+// latestVersion returns the highest version number of the document.
+func (s *MemoryStore) latestVersion(documentId int) int {
+	latest := 0
+	for _, version := range s.documentVersions {
+		if version.DocumentId == documentId && version.Version > latest {
+			latest = version.Version
+		}
+	}
+
+	return latest
+}
+
+func (s *MemoryStore) InsertDocument(document Document, version DocumentVersion) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkDocument(document); err != nil {
+		return -1, err
+	}
+
+	document.Id = s.nextDocumentId
+	document.CreatedAt = s.now().UTC()
+	s.documents[document.Id] = document
+
+	version.DocumentId = document.Id
+	if _, err := s.insertDocumentVersion(version); err != nil {
+		delete(s.documents, document.Id)
+		return -1, err
+	}
+
+	s.nextDocumentId++
+	return document.Id, nil
+}
+
+func (s *MemoryStore) InsertDocumentVersion(version DocumentVersion) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertDocumentVersion(version)
+}
+
+func (s *MemoryStore) GetDocuments(userId int) ([]Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var documents []Document
+	for _, document := range s.documents {
+		if document.UserId == userId {
+			document.LatestVersion = s.latestVersion(document.Id)
+			documents = append(documents, document)
+		}
+	}
+
+	sort.Slice(documents, func(i, j int) bool {
+		if documents[i].Name != documents[j].Name {
+			return documents[i].Name < documents[j].Name
+		}
+
+		return documents[i].Id < documents[j].Id
+	})
+
+	return documents, nil
+}
+
+func (s *MemoryStore) GetDocument(id int) (Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	document, ok := s.documents[id]
+	if !ok {
+		return Document{}, ErrNotFound
+	}
+
+	document.LatestVersion = s.latestVersion(id)
+	return document, nil
+}
+
+func (s *MemoryStore) DeleteDocument(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.documents[id]; !ok {
+		return ErrNotFound
+	}
+
+	for _, application := range s.applications {
+		if application.DocumentVersionId == nil {
+			continue
+		}
+
+		if version, ok := s.documentVersions[*application.DocumentVersionId]; ok && version.DocumentId == id {
+			return ErrInUse
+		}
+	}
+
+	delete(s.documents, id)
+	for versionId, version := range s.documentVersions {
+		if version.DocumentId == id {
+			delete(s.documentVersions, versionId)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) GetDocumentVersions(documentId int) ([]DocumentVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var versions []DocumentVersion
+	for _, version := range s.documentVersions {
+		if version.DocumentId == documentId {
+			versions = append(versions, version)
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	return versions, nil
+}
+
+func (s *MemoryStore) GetDocumentVersion(id int) (DocumentVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version, ok := s.documentVersions[id]
+	if !ok {
+		return DocumentVersion{}, ErrNotFound
+	}
+
+	return version, nil
+}
+
+func (s *MemoryStore) GetDocumentVersionsByIds(ids []int) (map[int]DocumentVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := map[int]DocumentVersion{}
+	for _, id := range ids {
+		if version, ok := s.documentVersions[id]; ok {
+			versions[id] = version
+		}
+	}
+
+	return versions, nil
+}
+
+func (s *MemoryStore) GetVersionApplications(versionId int) ([]Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var applications []Application
+	for _, application := range s.applications {
+		if application.DocumentVersionId != nil && *application.DocumentVersionId == versionId {
+			applications = append(applications, application)
+		}
+	}
+
+	sort.Slice(applications, func(i, j int) bool {
+		return applications[i].Id < applications[j].Id
+	})
+
+	return applications, nil
+}
+
+func (s *MemoryStore) GetDocumentUsage(userId int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var usage int64
+	for _, version := range s.documentVersions {
+		if document, ok := s.documents[version.DocumentId]; ok && document.UserId == userId {
+			usage += version.Size
+		}
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryDocumentVersions(t *testing.T) {
+	store := NewMemoryStore()
+
+	documentId, err := store.InsertDocument(Document{UserId: 1, Name: "CV", Kind: DocumentCV}, DocumentVersion{FileName: "cv.pdf", Size: 10, BlobKey: "v1"})
+	assert.Nil(t, err)
+
+	secondId, err := store.InsertDocumentVersion(DocumentVersion{DocumentId: documentId, FileName: "cv.pdf", Size: 20, BlobKey: "v2"})
+	assert.Nil(t, err)
+
+	_, err = store.InsertDocumentVersion(DocumentVersion{DocumentId: 42, BlobKey: "v3"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	document, _ := store.GetDocument(documentId)
+	assert.Equal(t, 2, document.LatestVersion)
+
+	second, _ := store.GetDocumentVersion(secondId)
+	assert.Equal(t, 2, second.Version)
+
+	usage, _ := store.GetDocumentUsage(1)
+	assert.Equal(t, int64(30), usage)
+
+	application := testApplication
+	application.DocumentVersionId = &secondId
+	applicationId, err := store.InsertApplication(application)
+	assert.Nil(t, err)
+
+	applications, _ := store.GetVersionApplications(secondId)
+	assert.Equal(t, 1, len(applications))
+
+	assert.ErrorIs(t, store.DeleteDocument(documentId), ErrInUse)
+
+	store.DeleteApplication(applicationId)
+	assert.Nil(t, store.DeleteDocument(documentId))
+
+	versions, _ := store.GetDocumentVersions(documentId)
+	assert.Empty(t, versions)
+}
+
+func TestMemoryInsertApplicationUnknownDocumentVersion(t *testing.T) {
+	store := NewMemoryStore()
+
+	versionId := 42
+	application := testApplication
+	application.DocumentVersionId = &versionId
+	_, err := store.InsertApplication(application)
+
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+}
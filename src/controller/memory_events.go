@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// notifyListeners calls the listeners of the event stream. The store must be
+// locked.
+func (s *MemoryStore) notifyListeners(userId int) {
+	for _, listen := range s.eventListeners {
+		listen(userId)
+	}
+}
+
+// GetEventsAfter orders the events by their positions, which equal their ids,
+// because the store commits the events in the order they are written.
+func (s *MemoryStore) GetEventsAfter(userId int, afterPosition int64, limit int) ([]OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := []OutboxEvent{}
+	for _, event := range s.outboxEvents {
+		if event.UserId == userId && event.Position > afterPosition {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Position < events[j].Position
+	})
+
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+func (s *MemoryStore) GetLatestEventPosition() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nextOutboxEventId - 1, nil
+}
+
+func (s *MemoryStore) PruneEvents(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := map[int64]bool{}
+	for _, delivery := range s.deliveries {
+		if delivery.Status == DeliveryPending {
+			pending[delivery.EventId] = true
+		}
+	}
+
+	pruned := 0
+	for id, event := range s.outboxEvents {
+		if !event.CreatedAt.Before(before) || event.DispatchedAt == nil || pending[id] {
+			continue
+		}
+
+		for deliveryId, delivery := range s.deliveries {
+			if delivery.EventId == id {
+				delete(s.deliveries, deliveryId)
+			}
+		}
+
+		delete(s.outboxEvents, id)
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+func (s *MemoryStore) ListenEvents(ctx context.Context, listen ListenFunc) error {
+	s.mu.Lock()
+	id := s.nextEventListenerId
+	s.eventListeners[id] = listen
+	s.nextEventListenerId++
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	s.mu.Lock()
+	delete(s.eventListeners, id)
+	s.mu.Unlock()
+
+	return nil
+}
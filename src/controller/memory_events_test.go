@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryEventLog(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listened := make(chan int, 10)
+	done := make(chan error)
+	go func() {
+		done <- store.ListenEvents(ctx, func(userId int) { listened <- userId })
+	}()
+
+	// The listener is registered asynchronously.
+	assert.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return len(store.eventListeners) == 1
+	}, time.Second, time.Millisecond)
+
+	id, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	store.InsertApplication(Application{UserId: 2, WorkTypeId: 1, StatusId: 2})
+	application, _ := store.GetApplication(id)
+	application.JobTitle = "Go Developer"
+	store.UpdateApplication(application)
+
+	assert.Equal(t, 1, <-listened)
+	assert.Equal(t, 2, <-listened)
+	assert.Equal(t, 1, <-listened)
+
+	cancel()
+	assert.Nil(t, <-done)
+
+	events, err := store.GetEventsAfter(1, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, int64(1), events[0].Id)
+	assert.Equal(t, int64(3), events[1].Id)
+	assert.Equal(t, events[1].Id, events[1].Position)
+
+	events, _ = store.GetEventsAfter(1, 1, 10)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, EventApplicationUpdated, events[0].Event)
+
+	events, _ = store.GetEventsAfter(1, 0, 1)
+	assert.Equal(t, 1, len(events))
+
+	latest, err := store.GetLatestEventPosition()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), latest)
+
+	// Only dispatched events without pending deliveries are pruned.
+	pruned, err := store.PruneEvents(now.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, pruned)
+
+	store.InsertWebhook(Webhook{UserId: 2, Url: "https://example.com/hook", Secret: "secret", Events: []string{EventApplicationCreated}, Active: true})
+	store.DispatchEvents(now, 10)
+
+	pruned, _ = store.PruneEvents(now)
+	assert.Equal(t, 0, pruned)
+
+	pruned, _ = store.PruneEvents(now.Add(time.Hour))
+	assert.Equal(t, 2, pruned)
+	assert.Equal(t, 1, len(store.GetOutboxEvents()))
+	assert.Equal(t, 2, store.GetOutboxEvents()[0].UserId)
+}
@@ -0,0 +1,105 @@
+package controller
+
+import "sort"
+
+func (s *MemoryStore) InsertInterview(interview Interview) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.applications[interview.ApplicationId]; !ok {
+		return -1, ErrConstraintViolation
+	}
+
+	if err := interview.Validate(); err != nil {
+		return -1, ErrConstraintViolation
+	}
+
+	interview.Id = s.nextInterviewId
+	s.nextInterviewId++
+	s.interviews[interview.Id] = interview
+
+	return interview.Id, nil
+}
+
+func sortInterviews(interviews []Interview) {
+	sort.Slice(interviews, func(i, j int) bool {
+		if !interviews[i].ScheduledAt.Equal(interviews[j].ScheduledAt) {
+			return interviews[i].ScheduledAt.Before(interviews[j].ScheduledAt)
+		}
+
+		return interviews[i].Id < interviews[j].Id
+	})
+}
+
+func (s *MemoryStore) GetInterviews(applicationId int) ([]Interview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var interviews []Interview
+	for _, interview := range s.interviews {
+		if interview.ApplicationId == applicationId {
+			interviews = append(interviews, interview)
+		}
+	}
+
+	sortInterviews(interviews)
+	return interviews, nil
+}
+
+func (s *MemoryStore) GetUserInterviews(userId int) ([]Interview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var interviews []Interview
+	for _, interview := range s.interviews {
+		if s.applications[interview.ApplicationId].UserId == userId {
+			interviews = append(interviews, interview)
+		}
+	}
+
+	sortInterviews(interviews)
+	return interviews, nil
+}
+
+func (s *MemoryStore) GetInterview(id int) (Interview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	interview, ok := s.interviews[id]
+	if !ok {
+		return Interview{}, ErrNotFound
+	}
+
+	return interview, nil
+}
+
+func (s *MemoryStore) UpdateInterview(interview Interview) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.interviews[interview.Id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if err := interview.Validate(); err != nil {
+		return ErrConstraintViolation
+	}
+
+	interview.ApplicationId = old.ApplicationId
+	s.interviews[interview.Id] = interview
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteInterview(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.interviews[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.interviews, id)
+	return nil
+}
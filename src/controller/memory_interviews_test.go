@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryInterviews(t *testing.T) {
+	store := NewMemoryStore()
+
+	applicationId, err := store.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	later := testInterview
+	later.ApplicationId = applicationId
+	later.ScheduledAt = testInterview.ScheduledAt.Add(24 * time.Hour)
+	laterId, err := store.InsertInterview(later)
+	assert.Nil(t, err)
+
+	earlier := testInterview
+	earlier.ApplicationId = applicationId
+	earlierId, err := store.InsertInterview(earlier)
+	assert.Nil(t, err)
+
+	interviews, err := store.GetInterviews(applicationId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(interviews))
+	assert.Equal(t, earlierId, interviews[0].Id)
+	assert.Equal(t, laterId, interviews[1].Id)
+
+	interviews, err = store.GetUserInterviews(testApplication.UserId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(interviews))
+	assert.Equal(t, earlierId, interviews[0].Id)
+
+	interviews, _ = store.GetUserInterviews(testApplication.UserId + 1)
+	assert.Empty(t, interviews)
+
+	store.DeleteApplication(applicationId)
+
+	_, err = store.GetInterview(earlierId)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryInsertInterviewConstraintViolation(t *testing.T) {
+	store := NewMemoryStore()
+
+	interview := testInterview
+	interview.ApplicationId = 42
+	_, err := store.InsertInterview(interview)
+
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+}
+
+func TestMemoryUpdateInterview(t *testing.T) {
+	store := NewMemoryStore()
+	applicationId, _ := store.InsertApplication(testApplication)
+
+	interview := testInterview
+	interview.ApplicationId = applicationId
+	interview.Id, _ = store.InsertInterview(interview)
+
+	interview.Notes = "went well"
+	interview.ApplicationId = 42
+	assert.Nil(t, store.UpdateInterview(interview))
+
+	stored, _ := store.GetInterview(interview.Id)
+	assert.Equal(t, "went well", stored.Notes)
+	assert.Equal(t, applicationId, stored.ApplicationId)
+
+	assert.Nil(t, store.DeleteInterview(interview.Id))
+	assert.ErrorIs(t, store.UpdateInterview(interview), ErrNotFound)
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"sort"
+	"time"
+)
+
+func (s *MemoryStore) GetNotificationPreferences(userId int) (NotificationPreferences, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	preferences, ok := s.notificationPreferences[userId]
+	if !ok {
+		return NotificationPreferences{}, ErrNotFound
+	}
+
+	preferences.DisabledEvents = append([]string{}, preferences.DisabledEvents...)
+	return preferences, nil
+}
+
+func (s *MemoryStore) SaveNotificationPreferences(preferences NotificationPreferences) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(preferences.Email) > 255 {
+		return ErrConstraintViolation
+	}
+
+	stored, ok := s.notificationPreferences[preferences.UserId]
+	if !ok {
+		stored = NotificationPreferences{UserId: preferences.UserId, DigestSentAt: s.now().UTC()}
+	}
+
+	stored.Email = preferences.Email
+	stored.DisabledEvents = append([]string{}, preferences.DisabledEvents...)
+	s.notificationPreferences[preferences.UserId] = stored
+
+	return nil
+}
+
+// SendDueDigests mirrors the leases of the PostgreSQL implementation like
+// FireDueReminders does.
+func (s *MemoryStore) SendDueDigests(now time.Time, period time.Duration, limit int, send DigestFunc) (int, error) {
+	s.mu.Lock()
+	var due []NotificationPreferences
+	for _, preferences := range s.notificationPreferences {
+		if preferences.Email != "" && !preferences.DigestSentAt.After(now.Add(-period)) &&
+			(preferences.DigestNextAttemptAt == nil || !preferences.DigestNextAttemptAt.After(now)) &&
+			!s.digestLeases[preferences.UserId].After(now) {
+			due = append(due, preferences)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].DigestSentAt.Equal(due[j].DigestSentAt) {
+			return due[i].DigestSentAt.Before(due[j].DigestSentAt)
+		}
+
+		return due[i].UserId < due[j].UserId
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, preferences := range due {
+		s.digestLeases[preferences.UserId] = now.Add(schedulingLease)
+	}
+	s.mu.Unlock()
+
+	sent := 0
+	for _, preferences := range due {
+		err := send(preferences)
+
+		s.mu.Lock()
+		delete(s.digestLeases, preferences.UserId)
+		if stored, ok := s.notificationPreferences[preferences.UserId]; ok {
+			if err == nil {
+				stored.DigestSentAt = now
+				stored.DigestAttempts = 0
+				stored.DigestNextAttemptAt = nil
+				sent++
+			} else {
+				nextAttemptAt := now.Add(retryBackoff(stored.DigestAttempts + 1))
+				stored.DigestAttempts++
+				stored.DigestNextAttemptAt = &nextAttemptAt
+			}
+
+			s.notificationPreferences[preferences.UserId] = stored
+		}
+		s.mu.Unlock()
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryNotificationPreferences(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.GetNotificationPreferences(1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Nil(t, store.SaveNotificationPreferences(NotificationPreferences{UserId: 1, Email: "jane@example.com", DisabledEvents: []string{"stale"}}))
+
+	preferences, err := store.GetNotificationPreferences(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "jane@example.com", preferences.Email)
+	assert.False(t, preferences.Enabled("stale"))
+	assert.True(t, preferences.Enabled("reminder"))
+	assert.WithinDuration(t, time.Now(), preferences.DigestSentAt, time.Minute)
+}
+
+func TestMemorySendDueDigests(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now.AddDate(0, 0, -7) }
+
+	store.SaveNotificationPreferences(NotificationPreferences{UserId: 1, Email: "jane@example.com"})
+	store.SaveNotificationPreferences(NotificationPreferences{UserId: 2})
+	store.now = func() time.Time { return now }
+	store.SaveNotificationPreferences(NotificationPreferences{UserId: 3, Email: "john@example.com"})
+
+	var users []int
+	sent, err := store.SendDueDigests(now, 7*24*time.Hour, 10, func(preferences NotificationPreferences) error {
+		users = append(users, preferences.UserId)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []int{1}, users)
+
+	preferences, _ := store.GetNotificationPreferences(1)
+	assert.Equal(t, now, preferences.DigestSentAt)
+
+	sent, _ = store.SendDueDigests(now, 7*24*time.Hour, 10, func(preferences NotificationPreferences) error { return nil })
+	assert.Equal(t, 0, sent)
+}
+
+func TestMemorySendDueDigestsSkipsFailing(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now.AddDate(0, 0, -8) }
+	store.SaveNotificationPreferences(NotificationPreferences{UserId: 1, Email: "jane@example.com"})
+	store.now = func() time.Time { return now.AddDate(0, 0, -7) }
+	store.SaveNotificationPreferences(NotificationPreferences{UserId: 2, Email: "john@example.com"})
+
+	var users []int
+	send := func(preferences NotificationPreferences) error {
+		if preferences.UserId == 1 {
+			return errors.New("unavailable")
+		}
+
+		users = append(users, preferences.UserId)
+		return nil
+	}
+
+	// The failing digest is due first, but does not hold up the other one.
+	sent, err := store.SendDueDigests(now, 7*24*time.Hour, 1, send)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+
+	sent, err = store.SendDueDigests(now, 7*24*time.Hour, 1, send)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []int{2}, users)
+
+	preferences, _ := store.GetNotificationPreferences(1)
+	assert.Equal(t, 1, preferences.DigestAttempts)
+	assert.Equal(t, now.Add(time.Minute), *preferences.DigestNextAttemptAt)
+
+	// The digest is retried after the backoff and the attempts are reset.
+	users = nil
+	sent, _ = store.SendDueDigests(now.Add(time.Minute), 7*24*time.Hour, 1, func(preferences NotificationPreferences) error {
+		users = append(users, preferences.UserId)
+		return nil
+	})
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []int{1}, users)
+
+	preferences, _ = store.GetNotificationPreferences(1)
+	assert.Equal(t, 0, preferences.DigestAttempts)
+	assert.Nil(t, preferences.DigestNextAttemptAt)
+}
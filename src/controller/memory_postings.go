@@ -0,0 +1,73 @@
+package controller
+
+import "sort"
+
+func (s *MemoryStore) InsertPostingSnapshot(snapshot PostingSnapshot) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(snapshot.Url) > 2000 || len(snapshot.ContentType) > 255 || len(snapshot.BlobKey) > 500 || snapshot.Size < 0 {
+		return -1, ErrConstraintViolation
+	}
+
+	for _, other := range s.postingSnapshots {
+		if other.BlobKey == snapshot.BlobKey {
+			return -1, ErrConstraintViolation
+		}
+	}
+
+	snapshot.Id = s.nextPostingSnapshotId
+	snapshot.FetchedAt = s.now().UTC()
+	s.nextPostingSnapshotId++
+	s.postingSnapshots[snapshot.Id] = snapshot
+
+	return snapshot.Id, nil
+}
+
+func (s *MemoryStore) GetPostingSnapshots(userId int, url string) ([]PostingSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var snapshots []PostingSnapshot
+	for _, snapshot := range s.postingSnapshots {
+		if snapshot.UserId == userId && snapshot.Url == url {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].FetchedAt.Equal(snapshots[j].FetchedAt) {
+			return snapshots[i].FetchedAt.After(snapshots[j].FetchedAt)
+		}
+
+		return snapshots[i].Id > snapshots[j].Id
+	})
+
+	return snapshots, nil
+}
+
+func (s *MemoryStore) GetPostingSnapshot(id int) (PostingSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.postingSnapshots[id]
+	if !ok {
+		return PostingSnapshot{}, ErrNotFound
+	}
+
+	return snapshot, nil
+}
+
+func (s *MemoryStore) GetPostingSnapshotUsage(userId int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var usage int64
+	for _, snapshot := range s.postingSnapshots {
+		if snapshot.UserId == userId {
+			usage += snapshot.Size
+		}
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryPostingSnapshots(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	url := "https://example.com/jobs/1"
+	first, err := store.InsertPostingSnapshot(PostingSnapshot{UserId: 1, Url: url, ContentType: "text/html", Size: 100, BlobKey: "a"})
+	assert.Nil(t, err)
+
+	now = now.Add(time.Hour)
+	latest, err := store.InsertPostingSnapshot(PostingSnapshot{UserId: 1, Url: url, ContentType: "text/html", Size: 50, BlobKey: "b"})
+	assert.Nil(t, err)
+	store.InsertPostingSnapshot(PostingSnapshot{UserId: 2, Url: url, ContentType: "text/html", Size: 10, BlobKey: "c"})
+
+	_, err = store.InsertPostingSnapshot(PostingSnapshot{UserId: 1, Url: url, BlobKey: "a"})
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+
+	snapshots, err := store.GetPostingSnapshots(1, url)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(snapshots))
+	assert.Equal(t, latest, snapshots[0].Id)
+	assert.Equal(t, first, snapshots[1].Id)
+
+	snapshot, err := store.GetPostingSnapshot(first)
+	assert.Nil(t, err)
+	assert.Equal(t, "a", snapshot.BlobKey)
+
+	_, err = store.GetPostingSnapshot(100)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	usage, err := store.GetPostingSnapshotUsage(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(150), usage)
+}
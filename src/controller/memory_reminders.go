@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"sort"
+	"time"
+)
+
+func (s *MemoryStore) InsertReminder(reminder Reminder) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.applications[reminder.ApplicationId]; !ok || len(reminder.Message) > 500 {
+		return -1, ErrConstraintViolation
+	}
+
+	reminder.Id = s.nextReminderId
+	reminder.RemindAt = reminder.RemindAt.UTC()
+	reminder.CreatedAt = s.now().UTC()
+	reminder.FiredAt = nil
+	reminder.Attempts = 0
+	reminder.NextAttemptAt = nil
+	s.nextReminderId++
+	s.reminders[reminder.Id] = reminder
+
+	return reminder.Id, nil
+}
+
+func sortReminders(reminders []Reminder) {
+	sort.Slice(reminders, func(i, j int) bool {
+		if !reminders[i].RemindAt.Equal(reminders[j].RemindAt) {
+			return reminders[i].RemindAt.Before(reminders[j].RemindAt)
+		}
+
+		return reminders[i].Id < reminders[j].Id
+	})
+}
+
+func (s *MemoryStore) GetReminders(applicationId int) ([]Reminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reminders []Reminder
+	for _, reminder := range s.reminders {
+		if reminder.ApplicationId == applicationId {
+			reminders = append(reminders, reminder)
+		}
+	}
+
+	sortReminders(reminders)
+	return reminders, nil
+}
+
+func (s *MemoryStore) GetUserReminders(userId int) ([]Reminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reminders []Reminder
+	for _, reminder := range s.reminders {
+		if s.applications[reminder.ApplicationId].UserId == userId {
+			reminders = append(reminders, reminder)
+		}
+	}
+
+	sortReminders(reminders)
+	return reminders, nil
+}
+
+func (s *MemoryStore) GetReminder(id int) (Reminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reminder, ok := s.reminders[id]
+	if !ok {
+		return Reminder{}, ErrNotFound
+	}
+
+	return reminder, nil
+}
+
+func (s *MemoryStore) DeleteReminder(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.reminders[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.reminders, id)
+	return nil
+}
+
+// FireDueReminders mirrors the leases of the PostgreSQL implementation.
+// Reminders, which are being fired by another call, are skipped. The store
+// is not locked while the reminders are fired, so fire may use the store.
+func (s *MemoryStore) FireDueReminders(now time.Time, limit int, fire FireFunc) (int, error) {
+	s.mu.Lock()
+	var due []Reminder
+	for _, reminder := range s.reminders {
+		if reminder.FiredAt == nil && !reminder.dueAt().After(now) && !s.reminderLeases[reminder.Id].After(now) {
+			due = append(due, reminder)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].dueAt().Equal(due[j].dueAt()) {
+			return due[i].dueAt().Before(due[j].dueAt())
+		}
+
+		return due[i].Id < due[j].Id
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, reminder := range due {
+		s.reminderLeases[reminder.Id] = now.Add(schedulingLease)
+	}
+	s.mu.Unlock()
+
+	fired := 0
+	for _, reminder := range due {
+		err := fire(reminder)
+
+		s.mu.Lock()
+		delete(s.reminderLeases, reminder.Id)
+		if stored, ok := s.reminders[reminder.Id]; ok {
+			if err == nil {
+				firedAt := now
+				stored.FiredAt = &firedAt
+				fired++
+			} else {
+				nextAttemptAt := now.Add(retryBackoff(stored.Attempts + 1))
+				stored.Attempts++
+				stored.NextAttemptAt = &nextAttemptAt
+			}
+
+			s.reminders[reminder.Id] = stored
+		}
+		s.mu.Unlock()
+	}
+
+	return fired, nil
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryReminders(t *testing.T) {
+	store := NewMemoryStore()
+	applicationId, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+
+	_, err := store.InsertReminder(Reminder{ApplicationId: 42, RemindAt: time.Now()})
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+
+	id, err := store.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: time.Now(), Message: "Follow up"})
+	assert.Nil(t, err)
+
+	reminders, err := store.GetReminders(applicationId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reminders))
+	assert.Nil(t, reminders[0].FiredAt)
+
+	reminders, err = store.GetUserReminders(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reminders))
+
+	reminders, _ = store.GetUserReminders(2)
+	assert.Empty(t, reminders)
+
+	assert.Nil(t, store.DeleteReminder(id))
+	assert.ErrorIs(t, store.DeleteReminder(id), ErrNotFound)
+}
+
+func TestMemoryFireDueReminders(t *testing.T) {
+	store := NewMemoryStore()
+	applicationId, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	dueId, _ := store.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now.Add(-time.Hour)})
+	failingId, _ := store.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now})
+	store.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now.Add(time.Hour)})
+
+	var firedIds []int
+	fired, err := store.FireDueReminders(now, 10, func(reminder Reminder) error {
+		if reminder.Id == failingId {
+			return errors.New("not delivered")
+		}
+
+		firedIds = append(firedIds, reminder.Id)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, []int{dueId}, firedIds)
+
+	reminder, _ := store.GetReminder(dueId)
+	assert.Equal(t, now, *reminder.FiredAt)
+
+	failing, _ := store.GetReminder(failingId)
+	assert.Nil(t, failing.FiredAt)
+	assert.Equal(t, 1, failing.Attempts)
+	assert.Equal(t, now.Add(time.Minute), *failing.NextAttemptAt)
+
+	// The failed reminder is retried after the backoff, the fired one is not.
+	firedIds = nil
+	fire := func(reminder Reminder) error {
+		firedIds = append(firedIds, reminder.Id)
+		return nil
+	}
+
+	fired, _ = store.FireDueReminders(now, 10, fire)
+	assert.Equal(t, 0, fired)
+
+	fired, _ = store.FireDueReminders(now.Add(time.Minute), 10, fire)
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, []int{failingId}, firedIds)
+}
+
+func TestMemoryFireDueRemindersSkipsFailing(t *testing.T) {
+	store := NewMemoryStore()
+	applicationId, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	failingId, _ := store.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now.Add(-time.Hour)})
+	laterId, _ := store.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now})
+
+	var firedIds []int
+	fire := func(reminder Reminder) error {
+		if reminder.Id == failingId {
+			return errors.New("not delivered")
+		}
+
+		firedIds = append(firedIds, reminder.Id)
+		return nil
+	}
+
+	// The failing reminder is due first, but does not hold up the later one.
+	fired, err := store.FireDueReminders(now, 1, fire)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, fired)
+
+	fired, err = store.FireDueReminders(now, 1, fire)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, []int{laterId}, firedIds)
+
+	fired, _ = store.FireDueReminders(now.Add(time.Minute), 1, fire)
+	assert.Equal(t, 0, fired)
+
+	failing, _ := store.GetReminder(failingId)
+	assert.Nil(t, failing.FiredAt)
+	assert.Equal(t, 2, failing.Attempts)
+	assert.Equal(t, now.Add(3*time.Minute), *failing.NextAttemptAt)
+}
+
+func TestMemoryFireDueRemindersOnce(t *testing.T) {
+	store := NewMemoryStore()
+	applicationId, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	for i := 0; i < 50; i++ {
+		store.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: time.Now()})
+	}
+
+	var mu sync.Mutex
+	counts := map[int]int{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.FireDueReminders(time.Now(), 5, func(reminder Reminder) error {
+				mu.Lock()
+				counts[reminder.Id]++
+				mu.Unlock()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	for id, count := range counts {
+		assert.Equal(t, 1, count, "reminder %d", id)
+	}
+}
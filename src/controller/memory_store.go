@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrConstraintViolation is returned by the in-memory store, if an operation
+// would violate a constraint the database enforces (foreign keys, column
+// sizes).
+var ErrConstraintViolation = errors.New("constraint violation")
+
+// MemoryStore is an in-memory implementation of the repositories. It mirrors
+// the behaviour of the PostgreSQL implementation and is meant for unit tests
+// and local demos. All data is lost, when the process terminates.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	workTypes    []WorkType
+	statuses     []ApplicationStatus
+	nextStatusId int
+	transitions  map[int][]StatusTransition
+
+	applications      map[int]Application
+	nextApplicationId int
+
+	statusHistory      []StatusChange
+	nextStatusChangeId int
+
+	interviews      map[int]Interview
+	nextInterviewId int
+
+	contacts            map[int]Contact
+	nextContactId       int
+	applicationContacts map[int]map[int]bool
+
+	companies     map[int]Company
+	nextCompanyId int
+
+	attachments      map[int]Attachment
+	nextAttachmentId int
+
+	documents             map[int]Document
+	nextDocumentId        int
+	documentVersions      map[int]DocumentVersion
+	nextDocumentVersionId int
+
+	tags            map[int]Tag
+	nextTagId       int
+	applicationTags map[int]map[int]bool
+
+	customFields      map[int]CustomField
+	nextCustomFieldId int
+	customFieldValues map[int]map[int]interface{}
+
+	reminders      map[int]Reminder
+	nextReminderId int
+	reminderLeases map[int]time.Time
+
+	notificationPreferences map[int]NotificationPreferences
+	digestLeases            map[int]time.Time
+
+	webhooks          map[int]Webhook
+	nextWebhookId     int
+	outboxEvents      map[int64]OutboxEvent
+	nextOutboxEventId int64
+	deliveries        map[int64]WebhookDelivery
+	nextDeliveryId    int64
+	deliveryLeases    map[int64]time.Time
+
+	eventListeners      map[int]ListenFunc
+	nextEventListenerId int
+
+	calendarTokens map[int]string
+
+	postingSnapshots      map[int]PostingSnapshot
+	nextPostingSnapshotId int
+
+	auditLog          []AuditRecord
+	nextAuditRecordId int64
+
+	// now returns the current time. Tests replace it to control the clock.
+	now func() time.Time
+}
+
+// NewMemoryStore creates an empty store, which contains the same default work
+// types and statuses as the database schema.
+func NewMemoryStore() *MemoryStore {
+	// Pending applications without a reply for 30 days are stale by default.
+	pendingStaleDays := 30
+
+	return &MemoryStore{
+		workTypes: []WorkType{
+			{Id: 1, Name: "Remote"},
+			{Id: 2, Name: "OnSite"},
+			{Id: 3, Name: "Hybrid"},
+		},
+		statuses: []ApplicationStatus{
+			{Id: 1, Name: "Accepted", Position: 1, Terminal: true, Accepted: true},
+			{Id: 2, Name: "Pending", Position: 2, StaleAfterDays: &pendingStaleDays},
+			{Id: 3, Name: "Declined", Position: 3, Terminal: true},
+		},
+		nextStatusId:            4,
+		transitions:             map[int][]StatusTransition{},
+		applications:            map[int]Application{},
+		nextApplicationId:       1,
+		nextStatusChangeId:      1,
+		interviews:              map[int]Interview{},
+		nextInterviewId:         1,
+		contacts:                map[int]Contact{},
+		nextContactId:           1,
+		applicationContacts:     map[int]map[int]bool{},
+		companies:               map[int]Company{},
+		nextCompanyId:           1,
+		attachments:             map[int]Attachment{},
+		nextAttachmentId:        1,
+		documents:               map[int]Document{},
+		nextDocumentId:          1,
+		documentVersions:        map[int]DocumentVersion{},
+		nextDocumentVersionId:   1,
+		tags:                    map[int]Tag{},
+		nextTagId:               1,
+		applicationTags:         map[int]map[int]bool{},
+		customFields:            map[int]CustomField{},
+		nextCustomFieldId:       1,
+		customFieldValues:       map[int]map[int]interface{}{},
+		reminders:               map[int]Reminder{},
+		nextReminderId:          1,
+		reminderLeases:          map[int]time.Time{},
+		notificationPreferences: map[int]NotificationPreferences{},
+		digestLeases:            map[int]time.Time{},
+		webhooks:                map[int]Webhook{},
+		nextWebhookId:           1,
+		outboxEvents:            map[int64]OutboxEvent{},
+		nextOutboxEventId:       1,
+		deliveries:              map[int64]WebhookDelivery{},
+		nextDeliveryId:          1,
+		deliveryLeases:          map[int64]time.Time{},
+		eventListeners:          map[int]ListenFunc{},
+		calendarTokens:          map[int]string{},
+		postingSnapshots:        map[int]PostingSnapshot{},
+		nextPostingSnapshotId:   1,
+		nextAuditRecordId:       1,
+		now:                     time.Now,
+	}
+}
+
+// truncateDate drops the time of day, like a DATE column does.
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+)
+
+// checkTag enforces the same constraints as the tag table. The name is
+// compared to the other tags of the same user regardless of case.
+func (s *MemoryStore) checkTag(tag Tag) error {
+	if len(tag.Name) > 64 || len(tag.Color) > 7 {
+		return ErrConstraintViolation
+	}
+
+	for _, other := range s.tags {
+		if other.Id != tag.Id && other.UserId == tag.UserId && strings.EqualFold(other.Name, tag.Name) {
+			return ErrAlreadyExists
+		}
+	}
+
+	return nil
+}
+
+func sortTags(tags []Tag) {
+	sort.Slice(tags, func(i, j int) bool {
+		a, b := strings.ToLower(tags[i].Name), strings.ToLower(tags[j].Name)
+		if a != b {
+			return a < b
+		}
+
+		return tags[i].Id < tags[j].Id
+	})
+}
+
+// tagNames returns the lowercased names of the tags of an application.
+func (s *MemoryStore) tagNames(applicationId int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var names []string
+	for tagId := range s.applicationTags[applicationId] {
+		names = append(names, strings.ToLower(s.tags[tagId].Name))
+	}
+
+	return names
+}
+
+func (s *MemoryStore) InsertTag(tag Tag) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag.Id = s.nextTagId
+	if err := s.checkTag(tag); err != nil {
+		return -1, err
+	}
+
+	s.nextTagId++
+	s.tags[tag.Id] = tag
+
+	return tag.Id, nil
+}
+
+func (s *MemoryStore) GetTags(userId int) ([]Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tags []Tag
+	for _, tag := range s.tags {
+		if tag.UserId == userId {
+			tags = append(tags, tag)
+		}
+	}
+
+	sortTags(tags)
+	return tags, nil
+}
+
+func (s *MemoryStore) GetTag(id int) (Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag, ok := s.tags[id]
+	if !ok {
+		return Tag{}, ErrNotFound
+	}
+
+	return tag, nil
+}
+
+func (s *MemoryStore) UpdateTag(tag Tag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.tags[tag.Id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	tag.UserId = old.UserId
+	if err := s.checkTag(tag); err != nil {
+		return err
+	}
+
+	s.tags[tag.Id] = tag
+	return nil
+}
+
+func (s *MemoryStore) DeleteTag(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tags[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.tags, id)
+	for _, tagIds := range s.applicationTags {
+		delete(tagIds, id)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) TagApplications(tagId int, applicationIds []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tags[tagId]; !ok {
+		return ErrConstraintViolation
+	}
+
+	for _, applicationId := range applicationIds {
+		if _, ok := s.applications[applicationId]; !ok {
+			return ErrConstraintViolation
+		}
+	}
+
+	for _, applicationId := range applicationIds {
+		if s.applicationTags[applicationId] == nil {
+			s.applicationTags[applicationId] = map[int]bool{}
+		}
+
+		s.applicationTags[applicationId][tagId] = true
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) UntagApplications(tagId int, applicationIds []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, applicationId := range applicationIds {
+		delete(s.applicationTags[applicationId], tagId)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) GetApplicationTags(applicationId int) ([]Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tags []Tag
+	for tagId := range s.applicationTags[applicationId] {
+		tags = append(tags, s.tags[tagId])
+	}
+
+	sortTags(tags)
+	return tags, nil
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTags(t *testing.T) {
+	store := NewMemoryStore()
+	applicationId, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+
+	tagId, err := store.InsertTag(Tag{UserId: 1, Name: "Go", Color: "#00add8"})
+	assert.Nil(t, err)
+
+	_, err = store.InsertTag(Tag{UserId: 1, Name: "go"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+
+	_, err = store.InsertTag(Tag{UserId: 2, Name: "go"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, store.TagApplications(tagId, []int{applicationId}))
+	assert.Nil(t, store.TagApplications(tagId, []int{applicationId}))
+	assert.ErrorIs(t, store.TagApplications(tagId, []int{42}), ErrConstraintViolation)
+
+	tags, err := store.GetApplicationTags(applicationId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(tags))
+	assert.Equal(t, "Go", tags[0].Name)
+
+	assert.Nil(t, store.DeleteTag(tagId))
+	tags, _ = store.GetApplicationTags(applicationId)
+	assert.Empty(t, tags)
+}
+
+func TestMemoryQueryApplicationsByTags(t *testing.T) {
+	store := NewMemoryStore()
+	goId, _ := store.InsertTag(Tag{UserId: 1, Name: "Go"})
+	berlinId, _ := store.InsertTag(Tag{UserId: 1, Name: "Berlin"})
+
+	for i := 0; i < 3; i++ {
+		store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	}
+
+	store.TagApplications(goId, []int{1, 2})
+	store.TagApplications(berlinId, []int{2, 3})
+
+	page, err := store.QueryApplications(1, ApplicationQuery{Tags: []string{"go", "BERLIN"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.Total)
+
+	page, err = store.QueryApplications(1, ApplicationQuery{Tags: []string{"go", "berlin"}, MatchAllTags: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.Total)
+	assert.Equal(t, 2, page.Applications[0].Id)
+
+	store.UntagApplications(goId, []int{2})
+	page, _ = store.QueryApplications(1, ApplicationQuery{Tags: []string{"go"}})
+	assert.Equal(t, 1, page.Total)
+	assert.Equal(t, 1, page.Applications[0].Id)
+}
@@ -0,0 +1,225 @@
+package controller
+
+import "sort"
+
+func (s *MemoryStore) GetWorkTypes() ([]WorkType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workTypes := make([]WorkType, len(s.workTypes))
+	copy(workTypes, s.workTypes)
+	return workTypes, nil
+}
+
+// filterStatuses returns the statuses matching the filter ordered by their
+// position.
+func (s *MemoryStore) filterStatuses(filter func(status ApplicationStatus) bool) []ApplicationStatus {
+	var statuses []ApplicationStatus
+	for _, status := range s.statuses {
+		if filter(status) {
+			statuses = append(statuses, status)
+		}
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Position != statuses[j].Position {
+			return statuses[i].Position < statuses[j].Position
+		}
+
+		return statuses[i].Id < statuses[j].Id
+	})
+
+	return statuses
+}
+
+func (s *MemoryStore) GetStatuses() ([]ApplicationStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterStatuses(func(status ApplicationStatus) bool {
+		return status.UserId == nil
+	}), nil
+}
+
+func (s *MemoryStore) GetUsableStatuses(userId int) ([]ApplicationStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := s.filterStatuses(func(status ApplicationStatus) bool {
+		return status.UserId != nil && *status.UserId == userId
+	})
+
+	return append(statuses, s.filterStatuses(func(status ApplicationStatus) bool {
+		return status.UserId == nil
+	})...), nil
+}
+
+func (s *MemoryStore) GetUserStatuses(userId int) ([]ApplicationStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := s.filterStatuses(func(status ApplicationStatus) bool {
+		return status.UserId != nil && *status.UserId == userId
+	})
+
+	if len(statuses) > 0 {
+		return statuses, nil
+	}
+
+	return s.filterStatuses(func(status ApplicationStatus) bool {
+		return status.UserId == nil
+	}), nil
+}
+
+func (s *MemoryStore) statusIndex(id int) int {
+	for i, status := range s.statuses {
+		if status.Id == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (s *MemoryStore) GetStatus(id int) (ApplicationStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.statusIndex(id)
+	if i < 0 {
+		return ApplicationStatus{}, ErrNotFound
+	}
+
+	return s.statuses[i], nil
+}
+
+// checkStatus enforces the same constraints as the application_status table.
+func (s *MemoryStore) checkStatus(status ApplicationStatus) error {
+	if len(status.Name) > 255 || len(status.Color) > 7 {
+		return ErrConstraintViolation
+	}
+
+	if status.StaleAfterDays != nil && *status.StaleAfterDays <= 0 {
+		return ErrConstraintViolation
+	}
+
+	if status.StaleStatusId != nil && s.statusIndex(*status.StaleStatusId) < 0 {
+		return ErrConstraintViolation
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) InsertStatus(status ApplicationStatus) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkStatus(status); err != nil {
+		return -1, err
+	}
+
+	status.Id = s.nextStatusId
+	s.nextStatusId++
+	s.statuses = append(s.statuses, status)
+
+	return status.Id, nil
+}
+
+func (s *MemoryStore) UpdateStatus(status ApplicationStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.statusIndex(status.Id)
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	if err := s.checkStatus(status); err != nil {
+		return err
+	}
+
+	status.UserId = s.statuses[i].UserId
+	s.statuses[i] = status
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteStatus(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.statusIndex(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	for _, application := range s.applications {
+		if application.StatusId == id {
+			return ErrInUse
+		}
+	}
+
+	for _, change := range s.statusHistory {
+		if change.NewStatusId == id || (change.OldStatusId != nil && *change.OldStatusId == id) {
+			return ErrInUse
+		}
+	}
+
+	s.statuses = append(s.statuses[:i], s.statuses[i+1:]...)
+
+	for j := range s.statuses {
+		if s.statuses[j].StaleStatusId != nil && *s.statuses[j].StaleStatusId == id {
+			s.statuses[j].StaleStatusId = nil
+		}
+	}
+
+	// Like the foreign keys of the transitions, deleting a status cascades.
+	for userId, transitions := range s.transitions {
+		var kept []StatusTransition
+		for _, transition := range transitions {
+			if transition.FromStatusId != id && transition.ToStatusId != id {
+				kept = append(kept, transition)
+			}
+		}
+
+		s.transitions[userId] = kept
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) GetWorkflow(userId int) (Workflow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workflow := Workflow{UserId: userId}
+	workflow.Transitions = append(workflow.Transitions, s.transitions[userId]...)
+	return workflow, nil
+}
+
+func (s *MemoryStore) SetWorkflow(workflow Workflow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var transitions []StatusTransition
+	for _, transition := range workflow.Transitions {
+		if s.statusIndex(transition.FromStatusId) < 0 || s.statusIndex(transition.ToStatusId) < 0 {
+			return ErrConstraintViolation
+		}
+
+		replaced := false
+		for i := range transitions {
+			if transitions[i].FromStatusId == transition.FromStatusId && transitions[i].ToStatusId == transition.ToStatusId {
+				transitions[i].RequiresReopen = transition.RequiresReopen
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			transitions = append(transitions, transition)
+		}
+	}
+
+	s.transitions[workflow.UserId] = transitions
+	return nil
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryGetUserStatuses(t *testing.T) {
+	store := NewMemoryStore()
+	userId := 1
+
+	statuses, err := store.GetUserStatuses(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(statuses))
+
+	store.InsertStatus(ApplicationStatus{Name: "Offer", UserId: &userId, Position: 2, Terminal: true})
+	store.InsertStatus(ApplicationStatus{Name: "Applied", UserId: &userId, Position: 1})
+
+	statuses, err = store.GetUserStatuses(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(statuses))
+	assert.Equal(t, "Applied", statuses[0].Name)
+	assert.Equal(t, "Offer", statuses[1].Name)
+
+	statuses, _ = store.GetStatuses()
+	assert.Equal(t, 3, len(statuses))
+}
+
+func TestMemoryGetUsableStatuses(t *testing.T) {
+	store := NewMemoryStore()
+	userId := 1
+	otherId := 2
+
+	store.InsertStatus(ApplicationStatus{Name: "Pending", UserId: &userId, Position: 2})
+	store.InsertStatus(ApplicationStatus{Name: "Applied", UserId: &userId, Position: 1})
+	store.InsertStatus(ApplicationStatus{Name: "Interview", UserId: &otherId, Position: 1})
+
+	statuses, err := store.GetUsableStatuses(userId)
+	assert.Nil(t, err)
+
+	var names []string
+	for _, status := range statuses {
+		names = append(names, status.Name)
+	}
+
+	assert.Equal(t, []string{"Applied", "Pending", "Accepted", "Pending", "Declined"}, names)
+}
+
+func TestMemoryUpdateStatusKeepsOwner(t *testing.T) {
+	store := NewMemoryStore()
+	userId := 1
+
+	id, _ := store.InsertStatus(ApplicationStatus{Name: "Applied", UserId: &userId})
+	err := store.UpdateStatus(ApplicationStatus{Id: id, Name: "Submitted", Color: "#00ff00"})
+
+	status, _ := store.GetStatus(id)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Submitted", status.Name)
+	assert.Equal(t, userId, *status.UserId)
+}
+
+func TestMemoryDeleteStatusInUse(t *testing.T) {
+	store := NewMemoryStore()
+	userId := 1
+
+	id, _ := store.InsertStatus(ApplicationStatus{Name: "Applied", UserId: &userId})
+	store.InsertApplication(Application{UserId: userId, WorkTypeId: 1, StatusId: id})
+
+	assert.ErrorIs(t, store.DeleteStatus(id), ErrInUse)
+	assert.ErrorIs(t, store.DeleteStatus(42), ErrNotFound)
+}
+
+func TestMemorySetWorkflow(t *testing.T) {
+	store := NewMemoryStore()
+
+	err := store.SetWorkflow(Workflow{UserId: 1, Transitions: []StatusTransition{
+		{FromStatusId: 2, ToStatusId: 3},
+		{FromStatusId: 2, ToStatusId: 3, RequiresReopen: true},
+	}})
+
+	assert.Nil(t, err)
+
+	workflow, _ := store.GetWorkflow(1)
+	assert.Equal(t, []StatusTransition{{FromStatusId: 2, ToStatusId: 3, RequiresReopen: true}}, workflow.Transitions)
+
+	workflow, _ = store.GetWorkflow(2)
+	assert.Empty(t, workflow.Transitions)
+
+	assert.ErrorIs(t, store.SetWorkflow(Workflow{UserId: 1, Transitions: []StatusTransition{{FromStatusId: 2, ToStatusId: 42}}}), ErrConstraintViolation)
+}
+
+func TestMemoryDeleteStatusCascadesTransitions(t *testing.T) {
+	store := NewMemoryStore()
+	userId := 1
+
+	id, _ := store.InsertStatus(ApplicationStatus{Name: "Ghosted", UserId: &userId})
+	store.SetWorkflow(Workflow{UserId: userId, Transitions: []StatusTransition{
+		{FromStatusId: 2, ToStatusId: id},
+		{FromStatusId: 2, ToStatusId: 3},
+	}})
+
+	assert.Nil(t, store.DeleteStatus(id))
+
+	workflow, _ := store.GetWorkflow(userId)
+	assert.Equal(t, []StatusTransition{{FromStatusId: 2, ToStatusId: 3}}, workflow.Transitions)
+}
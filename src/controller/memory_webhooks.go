@@ -0,0 +1,280 @@
+package controller
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// enqueueApplicationEvent writes an event about the application to the
+// outbox like the PostgreSQL implementation does. The store must be locked.
+func (s *MemoryStore) enqueueApplicationEvent(event string, application Application, oldStatusId *int) error {
+	payload, err := json.Marshal(eventPayload(application, oldStatusId))
+	if err != nil {
+		return err
+	}
+
+	s.outboxEvents[s.nextOutboxEventId] = OutboxEvent{
+		Id:            s.nextOutboxEventId,
+		Position:      s.nextOutboxEventId,
+		UserId:        application.UserId,
+		Event:         event,
+		ApplicationId: application.Id,
+		Payload:       payload,
+		CreatedAt:     s.now().UTC(),
+	}
+
+	s.nextOutboxEventId++
+	s.notifyListeners(application.UserId)
+	return nil
+}
+
+// GetOutboxEvents returns the events in the outbox in the order they were
+// written. It is meant for tests.
+func (s *MemoryStore) GetOutboxEvents() []OutboxEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []OutboxEvent
+	for _, event := range s.outboxEvents {
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Id < events[j].Id
+	})
+
+	return events
+}
+
+func checkWebhook(webhook Webhook) error {
+	if len(webhook.Url) > 2000 || len(webhook.Secret) > 64 || webhook.Events == nil {
+		return ErrConstraintViolation
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) InsertWebhook(webhook Webhook) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkWebhook(webhook); err != nil {
+		return -1, err
+	}
+
+	webhook.Id = s.nextWebhookId
+	webhook.Events = append([]string{}, webhook.Events...)
+	webhook.CreatedAt = s.now().UTC()
+	s.nextWebhookId++
+	s.webhooks[webhook.Id] = webhook
+
+	return webhook.Id, nil
+}
+
+func (s *MemoryStore) GetWebhooks(userId int) ([]Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var webhooks []Webhook
+	for _, webhook := range s.webhooks {
+		if webhook.UserId == userId {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].Id < webhooks[j].Id
+	})
+
+	return webhooks, nil
+}
+
+func (s *MemoryStore) GetWebhook(id int) (Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook, ok := s.webhooks[id]
+	if !ok {
+		return Webhook{}, ErrNotFound
+	}
+
+	return webhook, nil
+}
+
+func (s *MemoryStore) UpdateWebhook(webhook Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.webhooks[webhook.Id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if err := checkWebhook(webhook); err != nil {
+		return err
+	}
+
+	stored.Url = webhook.Url
+	stored.Events = append([]string{}, webhook.Events...)
+	stored.Active = webhook.Active
+	s.webhooks[webhook.Id] = stored
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteWebhook(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.webhooks, id)
+
+	for deliveryId, delivery := range s.deliveries {
+		if delivery.WebhookId == id {
+			delete(s.deliveries, deliveryId)
+		}
+	}
+
+	return nil
+}
+
+// withEvent fills in the event of the delivery like the join of the
+// PostgreSQL implementation.
+func (s *MemoryStore) withEvent(delivery WebhookDelivery) WebhookDelivery {
+	event := s.outboxEvents[delivery.EventId]
+	delivery.Event = event.Event
+	delivery.Payload = event.Payload
+	delivery.EventCreatedAt = event.CreatedAt
+
+	return delivery
+}
+
+func (s *MemoryStore) GetWebhookDeliveries(webhookId int, limit int) ([]WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deliveries []WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.WebhookId == webhookId {
+			deliveries = append(deliveries, s.withEvent(delivery))
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].Id > deliveries[j].Id
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (s *MemoryStore) DispatchEvents(now time.Time, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []OutboxEvent
+	for _, event := range s.outboxEvents {
+		if event.DispatchedAt == nil {
+			pending = append(pending, event)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Id < pending[j].Id
+	})
+
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	var webhookIds []int
+	for id := range s.webhooks {
+		webhookIds = append(webhookIds, id)
+	}
+	sort.Ints(webhookIds)
+
+	for _, event := range pending {
+		for _, webhookId := range webhookIds {
+			webhook := s.webhooks[webhookId]
+			if webhook.UserId != event.UserId || !webhook.Active || !containsString(webhook.Events, event.Event) {
+				continue
+			}
+
+			nextAttemptAt := now
+			s.deliveries[s.nextDeliveryId] = WebhookDelivery{
+				Id:            s.nextDeliveryId,
+				WebhookId:     webhookId,
+				EventId:       event.Id,
+				Status:        DeliveryPending,
+				NextAttemptAt: &nextAttemptAt,
+				CreatedAt:     s.now().UTC(),
+			}
+
+			s.nextDeliveryId++
+		}
+
+		dispatchedAt := now
+		event.DispatchedAt = &dispatchedAt
+		s.outboxEvents[event.Id] = event
+	}
+
+	return len(pending), nil
+}
+
+// DeliverDueWebhooks mirrors the leases of the PostgreSQL implementation.
+// The deliveries are attempted without holding the lock of the store.
+func (s *MemoryStore) DeliverDueWebhooks(now time.Time, limit int, deliver DeliverFunc) (int, error) {
+	s.mu.Lock()
+	var due []WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == DeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) &&
+			s.webhooks[delivery.WebhookId].Active && !s.deliveryLeases[delivery.Id].After(now) {
+			due = append(due, s.withEvent(delivery))
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(*due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+		}
+
+		return due[i].Id < due[j].Id
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	webhooks := make([]Webhook, len(due))
+	for i, delivery := range due {
+		s.deliveryLeases[delivery.Id] = now.Add(deliveryLease)
+		webhooks[i] = s.webhooks[delivery.WebhookId]
+	}
+	s.mu.Unlock()
+
+	for i, delivery := range due {
+		delivery = deliver(webhooks[i], delivery)
+
+		s.mu.Lock()
+		delete(s.deliveryLeases, delivery.Id)
+		if stored, ok := s.deliveries[delivery.Id]; ok {
+			stored.Status = delivery.Status
+			stored.Attempts = delivery.Attempts
+			stored.NextAttemptAt = delivery.NextAttemptAt
+			stored.LastAttemptAt = delivery.LastAttemptAt
+			stored.ResponseStatus = delivery.ResponseStatus
+			stored.Error = delivery.Error
+			s.deliveries[delivery.Id] = stored
+		}
+		s.mu.Unlock()
+	}
+
+	return len(due), nil
+}
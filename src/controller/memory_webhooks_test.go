@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryOutboxEvents(t *testing.T) {
+	store := NewMemoryStore()
+
+	id, _ := store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Go Developer"})
+	application, _ := store.GetApplication(id)
+	application.JobTitle = "Senior Go Developer"
+	store.UpdateApplication(application)
+	application.StatusId = 1
+	store.UpdateApplication(application)
+	store.DeleteApplication(id)
+
+	var events []string
+	for _, event := range store.GetOutboxEvents() {
+		assert.Equal(t, 1, event.UserId)
+		assert.Equal(t, id, event.ApplicationId)
+		events = append(events, event.Event)
+	}
+
+	assert.Equal(t, []string{
+		EventApplicationCreated,
+		EventApplicationUpdated,
+		EventApplicationUpdated,
+		EventApplicationStatusChanged,
+		EventApplicationDeleted,
+	}, events)
+
+	var payload struct {
+		Application Application
+		OldStatusId int `json:"oldStatusId"`
+	}
+	assert.Nil(t, json.Unmarshal(store.GetOutboxEvents()[3].Payload, &payload))
+	assert.Equal(t, "Senior Go Developer", payload.Application.JobTitle)
+	assert.Equal(t, 2, payload.OldStatusId)
+}
+
+func TestMemoryDeliverWebhooks(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	webhookId, _ := store.InsertWebhook(Webhook{UserId: 1, Url: "https://example.com/hook", Secret: "secret", Events: []string{EventApplicationCreated}, Active: true})
+	store.InsertWebhook(Webhook{UserId: 1, Url: "https://example.com/inactive", Secret: "secret", Events: []string{EventApplicationCreated}})
+
+	store.InsertApplication(Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	store.InsertApplication(Application{UserId: 2, WorkTypeId: 1, StatusId: 2})
+
+	dispatched, err := store.DispatchEvents(now, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, dispatched)
+
+	// Events without subscribers are kept for the event stream.
+	for _, event := range store.GetOutboxEvents() {
+		assert.Equal(t, now, *event.DispatchedAt)
+	}
+
+	var delivered []WebhookDelivery
+	attempted, err := store.DeliverDueWebhooks(now, 10, func(webhook Webhook, delivery WebhookDelivery) WebhookDelivery {
+		assert.Equal(t, "secret", webhook.Secret)
+		delivered = append(delivered, delivery)
+
+		next := now.Add(time.Minute)
+		delivery.Attempts++
+		delivery.NextAttemptAt = &next
+		delivery.Error = "unavailable"
+		return delivery
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempted)
+	assert.Equal(t, EventApplicationCreated, delivered[0].Event)
+
+	attempted, _ = store.DeliverDueWebhooks(now, 10, nil)
+	assert.Equal(t, 0, attempted)
+
+	// Claimed deliveries are skipped by other schedulers until their lease
+	// expires.
+	later := now.Add(time.Minute)
+	attempted, _ = store.DeliverDueWebhooks(later, 10, func(webhook Webhook, delivery WebhookDelivery) WebhookDelivery {
+		claimed, _ := store.DeliverDueWebhooks(later, 10, nil)
+		assert.Equal(t, 0, claimed)
+
+		claimed, _ = store.DeliverDueWebhooks(later.Add(deliveryLease), 10, func(webhook Webhook, delivery WebhookDelivery) WebhookDelivery {
+			return delivery
+		})
+		assert.Equal(t, 1, claimed)
+
+		return delivery
+	})
+	assert.Equal(t, 1, attempted)
+
+	deliveries, _ := store.GetWebhookDeliveries(webhookId, 10)
+	assert.Equal(t, 1, len(deliveries))
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, "unavailable", deliveries[0].Error)
+	assert.Equal(t, DeliveryPending, deliveries[0].Status)
+
+	assert.Nil(t, store.DeleteWebhook(webhookId))
+	deliveries, _ = store.GetWebhookDeliveries(webhookId, 10)
+	assert.Empty(t, deliveries)
+}
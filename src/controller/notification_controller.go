@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// NotificationPreferences configure, which notifications a user receives
+// and where. All events are enabled, unless they are disabled explicitly.
+// Users without an email address do not receive emails. Digests, which could
+// not be delivered, are retried at DigestNextAttemptAt.
+type NotificationPreferences struct {
+	UserId              int        `db:"user_id" json:"-"`
+	Email               string     `db:"email" json:"email"`
+	DisabledEvents      []string   `db:"disabled_events" json:"-"`
+	DigestSentAt        time.Time  `db:"digest_sent_at" json:"-"`
+	DigestAttempts      int        `db:"digest_attempts" json:"-"`
+	DigestNextAttemptAt *time.Time `db:"digest_next_attempt_at" json:"-"`
+}
+
+// Enabled reports, whether the user wants to be notified about the event.
+func (p NotificationPreferences) Enabled(event string) bool {
+	return !containsString(p.DisabledEvents, event)
+}
+
+// DigestFunc delivers the digest of a user. Digests, which could not be
+// delivered, are retried later with a growing delay.
+type DigestFunc func(preferences NotificationPreferences) error
+
+type NotificationController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const notificationPreferenceColumns = "user_id, email, disabled_events, digest_sent_at, digest_attempts, digest_next_attempt_at"
+
+func scanNotificationPreferences(row pgx.Row) (NotificationPreferences, error) {
+	var preferences NotificationPreferences
+	err := row.Scan(&preferences.UserId, &preferences.Email, &preferences.DisabledEvents, &preferences.DigestSentAt,
+		&preferences.DigestAttempts, &preferences.DigestNextAttemptAt)
+
+	return preferences, err
+}
+
+// GetNotificationPreferences returns the preferences of the user or
+// ErrNotFound, if the user has not saved any.
+func (c NotificationController) GetNotificationPreferences(userId int) (NotificationPreferences, error) {
+	preferences, err := scanNotificationPreferences(c.Database.QueryRow(c.Context,
+		"SELECT "+notificationPreferenceColumns+" FROM notification_preference WHERE user_id = $1", userId))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NotificationPreferences{}, ErrNotFound
+	}
+
+	return preferences, err
+}
+
+// SaveNotificationPreferences creates or replaces the email address and the
+// disabled events of the user. The first digest is sent a period after the
+// preferences were created.
+func (c NotificationController) SaveNotificationPreferences(preferences NotificationPreferences) error {
+	if preferences.DisabledEvents == nil {
+		preferences.DisabledEvents = []string{}
+	}
+
+	_, err := c.Database.Exec(c.Context,
+		`INSERT INTO notification_preference (user_id, email, disabled_events) VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id) DO UPDATE SET email = excluded.email, disabled_events = excluded.disabled_events`,
+		preferences.UserId, preferences.Email, preferences.DisabledEvents)
+
+	return err
+}
+
+// SendDueDigests sends up to limit digests to the users with an email
+// address, whose last digest was sent at least a period before the given
+// time, and returns the number of delivered digests. Like reminders, the
+// digests are claimed for schedulingLease before they are sent, so every
+// digest is sent by only one of several concurrently running schedulers.
+// Digests, which could not be delivered, are retried after a growing delay.
+func (c NotificationController) SendDueDigests(now time.Time, period time.Duration, limit int, send DigestFunc) (int, error) {
+	rows, err := c.Database.Query(c.Context,
+		`WITH claimed AS (
+			UPDATE notification_preference SET digest_locked_until = $4
+			WHERE user_id IN (
+				SELECT user_id FROM notification_preference
+				WHERE email <> '' AND digest_sent_at <= $1
+				  AND (digest_next_attempt_at IS NULL OR digest_next_attempt_at <= $2)
+				  AND (digest_locked_until IS NULL OR digest_locked_until <= $2)
+				ORDER BY digest_sent_at, user_id
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED)
+			RETURNING *)
+		 SELECT `+notificationPreferenceColumns+` FROM claimed
+		 ORDER BY digest_sent_at, user_id`, now.Add(-period), now, limit, now.Add(schedulingLease))
+	if err != nil {
+		return 0, err
+	}
+
+	var due []NotificationPreferences
+	for rows.Next() {
+		preferences, err := scanNotificationPreferences(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		due = append(due, preferences)
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, preferences := range due {
+		if send(preferences) != nil {
+			nextAttemptAt := now.Add(retryBackoff(preferences.DigestAttempts + 1))
+			if _, err := c.Database.Exec(c.Context,
+				`UPDATE notification_preference
+				 SET digest_attempts = digest_attempts + 1, digest_next_attempt_at = $2, digest_locked_until = NULL
+				 WHERE user_id = $1`, preferences.UserId, nextAttemptAt); err != nil {
+				return sent, err
+			}
+
+			continue
+		}
+
+		if _, err := c.Database.Exec(c.Context,
+			`UPDATE notification_preference
+			 SET digest_sent_at = $2, digest_attempts = 0, digest_next_attempt_at = NULL, digest_locked_until = NULL
+			 WHERE user_id = $1`, preferences.UserId, now); err != nil {
+			return sent, err
+		}
+
+		sent++
+	}
+
+	return sent, nil
+}
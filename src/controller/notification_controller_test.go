@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	controller.CreateScheme()
+	notifications := NotificationController{Database: controller.Database, Context: controller.Context}
+
+	_, err := notifications.GetNotificationPreferences(1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Nil(t, notifications.SaveNotificationPreferences(NotificationPreferences{UserId: 1, Email: "jane@example.com"}))
+	assert.Nil(t, notifications.SaveNotificationPreferences(NotificationPreferences{UserId: 1, Email: "jane@example.org", DisabledEvents: []string{"digest"}}))
+
+	preferences, err := notifications.GetNotificationPreferences(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "jane@example.org", preferences.Email)
+	assert.Equal(t, []string{"digest"}, preferences.DisabledEvents)
+	assert.False(t, preferences.Enabled("digest"))
+}
+
+func TestSendDueDigests(t *testing.T) {
+	controller.CreateScheme()
+	notifications := NotificationController{Database: controller.Database, Context: controller.Context}
+
+	for userId := 1; userId <= 3; userId++ {
+		if err := notifications.SaveNotificationPreferences(NotificationPreferences{UserId: userId, Email: "jane@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	period := 7 * 24 * time.Hour
+	sent, err := notifications.SendDueDigests(time.Now(), period, 10, func(preferences NotificationPreferences) error {
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+
+	// Failed digests are retried after a backoff.
+	later := time.Now().Add(period)
+	sent, err = notifications.SendDueDigests(later, period, 10, func(preferences NotificationPreferences) error {
+		if preferences.UserId == 2 {
+			return errors.New("unavailable")
+		}
+
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, sent)
+
+	var users []int
+	collect := func(preferences NotificationPreferences) error {
+		users = append(users, preferences.UserId)
+		return nil
+	}
+
+	sent, err = notifications.SendDueDigests(later, period, 10, collect)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+
+	sent, err = notifications.SendDueDigests(later.Add(time.Minute), period, 10, collect)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []int{2}, users)
+}
+
+func TestSendDueDigestsSkipsFailing(t *testing.T) {
+	controller.CreateScheme()
+	notifications := NotificationController{Database: controller.Database, Context: controller.Context}
+
+	for userId := 1; userId <= 2; userId++ {
+		if err := notifications.SaveNotificationPreferences(NotificationPreferences{UserId: userId, Email: "jane@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	period := 7 * 24 * time.Hour
+	later := time.Now().Add(period)
+	var users []int
+	send := func(preferences NotificationPreferences) error {
+		if preferences.UserId == 1 {
+			return errors.New("unavailable")
+		}
+
+		users = append(users, preferences.UserId)
+		return nil
+	}
+
+	// The failing digest is due first, but does not hold up the other one.
+	sent, err := notifications.SendDueDigests(later, period, 1, send)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+
+	sent, err = notifications.SendDueDigests(later, period, 1, send)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []int{2}, users)
+
+	preferences, err := notifications.GetNotificationPreferences(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, preferences.DigestAttempts)
+	assert.NotNil(t, preferences.DigestNextAttemptAt)
+}
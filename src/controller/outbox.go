@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// The events, which are written to the outbox, when applications change.
+const (
+	EventApplicationCreated       = "application.created"
+	EventApplicationUpdated       = "application.updated"
+	EventApplicationDeleted       = "application.deleted"
+	EventApplicationStatusChanged = "application.status_changed"
+)
+
+// ApplicationEvents lists all events, to which webhooks can subscribe.
+var ApplicationEvents = []string{
+	EventApplicationCreated,
+	EventApplicationUpdated,
+	EventApplicationDeleted,
+	EventApplicationStatusChanged,
+}
+
+// OutboxEvent records a change of an application. The payload contains the
+// application after the change, or before, if it was deleted. Status changes
+// contain the previous status as oldStatusId. The position orders the events
+// by the commits, which wrote them, and is taken, when the event commits.
+type OutboxEvent struct {
+	Id            int64           `db:"id" json:"id"`
+	Position      int64           `db:"position" json:"position"`
+	UserId        int             `db:"user_id" json:"userId"`
+	Event         string          `db:"event" json:"event"`
+	ApplicationId int             `db:"application_id" json:"applicationId"`
+	Payload       json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt     time.Time       `db:"created_at" json:"createdAt"`
+	DispatchedAt  *time.Time      `db:"dispatched_at" json:"dispatchedAt"`
+}
+
+// eventPayload creates the payload of an event about the application.
+func eventPayload(application Application, oldStatusId *int) map[string]interface{} {
+	payload := map[string]interface{}{"application": application}
+	if oldStatusId != nil {
+		payload["oldStatusId"] = *oldStatusId
+	}
+
+	return payload
+}
+
+// enqueueApplicationEvent writes an event about the current state of the
+// application to the outbox. It is called in the transaction, which changes
+// the application, so the event is recorded, if and only if the change is
+// committed.
+func enqueueApplicationEvent(ctx context.Context, tx pgx.Tx, event string, applicationId int, oldStatusId *int) error {
+	application, err := scanApplication(tx.QueryRow(ctx, "SELECT "+applicationColumns+" FROM application WHERE id = $1", applicationId))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO outbox_event (user_id, event, application_id, payload) VALUES ($1, $2, $3, $4::jsonb)",
+		application.UserId, event, application.Id, jsonValue{eventPayload(application, oldStatusId)})
+
+	return err
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PostingSnapshot is a fetched page of a job posting, which is kept, so the
+// posting can be read after it was taken down. Url is the url, which was
+// requested. The content is stored in a blob store under the blob key.
+type PostingSnapshot struct {
+	Id          int       `db:"id" json:"id"`
+	UserId      int       `db:"user_id" json:"userId"`
+	Url         string    `db:"url" json:"url"`
+	ContentType string    `db:"content_type" json:"contentType"`
+	Size        int64     `db:"size" json:"size"`
+	BlobKey     string    `db:"blob_key" json:"-"`
+	FetchedAt   time.Time `db:"fetched_at" json:"fetchedAt"`
+}
+
+type PostingController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const postingSnapshotColumns = "id, user_id, url, content_type, size, blob_key, fetched_at"
+
+func scanPostingSnapshot(row pgx.Row) (PostingSnapshot, error) {
+	var snapshot PostingSnapshot
+	err := row.Scan(&snapshot.Id, &snapshot.UserId, &snapshot.Url, &snapshot.ContentType,
+		&snapshot.Size, &snapshot.BlobKey, &snapshot.FetchedAt)
+
+	return snapshot, err
+}
+
+func (c PostingController) InsertPostingSnapshot(snapshot PostingSnapshot) (int, error) {
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		`INSERT INTO job_posting_snapshot (user_id, url, content_type, size, blob_key)
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		snapshot.UserId, snapshot.Url, snapshot.ContentType, snapshot.Size, snapshot.BlobKey).Scan(&id)
+
+	return id, err
+}
+
+// GetPostingSnapshots returns the snapshots of the posting at the url, which
+// were fetched by the user, the latest first.
+func (c PostingController) GetPostingSnapshots(userId int, url string) ([]PostingSnapshot, error) {
+	rows, err := c.Database.Query(c.Context,
+		"SELECT "+postingSnapshotColumns+" FROM job_posting_snapshot WHERE user_id = $1 AND url = $2 ORDER BY fetched_at DESC, id DESC",
+		userId, url)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []PostingSnapshot
+	for rows.Next() {
+		snapshot, err := scanPostingSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
+
+func (c PostingController) GetPostingSnapshot(id int) (PostingSnapshot, error) {
+	snapshot, err := scanPostingSnapshot(c.Database.QueryRow(c.Context,
+		"SELECT "+postingSnapshotColumns+" FROM job_posting_snapshot WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PostingSnapshot{}, ErrNotFound
+	}
+
+	return snapshot, err
+}
+
+// GetPostingSnapshotUsage sums up the sizes of all snapshots of the user.
+func (c PostingController) GetPostingSnapshotUsage(userId int) (int64, error) {
+	var usage int64
+	err := c.Database.QueryRow(c.Context,
+		"SELECT coalesce(sum(size), 0) FROM job_posting_snapshot WHERE user_id = $1", userId).Scan(&usage)
+
+	return usage, err
+}
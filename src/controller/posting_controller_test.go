@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostingSnapshots(t *testing.T) {
+	controller.CreateScheme()
+	postings := PostingController{Database: controller.Database, Context: controller.Context}
+
+	url := "https://example.com/jobs/1"
+	first, err := postings.InsertPostingSnapshot(PostingSnapshot{UserId: 1, Url: url, ContentType: "text/html", Size: 100, BlobKey: "a"})
+	assert.Nil(t, err)
+	latest, err := postings.InsertPostingSnapshot(PostingSnapshot{UserId: 1, Url: url, ContentType: "text/html", Size: 50, BlobKey: "b"})
+	assert.Nil(t, err)
+
+	_, err = postings.InsertPostingSnapshot(PostingSnapshot{UserId: 1, Url: url, ContentType: "text/html", BlobKey: "a"})
+	assert.NotNil(t, err)
+
+	snapshots, err := postings.GetPostingSnapshots(1, url)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(snapshots))
+	assert.Equal(t, latest, snapshots[0].Id)
+
+	snapshot, err := postings.GetPostingSnapshot(first)
+	assert.Nil(t, err)
+	assert.Equal(t, url, snapshot.Url)
+	assert.False(t, snapshot.FetchedAt.IsZero())
+
+	_, err = postings.GetPostingSnapshot(100)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	usage, err := postings.GetPostingSnapshotUsage(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(150), usage)
+}
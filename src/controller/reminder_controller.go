@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Reminder reminds the owner of an application at a given time, e.g. to
+// follow up. FiredAt is set, once the reminder has been delivered. Reminders,
+// which could not be delivered, are retried at NextAttemptAt.
+type Reminder struct {
+	Id            int        `db:"id" json:"id"`
+	ApplicationId int        `db:"application_id" json:"applicationId"`
+	RemindAt      time.Time  `db:"remind_at" json:"remindAt"`
+	Message       string     `db:"message" json:"message"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	FiredAt       *time.Time `db:"fired_at" json:"firedAt"`
+	Attempts      int        `db:"attempts" json:"attempts"`
+	NextAttemptAt *time.Time `db:"next_attempt_at" json:"nextAttemptAt"`
+}
+
+// dueAt returns the time of the next attempt to deliver the reminder.
+func (r Reminder) dueAt() time.Time {
+	if r.NextAttemptAt != nil {
+		return *r.NextAttemptAt
+	}
+
+	return r.RemindAt
+}
+
+// FireFunc delivers a due reminder. Reminders, which could not be delivered,
+// are retried later with a growing delay.
+type FireFunc func(reminder Reminder) error
+
+type ReminderController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const reminderColumns = "id, application_id, remind_at, message, created_at, fired_at, attempts, next_attempt_at"
+
+func scanReminder(row pgx.Row) (Reminder, error) {
+	var reminder Reminder
+	err := row.Scan(&reminder.Id, &reminder.ApplicationId, &reminder.RemindAt, &reminder.Message, &reminder.CreatedAt, &reminder.FiredAt,
+		&reminder.Attempts, &reminder.NextAttemptAt)
+
+	return reminder, err
+}
+
+// scanReminders reads all reminders from the result of a query.
+func scanReminders(rows pgx.Rows, err error) ([]Reminder, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []Reminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+func (c ReminderController) InsertReminder(reminder Reminder) (int, error) {
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		"INSERT INTO reminder (application_id, remind_at, message) VALUES ($1, $2, $3) RETURNING id",
+		reminder.ApplicationId, reminder.RemindAt, reminder.Message).Scan(&id)
+
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (c ReminderController) GetReminders(applicationId int) ([]Reminder, error) {
+	return scanReminders(c.Database.Query(c.Context,
+		"SELECT "+reminderColumns+" FROM reminder WHERE application_id = $1 ORDER BY remind_at, id", applicationId))
+}
+
+// GetUserReminders returns the reminders of all applications of the user.
+func (c ReminderController) GetUserReminders(userId int) ([]Reminder, error) {
+	return scanReminders(c.Database.Query(c.Context,
+		`SELECT `+reminderColumns+` FROM reminder
+		 WHERE application_id IN (SELECT id FROM application WHERE user_id = $1)
+		 ORDER BY remind_at, id`, userId))
+}
+
+func (c ReminderController) GetReminder(id int) (Reminder, error) {
+	reminder, err := scanReminder(c.Database.QueryRow(c.Context, "SELECT "+reminderColumns+" FROM reminder WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Reminder{}, ErrNotFound
+	}
+
+	return reminder, err
+}
+
+func (c ReminderController) DeleteReminder(id int) error {
+	result, err := c.Database.Exec(c.Context, "DELETE FROM reminder WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// FireDueReminders fires up to limit reminders, which are due at the given
+// time, and returns the number of delivered reminders. The reminders are
+// claimed for schedulingLease before they are fired, so every reminder is
+// fired by only one of several concurrently running schedulers, while no
+// transaction is kept open during the delivery. Reminders, which could not
+// be delivered, are retried after a growing delay, so they do not hold up
+// the reminders due after them.
+func (c ReminderController) FireDueReminders(now time.Time, limit int, fire FireFunc) (int, error) {
+	reminders, err := scanReminders(c.Database.Query(c.Context,
+		`WITH claimed AS (
+			UPDATE reminder SET locked_until = $3
+			WHERE id IN (
+				SELECT id FROM reminder
+				WHERE fired_at IS NULL AND coalesce(next_attempt_at, remind_at) <= $1
+				  AND (locked_until IS NULL OR locked_until <= $1)
+				ORDER BY coalesce(next_attempt_at, remind_at), id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED)
+			RETURNING *)
+		 SELECT `+reminderColumns+` FROM claimed
+		 ORDER BY coalesce(next_attempt_at, remind_at), id`, now, limit, now.Add(schedulingLease)))
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for _, reminder := range reminders {
+		if fire(reminder) != nil {
+			nextAttemptAt := now.Add(retryBackoff(reminder.Attempts + 1))
+			if _, err := c.Database.Exec(c.Context,
+				"UPDATE reminder SET attempts = attempts + 1, next_attempt_at = $2, locked_until = NULL WHERE id = $1",
+				reminder.Id, nextAttemptAt); err != nil {
+				return fired, err
+			}
+
+			continue
+		}
+
+		if _, err := c.Database.Exec(c.Context,
+			"UPDATE reminder SET fired_at = $2, locked_until = NULL WHERE id = $1", reminder.Id, now); err != nil {
+			return fired, err
+		}
+
+		fired++
+	}
+
+	return fired, nil
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFireDueReminders(t *testing.T) {
+	controller.CreateScheme()
+	reminders := ReminderController{Database: controller.Database, Context: controller.Context}
+
+	applicationId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for i := 0; i < 20; i++ {
+		if _, err := reminders.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now.Add(-time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	futureId, err := reminders.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now.Add(time.Hour), Message: "Follow up"})
+	assert.Nil(t, err)
+
+	var mu sync.Mutex
+	counts := map[int]int{}
+
+	// Concurrent schedulers skip the reminders claimed by each other.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				fired, err := reminders.FireDueReminders(now, 3, func(reminder Reminder) error {
+					mu.Lock()
+					counts[reminder.Id]++
+					mu.Unlock()
+					return nil
+				})
+
+				if err != nil || fired == 0 {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 20, len(counts))
+	for id, count := range counts {
+		assert.Equal(t, 1, count, "reminder %d", id)
+	}
+
+	future, err := reminders.GetReminder(futureId)
+	assert.Nil(t, err)
+	assert.Nil(t, future.FiredAt)
+	assert.Equal(t, "Follow up", future.Message)
+
+	all, err := reminders.GetReminders(applicationId)
+	assert.Nil(t, err)
+	assert.Equal(t, 21, len(all))
+
+	assert.Nil(t, reminders.DeleteReminder(futureId))
+	assert.ErrorIs(t, reminders.DeleteReminder(futureId), ErrNotFound)
+}
+
+func TestFireDueRemindersSkipsFailing(t *testing.T) {
+	controller.CreateScheme()
+	reminders := ReminderController{Database: controller.Database, Context: controller.Context}
+
+	applicationId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	failingId, err := reminders.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now.Add(-time.Hour)})
+	assert.Nil(t, err)
+	laterId, err := reminders.InsertReminder(Reminder{ApplicationId: applicationId, RemindAt: now})
+	assert.Nil(t, err)
+
+	var firedIds []int
+	fire := func(reminder Reminder) error {
+		if reminder.Id == failingId {
+			return errors.New("not delivered")
+		}
+
+		firedIds = append(firedIds, reminder.Id)
+		return nil
+	}
+
+	// The failing reminder is due first, but does not hold up the later one.
+	fired, err := reminders.FireDueReminders(now, 1, fire)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, fired)
+
+	fired, err = reminders.FireDueReminders(now, 1, fire)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, []int{laterId}, firedIds)
+
+	fired, err = reminders.FireDueReminders(now.Add(time.Minute), 1, fire)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, fired)
+
+	failing, err := reminders.GetReminder(failingId)
+	assert.Nil(t, err)
+	assert.Nil(t, failing.FiredAt)
+	assert.Equal(t, 2, failing.Attempts)
+	assert.True(t, now.Add(3*time.Minute).Equal(*failing.NextAttemptAt))
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by repositories, if the requested entity does not
+// exist.
+var ErrNotFound = errors.New("entity not found")
+
+// ErrInUse is returned, if an entity cannot be deleted, because other
+// entities still reference it.
+var ErrInUse = errors.New("entity is still in use")
+
+// ErrAlreadyExists is returned, if an entity would duplicate an existing
+// one, e.g. a company with the same normalised name.
+var ErrAlreadyExists = errors.New("entity already exists")
+
+// ErrStatusChanged is returned, if the status of an application changed,
+// since it was read to check a transition.
+var ErrStatusChanged = errors.New("the status of the application changed")
+
+// ApplicationRepository describes the storage of applications. Ownership is
+// modelled through Application.UserId, ids are assigned by the repository.
+type ApplicationRepository interface {
+	InsertApplication(application Application) (int, error)
+	InsertApplications(applications []Application) ([]int, error)
+	GetApplications(userId int) ([]Application, error)
+	StreamApplications(userId int, each func(Application) error) error
+	QueryApplications(userId int, query ApplicationQuery) (ApplicationPage, error)
+	SearchApplications(userId int, query string, limit int) ([]SearchResult, error)
+	GetApplication(id int) (Application, error)
+	DeleteApplication(id int) error
+	UpdateApplication(application Application) error
+	UpdateApplicationFromStatus(application Application, fromStatusId int) error
+	GetStatusHistory(applicationId int) ([]StatusChange, error)
+	MarkStaleApplications(now time.Time) ([]Application, error)
+}
+
+// TypesRepository describes the storage of the lookup types, which are
+// referenced by applications.
+type TypesRepository interface {
+	GetWorkTypes() ([]WorkType, error)
+	GetStatuses() ([]ApplicationStatus, error)
+	GetUserStatuses(userId int) ([]ApplicationStatus, error)
+	GetUsableStatuses(userId int) ([]ApplicationStatus, error)
+	GetStatus(id int) (ApplicationStatus, error)
+	InsertStatus(status ApplicationStatus) (int, error)
+	UpdateStatus(status ApplicationStatus) error
+	DeleteStatus(id int) error
+	GetWorkflow(userId int) (Workflow, error)
+	SetWorkflow(workflow Workflow) error
+}
+
+// InterviewRepository describes the storage of the interviews of
+// applications. Deleting an application deletes its interviews.
+type InterviewRepository interface {
+	InsertInterview(interview Interview) (int, error)
+	GetInterviews(applicationId int) ([]Interview, error)
+	GetUserInterviews(userId int) ([]Interview, error)
+	GetInterview(id int) (Interview, error)
+	UpdateInterview(interview Interview) error
+	DeleteInterview(id int) error
+}
+
+// ContactRepository describes the storage of the contacts of a user and
+// their links to applications.
+type ContactRepository interface {
+	InsertContact(contact Contact) (int, error)
+	GetContacts(userId int) ([]Contact, error)
+	GetContact(id int) (Contact, error)
+	UpdateContact(contact Contact) error
+	DeleteContact(id int) error
+	LinkContact(applicationId int, contactId int) error
+	UnlinkContact(applicationId int, contactId int) error
+	GetApplicationContacts(applicationId int) ([]Contact, error)
+	GetContactApplications(contactId int) ([]Application, error)
+}
+
+// CompanyRepository describes the storage of the companies of a user.
+// Inserting or renaming a company to the normalised name of another company
+// of the same user fails with ErrAlreadyExists.
+type CompanyRepository interface {
+	InsertCompany(company Company) (int, error)
+	GetCompanies(userId int) ([]Company, error)
+	GetCompany(id int) (Company, error)
+	UpdateCompany(company Company) error
+	DeleteCompany(id int) error
+}
+
+// AttachmentRepository describes the storage of the metadata of files
+// attached to applications. Deleting an application deletes the metadata of
+// its attachments, the blobs have to be deleted by the caller.
+type AttachmentRepository interface {
+	InsertAttachment(attachment Attachment) (int, error)
+	GetAttachments(applicationId int) ([]Attachment, error)
+	GetAttachment(id int) (Attachment, error)
+	DeleteAttachment(id int) error
+	GetAttachmentUsage(userId int) (int64, error)
+}
+
+// DocumentRepository describes the storage of the document library of a
+// user. Documents, of which a version is referenced by an application,
+// cannot be deleted.
+type DocumentRepository interface {
+	InsertDocument(document Document, version DocumentVersion) (int, error)
+	InsertDocumentVersion(version DocumentVersion) (int, error)
+	GetDocuments(userId int) ([]Document, error)
+	GetDocument(id int) (Document, error)
+	DeleteDocument(id int) error
+	GetDocumentVersions(documentId int) ([]DocumentVersion, error)
+	GetDocumentVersion(id int) (DocumentVersion, error)
+	GetDocumentVersionsByIds(ids []int) (map[int]DocumentVersion, error)
+	GetVersionApplications(versionId int) ([]Application, error)
+	GetDocumentUsage(userId int) (int64, error)
+}
+
+// TagRepository describes the storage of tags and their assignment to
+// applications. Deleting a tag or an application removes the assignments.
+type TagRepository interface {
+	InsertTag(tag Tag) (int, error)
+	GetTags(userId int) ([]Tag, error)
+	GetTag(id int) (Tag, error)
+	UpdateTag(tag Tag) error
+	DeleteTag(id int) error
+	TagApplications(tagId int, applicationIds []int) error
+	UntagApplications(tagId int, applicationIds []int) error
+	GetApplicationTags(applicationId int) ([]Tag, error)
+}
+
+// CustomFieldRepository describes the storage of the custom field
+// definitions of a user. The values are stored by the ApplicationRepository
+// along with the applications. Deleting a field deletes its values.
+type CustomFieldRepository interface {
+	InsertCustomField(field CustomField) (int, error)
+	GetCustomFields(userId int) ([]CustomField, error)
+	GetCustomField(id int) (CustomField, error)
+	UpdateCustomField(field CustomField) error
+	DeleteCustomField(id int) error
+	GetCustomFieldValues(applicationIds []int) (map[int]map[string]interface{}, error)
+}
+
+// ReminderRepository describes the storage of the reminders of
+// applications. Deleting an application deletes its reminders.
+type ReminderRepository interface {
+	InsertReminder(reminder Reminder) (int, error)
+	GetReminders(applicationId int) ([]Reminder, error)
+	GetUserReminders(userId int) ([]Reminder, error)
+	GetReminder(id int) (Reminder, error)
+	DeleteReminder(id int) error
+	FireDueReminders(now time.Time, limit int, fire FireFunc) (int, error)
+}
+
+// NotificationRepository describes the storage of the notification
+// preferences of the users.
+type NotificationRepository interface {
+	GetNotificationPreferences(userId int) (NotificationPreferences, error)
+	SaveNotificationPreferences(preferences NotificationPreferences) error
+	SendDueDigests(now time.Time, period time.Duration, limit int, send DigestFunc) (int, error)
+}
+
+// WebhookRepository describes the storage of the webhooks of a user and the
+// deliveries of the events written to the outbox by the
+// ApplicationRepository. Deleting a webhook deletes its deliveries.
+type WebhookRepository interface {
+	InsertWebhook(webhook Webhook) (int, error)
+	GetWebhooks(userId int) ([]Webhook, error)
+	GetWebhook(id int) (Webhook, error)
+	UpdateWebhook(webhook Webhook) error
+	DeleteWebhook(id int) error
+	GetWebhookDeliveries(webhookId int, limit int) ([]WebhookDelivery, error)
+	DispatchEvents(now time.Time, limit int) (int, error)
+	DeliverDueWebhooks(now time.Time, limit int, deliver DeliverFunc) (int, error)
+}
+
+// EventRepository describes the log of the events written to the outbox by
+// the ApplicationRepository, which is read by the event stream in the order
+// of the positions of the events. Listening blocks until the context is
+// cancelled.
+type EventRepository interface {
+	GetEventsAfter(userId int, afterPosition int64, limit int) ([]OutboxEvent, error)
+	GetLatestEventPosition() (int64, error)
+	PruneEvents(before time.Time) (int, error)
+	ListenEvents(ctx context.Context, listen ListenFunc) error
+}
+
+// CalendarRepository describes the storage of the secret tokens, which give
+// access to the calendar feeds of the users. A user has at most one token.
+type CalendarRepository interface {
+	GetCalendarToken(userId int) (string, error)
+	GetCalendarTokenUser(token string) (int, error)
+	SaveCalendarToken(userId int, token string) error
+	DeleteCalendarToken(userId int) error
+}
+
+// PostingRepository describes the storage of the snapshots of the job
+// postings fetched by the users. The contents are kept in a blob store.
+type PostingRepository interface {
+	InsertPostingSnapshot(snapshot PostingSnapshot) (int, error)
+	GetPostingSnapshots(userId int, url string) ([]PostingSnapshot, error)
+	GetPostingSnapshot(id int) (PostingSnapshot, error)
+	GetPostingSnapshotUsage(userId int) (int64, error)
+}
+
+// AccountRepository describes the data stored for a user across all
+// repositories. The export contains the rows of the user by table, the
+// erasure deletes them in a single transaction and records it in the audit
+// log, which is kept.
+type AccountRepository interface {
+	ExportUserData(userId int) ([]UserTable, error)
+	EraseUserData(userId int) (ErasedUser, error)
+}
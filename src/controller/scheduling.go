@@ -0,0 +1,32 @@
+package controller
+
+import "time"
+
+const (
+	// schedulingLease is the time, for which a scheduler claims the
+	// reminders and digests it sends. They are sent outside of a
+	// transaction, so they are sent again by another scheduler, if the
+	// scheduler stopped before their outcome was stored.
+	schedulingLease = 15 * time.Minute
+
+	// initialRetryBackoff is the delay before the second attempt to send a
+	// reminder or a digest. It doubles with every further attempt.
+	initialRetryBackoff = time.Minute
+
+	// maxRetryBackoff limits the delay between two attempts.
+	maxRetryBackoff = 6 * time.Hour
+)
+
+// retryBackoff returns the delay after the given number of failed attempts.
+func retryBackoff(attempts int) time.Duration {
+	backoff := initialRetryBackoff
+	for i := 1; i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return backoff
+}
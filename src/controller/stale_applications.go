@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// staleCandidate is an application, which has reached the stale threshold of
+// its status.
+type staleCandidate struct {
+	id            int
+	userId        int
+	statusId      int
+	staleStatusId *int
+}
+
+// MarkStaleApplications marks the applications, which have been in a
+// non-terminal status for at least the stale threshold of the status, as
+// stale. Applications, whose status has a stale status, are moved to that
+// status instead, which is published like a status change by the owner. If
+// the workflow of the owner does not allow the move without reopening the
+// application, it is marked as stale. The flag of applications below the
+// threshold is cleared.
+// It returns the applications, which became stale or were moved. The
+// applications are locked while they are processed and locked applications
+// are skipped, so concurrently running schedulers process every application
+// only once.
+func (c ApplicationController) MarkStaleApplications(now time.Time) ([]Application, error) {
+	var ids []int
+	err := c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(c.Context,
+			`UPDATE application SET stale = false
+			 FROM application_status s
+			 WHERE s.id = application.status_id AND application.stale
+			   AND (s.terminal OR s.stale_after_days IS NULL OR application.status_changed_at > $1 - s.stale_after_days * interval '1 day')`,
+			now); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(c.Context,
+			`SELECT a.id, a.user_id, a.status_id,
+			   CASE WHEN NOT EXISTS (SELECT 1 FROM status_transition t WHERE t.user_id = a.user_id)
+			          OR EXISTS (SELECT 1 FROM status_transition t
+			                     WHERE t.user_id = a.user_id AND t.from_status_id = a.status_id
+			                       AND t.to_status_id = s.stale_status_id AND NOT t.requires_reopen)
+			        THEN s.stale_status_id END
+			 FROM application a JOIN application_status s ON s.id = a.status_id
+			 WHERE NOT a.stale AND NOT s.terminal AND s.stale_after_days IS NOT NULL
+			   AND a.status_changed_at <= $1 - s.stale_after_days * interval '1 day'
+			 ORDER BY a.id
+			 FOR UPDATE OF a SKIP LOCKED`, now)
+		if err != nil {
+			return err
+		}
+
+		var candidates []staleCandidate
+		for rows.Next() {
+			var candidate staleCandidate
+			if err := rows.Scan(&candidate.id, &candidate.userId, &candidate.statusId, &candidate.staleStatusId); err != nil {
+				rows.Close()
+				return err
+			}
+
+			candidates = append(candidates, candidate)
+		}
+
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, candidate := range candidates {
+			if candidate.staleStatusId == nil {
+				if _, err := tx.Exec(c.Context, "UPDATE application SET stale = true WHERE id = $1", candidate.id); err != nil {
+					return err
+				}
+			} else {
+				if _, err := tx.Exec(c.Context,
+					"UPDATE application SET status_id = $2, status_changed_at = $3, stale = false WHERE id = $1",
+					candidate.id, *candidate.staleStatusId, now); err != nil {
+					return err
+				}
+
+				// The history has no system user, so the owner is
+				// recorded as the acting user.
+				if _, err := tx.Exec(c.Context,
+					"INSERT INTO application_status_history (application_id, old_status_id, new_status_id, changed_at, changed_by) VALUES ($1, $2, $3, $4, $5)",
+					candidate.id, candidate.statusId, *candidate.staleStatusId, now, candidate.userId); err != nil {
+					return err
+				}
+
+				if err := enqueueApplicationEvent(c.Context, tx, EventApplicationUpdated, candidate.id, nil); err != nil {
+					return err
+				}
+
+				if err := enqueueApplicationEvent(c.Context, tx, EventApplicationStatusChanged, candidate.id, &candidate.statusId); err != nil {
+					return err
+				}
+			}
+
+			ids = append(ids, candidate.id)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var applications []Application
+	for _, id := range ids {
+		application, err := c.GetApplication(id)
+		if err != nil {
+			return nil, err
+		}
+
+		applications = append(applications, application)
+	}
+
+	return applications, nil
+}
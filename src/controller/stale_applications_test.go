@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkStaleApplications(t *testing.T) {
+	controller.CreateScheme()
+	types := TypesController{Database: controller.Database, Context: controller.Context}
+
+	userId := testApplication.UserId
+	ghostedId, err := types.InsertStatus(ApplicationStatus{Name: "Ghosted", UserId: &userId, Terminal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	days := 10
+	waitingId, err := types.InsertStatus(ApplicationStatus{Name: "Waiting", UserId: &userId, StaleAfterDays: &days, StaleStatusId: &ghostedId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	stale := testApplication
+	stale.StatusId = 2
+	stale.SubmissionDate = now.AddDate(0, 0, -40)
+	staleId, _ := controller.InsertApplication(stale)
+
+	fresh := testApplication
+	fresh.StatusId = 2
+	fresh.SubmissionDate = now.AddDate(0, 0, -5)
+	freshId, _ := controller.InsertApplication(fresh)
+
+	moved := testApplication
+	moved.StatusId = waitingId
+	moved.SubmissionDate = now.AddDate(0, 0, -12)
+	movedId, _ := controller.InsertApplication(moved)
+
+	marked, err := controller.MarkStaleApplications(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(marked))
+	assert.Equal(t, staleId, marked[0].Id)
+	assert.True(t, marked[0].Stale)
+	assert.Equal(t, movedId, marked[1].Id)
+	assert.Equal(t, ghostedId, marked[1].StatusId)
+
+	application, _ := controller.GetApplication(freshId)
+	assert.False(t, application.Stale)
+
+	marked, err = controller.MarkStaleApplications(now)
+	assert.Nil(t, err)
+	assert.Empty(t, marked)
+
+	page, err := controller.QueryApplications(userId, ApplicationQuery{Stale: &[]bool{true}[0]})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.Total)
+
+	application, _ = controller.GetApplication(staleId)
+	application.StatusId = 3
+	assert.Nil(t, controller.UpdateApplication(application))
+
+	application, _ = controller.GetApplication(staleId)
+	assert.False(t, application.Stale)
+	assert.WithinDuration(t, time.Now(), application.StatusChangedAt, time.Minute)
+}
+
+func TestMarkStaleApplicationsRespectsWorkflow(t *testing.T) {
+	controller.CreateScheme()
+	types := TypesController{Database: controller.Database, Context: controller.Context}
+
+	userId := testApplication.UserId
+	ghostedId, _ := types.InsertStatus(ApplicationStatus{Name: "Ghosted", UserId: &userId, Terminal: true})
+	days := 10
+	waitingId, err := types.InsertStatus(ApplicationStatus{Name: "Waiting", UserId: &userId, StaleAfterDays: &days, StaleStatusId: &ghostedId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The workflow only allows to move waiting applications to declined.
+	assert.Nil(t, types.SetWorkflow(Workflow{UserId: userId, Transitions: []StatusTransition{{FromStatusId: waitingId, ToStatusId: 3}}}))
+
+	now := time.Now()
+	waiting := testApplication
+	waiting.StatusId = waitingId
+	waiting.SubmissionDate = now.AddDate(0, 0, -12)
+	waitingApplicationId, _ := controller.InsertApplication(waiting)
+
+	marked, err := controller.MarkStaleApplications(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(marked))
+	assert.Equal(t, waitingApplicationId, marked[0].Id)
+	assert.Equal(t, waitingId, marked[0].StatusId)
+	assert.True(t, marked[0].Stale)
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Tag groups the applications of a user. Tag names are unique per user
+// regardless of case.
+type Tag struct {
+	Id     int    `db:"id" json:"id"`
+	UserId int    `db:"user_id" json:"userId"`
+	Name   string `db:"name" json:"name"`
+	Color  string `db:"color" json:"color"`
+}
+
+type TagController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const tagColumns = "id, user_id, name, color"
+
+func scanTag(row pgx.Row) (Tag, error) {
+	var tag Tag
+	err := row.Scan(&tag.Id, &tag.UserId, &tag.Name, &tag.Color)
+
+	return tag, err
+}
+
+func (c TagController) queryTags(sql string, args ...interface{}) ([]Tag, error) {
+	rows, err := c.Database.Query(c.Context, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (c TagController) InsertTag(tag Tag) (int, error) {
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		"INSERT INTO tag (user_id, name, color) VALUES ($1, $2, $3) RETURNING id",
+		tag.UserId, tag.Name, tag.Color).Scan(&id)
+
+	if err != nil {
+		return -1, mapUniqueViolation(err)
+	}
+
+	return id, nil
+}
+
+func (c TagController) GetTags(userId int) ([]Tag, error) {
+	return c.queryTags("SELECT "+tagColumns+" FROM tag WHERE user_id = $1 ORDER BY lower(name), id", userId)
+}
+
+func (c TagController) GetTag(id int) (Tag, error) {
+	tag, err := scanTag(c.Database.QueryRow(c.Context, "SELECT "+tagColumns+" FROM tag WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Tag{}, ErrNotFound
+	}
+
+	return tag, err
+}
+
+// UpdateTag renames or recolours the tag. The owner cannot be changed.
+func (c TagController) UpdateTag(tag Tag) error {
+	result, err := c.Database.Exec(c.Context, "UPDATE tag SET name = $2, color = $3 WHERE id = $1", tag.Id, tag.Name, tag.Color)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteTag deletes the tag and removes it from all applications.
+func (c TagController) DeleteTag(id int) error {
+	result, err := c.Database.Exec(c.Context, "DELETE FROM tag WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// TagApplications adds the tag to all given applications. Applications,
+// which already have the tag, are skipped.
+func (c TagController) TagApplications(tagId int, applicationIds []int) error {
+	_, err := c.Database.Exec(c.Context,
+		`INSERT INTO application_tag (application_id, tag_id)
+		 SELECT application_id, $1 FROM unnest($2::INTEGER[]) AS application_id
+		 ON CONFLICT DO NOTHING`,
+		tagId, applicationIds)
+
+	return err
+}
+
+// UntagApplications removes the tag from all given applications.
+func (c TagController) UntagApplications(tagId int, applicationIds []int) error {
+	_, err := c.Database.Exec(c.Context,
+		"DELETE FROM application_tag WHERE tag_id = $1 AND application_id = ANY($2)",
+		tagId, applicationIds)
+
+	return err
+}
+
+func (c TagController) GetApplicationTags(applicationId int) ([]Tag, error) {
+	return c.queryTags(
+		`SELECT `+tagColumns+` FROM tag
+		 WHERE id IN (SELECT tag_id FROM application_tag WHERE application_id = $1)
+		 ORDER BY lower(name), id`,
+		applicationId)
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	assert.Equal(t, []string{"go", "berlin"}, ParseTags(" Go,berlin,,GO "))
+	assert.Nil(t, ParseTags(""))
+}
+
+func TestTagApplications(t *testing.T) {
+	controller.CreateScheme()
+	tags := TagController{Database: controller.Database, Context: controller.Context}
+
+	firstId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondId, err := controller.InsertApplication(testApplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goId, err := tags.InsertTag(Tag{UserId: testApplication.UserId, Name: "Go", Color: "#00add8"})
+	assert.Nil(t, err)
+
+	_, err = tags.InsertTag(Tag{UserId: testApplication.UserId, Name: "GO"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+
+	berlinId, err := tags.InsertTag(Tag{UserId: testApplication.UserId, Name: "Berlin"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, tags.TagApplications(goId, []int{firstId, secondId}))
+	assert.Nil(t, tags.TagApplications(goId, []int{firstId}))
+	assert.Nil(t, tags.TagApplications(berlinId, []int{secondId}))
+
+	page, err := controller.QueryApplications(testApplication.UserId, ApplicationQuery{Tags: []string{"go", "berlin"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, page.Total)
+
+	page, err = controller.QueryApplications(testApplication.UserId, ApplicationQuery{Tags: []string{"go", "berlin"}, MatchAllTags: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.Total)
+	assert.Equal(t, secondId, page.Applications[0].Id)
+
+	assert.Nil(t, tags.UntagApplications(goId, []int{secondId}))
+	applicationTags, err := tags.GetApplicationTags(secondId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(applicationTags))
+	assert.Equal(t, "Berlin", applicationTags[0].Name)
+
+	assert.Nil(t, tags.DeleteTag(berlinId))
+	assert.ErrorIs(t, tags.DeleteTag(berlinId), ErrNotFound)
+
+	applicationTags, _ = tags.GetApplicationTags(secondId)
+	assert.Empty(t, applicationTags)
+}
@@ -2,7 +2,9 @@ package controller
 
 import (
 	"context"
+	"errors"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -11,9 +13,25 @@ type WorkType struct {
 	Name string `db:"name"`
 }
 
+// ApplicationStatus is a stage of the application pipeline. Statuses without
+// a user are the global defaults. Terminal statuses end the pipeline, e.g.
+// an offer or a rejection.
 type ApplicationStatus struct {
-	Id   int    `db:"id"`
-	Name string `db:"name"`
+	Id       int    `db:"id" json:"id"`
+	Name     string `db:"name" json:"name"`
+	UserId   *int   `db:"user_id" json:"userId"`
+	Position int    `db:"position" json:"position"`
+	Color    string `db:"color" json:"color"`
+	Terminal bool   `db:"terminal" json:"terminal"`
+
+	// Applications, which stay in the status for StaleAfterDays, are
+	// marked as stale or, if StaleStatusId is set, moved to that status.
+	StaleAfterDays *int `db:"stale_after_days" json:"staleAfterDays"`
+	StaleStatusId  *int `db:"stale_status_id" json:"staleStatusId"`
+
+	// The start dates of applications in an accepted status are shown in
+	// the calendar feed.
+	Accepted bool `db:"accepted" json:"accepted"`
 }
 
 type TypesController struct {
@@ -35,47 +53,11 @@ func NewTypesController(dbConfig DbConfig) (TypesController, error) {
 	return controller, nil
 }
 
-// TODO: Code duplication (see ApplicationController.CreateScheme). Refactor it,
-// so both structs share the same logic.
-func (c TypesController) CreateScheme() {
-	queries := []string{
-		`DROP TABLE IF EXISTS work_type CASCADE`,
-		`CREATE TABLE work_type (
-				id SERIAL PRIMARY KEY NOT NULL,
-				name VARCHAR(255) NOT NULL)`,
-		`DROP TABLE IF EXISTS application_status CASCADE`,
-		`CREATE TABLE application_status (
-				id SERIAL PRIMARY KEY NOT NULL,
-				name VARCHAR(255) NOT NULL)`,
-		`DROP TABLE IF EXISTS application CASCADE`,
-		`CREATE TABLE application (
-				id SERIAL PRIMARY KEY NOT NULL,
-				user_id INTEGER NOT NULL,
-				job_title VARCHAR(255) NOT NULL,
-				work_type_id INTEGER NOT NULL,
-				company_name VARCHAR(255) NOT NULL,
-				submission_date DATE,
-				status_id INTEGER NOT NULL,
-				wanted_salary REAL,
-				accepted_salary REAL,
-				start_date DATE,
-				commentary VARCHAR(500),
-
-				FOREIGN KEY (work_type_id) REFERENCES work_type (id)
-					ON DELETE SET DEFAULT,
-				FOREIGN KEY (status_id) REFERENCES application_status (id)
-					ON DELETE SET DEFAULT)`,
-		`INSERT INTO work_type (name) VALUES ('Remote')`,
-		`INSERT INTO work_type (name) VALUES ('OnSite')`,
-		`INSERT INTO work_type (name) VALUES ('Hybrid')`,
-		`INSERT INTO application_status (name) VALUES ('Accepted')`,
-		`INSERT INTO application_status (name) VALUES ('Pending')`,
-		`INSERT INTO application_status (name) VALUES ('Declined')`,
-	}
-
-	for _, query := range queries {
-		c.Database.Exec(c.Context, query)
-	}
+// CreateScheme resets the database to a clean schema by rolling back and
+// re-applying all migrations. All data is lost, so it is only meant for
+// tests. Production databases are migrated with migrations.Migrator.Up.
+func (c TypesController) CreateScheme() error {
+	return resetScheme(c.Database)
 }
 
 func (c TypesController) GetWorkTypes() ([]WorkType, error) {
@@ -96,8 +78,17 @@ func (c TypesController) GetWorkTypes() ([]WorkType, error) {
 	return workTypes, nil
 }
 
-func (c TypesController) GetStatuses() ([]ApplicationStatus, error) {
-	rows, err := c.Database.Query(c.Context, "SELECT * FROM application_status")
+const statusColumns = "id, name, user_id, position, color, terminal, stale_after_days, stale_status_id, accepted"
+
+func scanStatus(row pgx.Row) (ApplicationStatus, error) {
+	var status ApplicationStatus
+	err := row.Scan(&status.Id, &status.Name, &status.UserId, &status.Position, &status.Color, &status.Terminal,
+		&status.StaleAfterDays, &status.StaleStatusId, &status.Accepted)
+	return status, err
+}
+
+func (c TypesController) queryStatuses(sql string, args ...interface{}) ([]ApplicationStatus, error) {
+	rows, err := c.Database.Query(c.Context, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -106,10 +97,148 @@ func (c TypesController) GetStatuses() ([]ApplicationStatus, error) {
 
 	var statuses []ApplicationStatus
 	for rows.Next() {
-		var status ApplicationStatus
-		rows.Scan(&status.Id, &status.Name)
+		status, err := scanStatus(rows)
+		if err != nil {
+			return nil, err
+		}
+
 		statuses = append(statuses, status)
 	}
 
-	return statuses, nil
+	return statuses, rows.Err()
+}
+
+// GetStatuses returns the global default statuses.
+func (c TypesController) GetStatuses() ([]ApplicationStatus, error) {
+	return c.queryStatuses("SELECT " + statusColumns + " FROM application_status WHERE user_id IS NULL ORDER BY position, id")
+}
+
+// GetUserStatuses returns the statuses defined by the user. Users, who did
+// not define any statuses, get the global defaults.
+func (c TypesController) GetUserStatuses(userId int) ([]ApplicationStatus, error) {
+	return c.queryStatuses(
+		`SELECT `+statusColumns+` FROM application_status
+		 WHERE user_id = $1
+		    OR (user_id IS NULL AND NOT EXISTS (SELECT 1 FROM application_status WHERE user_id = $1))
+		 ORDER BY position, id`, userId)
+}
+
+// GetUsableStatuses returns the statuses defined by the user and the global
+// defaults, which applications of the user may still be in after the user
+// defined own statuses. The statuses of the user come first, so they win
+// over global statuses with the same name.
+func (c TypesController) GetUsableStatuses(userId int) ([]ApplicationStatus, error) {
+	return c.queryStatuses(
+		`SELECT `+statusColumns+` FROM application_status
+		 WHERE user_id IS NULL OR user_id = $1
+		 ORDER BY user_id IS NULL, position, id`, userId)
+}
+
+func (c TypesController) GetStatus(id int) (ApplicationStatus, error) {
+	status, err := scanStatus(c.Database.QueryRow(c.Context, "SELECT "+statusColumns+" FROM application_status WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ApplicationStatus{}, ErrNotFound
+	}
+
+	return status, err
+}
+
+func (c TypesController) InsertStatus(status ApplicationStatus) (int, error) {
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		"INSERT INTO application_status (name, user_id, position, color, terminal, stale_after_days, stale_status_id, accepted) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
+		status.Name, status.UserId, status.Position, status.Color, status.Terminal, status.StaleAfterDays, status.StaleStatusId, status.Accepted).Scan(&id)
+
+	return id, err
+}
+
+// UpdateStatus renames, reorders or recolours a status and changes its stale
+// threshold and whether it is accepted. The owner of a status cannot be
+// changed.
+func (c TypesController) UpdateStatus(status ApplicationStatus) error {
+	tag, err := c.Database.Exec(c.Context,
+		`UPDATE application_status
+		 SET name = $2, position = $3, color = $4, terminal = $5, stale_after_days = $6, stale_status_id = $7, accepted = $8
+		 WHERE id = $1`,
+		status.Id, status.Name, status.Position, status.Color, status.Terminal, status.StaleAfterDays, status.StaleStatusId, status.Accepted)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteStatus deletes a status, which is neither used by an application nor
+// by the status history.
+func (c TypesController) DeleteStatus(id int) error {
+	var inUse bool
+	if err := c.Database.QueryRow(c.Context,
+		`SELECT EXISTS (SELECT 1 FROM application WHERE status_id = $1)
+		     OR EXISTS (SELECT 1 FROM application_status_history WHERE old_status_id = $1 OR new_status_id = $1)`,
+		id).Scan(&inUse); err != nil {
+		return err
+	}
+
+	if inUse {
+		return ErrInUse
+	}
+
+	tag, err := c.Database.Exec(c.Context, "DELETE FROM application_status WHERE id = $1", id)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (c TypesController) GetWorkflow(userId int) (Workflow, error) {
+	rows, err := c.Database.Query(c.Context,
+		"SELECT from_status_id, to_status_id, requires_reopen FROM status_transition WHERE user_id = $1 ORDER BY id", userId)
+	if err != nil {
+		return Workflow{}, err
+	}
+
+	defer rows.Close()
+
+	workflow := Workflow{UserId: userId}
+	for rows.Next() {
+		var transition StatusTransition
+		if err := rows.Scan(&transition.FromStatusId, &transition.ToStatusId, &transition.RequiresReopen); err != nil {
+			return Workflow{}, err
+		}
+
+		workflow.Transitions = append(workflow.Transitions, transition)
+	}
+
+	return workflow, rows.Err()
+}
+
+// SetWorkflow replaces all transitions of the user with the transitions of
+// the workflow.
+func (c TypesController) SetWorkflow(workflow Workflow) error {
+	return c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(c.Context, "DELETE FROM status_transition WHERE user_id = $1", workflow.UserId); err != nil {
+			return err
+		}
+
+		for _, transition := range workflow.Transitions {
+			if _, err := tx.Exec(c.Context,
+				`INSERT INTO status_transition (user_id, from_status_id, to_status_id, requires_reopen) VALUES ($1, $2, $3, $4)
+				 ON CONFLICT (user_id, from_status_id, to_status_id) DO UPDATE SET requires_reopen = excluded.requires_reopen`,
+				workflow.UserId, transition.FromStatusId, transition.ToStatusId, transition.RequiresReopen); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
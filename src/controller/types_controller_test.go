@@ -119,3 +119,132 @@ func TestGetStatusesNoRelationError(t *testing.T) {
 
 	assert.NotNil(t, err)
 }
+
+func TestGetUserStatuses(t *testing.T) {
+	controller, err := NewTypesController(DbConfig{
+		Host:     "localhost",
+		Port:     5432,
+		Username: "test",
+		Password: "test",
+		Database: "test",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller.CreateScheme()
+	userId := 1
+
+	statuses, err := controller.GetUserStatuses(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(statuses))
+
+	id, err := controller.InsertStatus(ApplicationStatus{Name: "Applied", UserId: &userId, Color: "#ff0000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err = controller.GetUserStatuses(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(statuses))
+	assert.Equal(t, id, statuses[0].Id)
+
+	statuses, err = controller.GetStatuses()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(statuses))
+}
+
+func TestGetUsableStatuses(t *testing.T) {
+	controller, err := NewTypesController(DbConfig{
+		Host:     "localhost",
+		Port:     5432,
+		Username: "test",
+		Password: "test",
+		Database: "test",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller.CreateScheme()
+	userId := 1
+	otherId := 2
+
+	id, err := controller.InsertStatus(ApplicationStatus{Name: "Pending", UserId: &userId, Color: "#ff0000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := controller.InsertStatus(ApplicationStatus{Name: "Interview", UserId: &otherId, Color: "#ff0000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := controller.GetUsableStatuses(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(statuses))
+	assert.Equal(t, id, statuses[0].Id)
+	for _, status := range statuses[1:] {
+		assert.Nil(t, status.UserId)
+	}
+}
+
+func TestDeleteStatus(t *testing.T) {
+	controller, err := NewTypesController(DbConfig{
+		Host:     "localhost",
+		Port:     5432,
+		Username: "test",
+		Password: "test",
+		Database: "test",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller.CreateScheme()
+	userId := 1
+
+	id, err := controller.InsertStatus(ApplicationStatus{Name: "Applied", UserId: &userId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.ErrorIs(t, controller.DeleteStatus(1), ErrInUse)
+	assert.Nil(t, controller.DeleteStatus(id))
+	assert.ErrorIs(t, controller.DeleteStatus(id), ErrNotFound)
+}
+
+func TestSetWorkflow(t *testing.T) {
+	controller, err := NewTypesController(DbConfig{
+		Host:     "localhost",
+		Port:     5432,
+		Username: "test",
+		Password: "test",
+		Database: "test",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller.CreateScheme()
+
+	transitions := []StatusTransition{
+		{FromStatusId: 2, ToStatusId: 3},
+		{FromStatusId: 3, ToStatusId: 2, RequiresReopen: true},
+	}
+
+	assert.Nil(t, controller.SetWorkflow(Workflow{UserId: 1, Transitions: transitions}))
+
+	workflow, err := controller.GetWorkflow(1)
+	assert.Nil(t, err)
+	assert.Equal(t, transitions, workflow.Transitions)
+
+	assert.Nil(t, controller.SetWorkflow(Workflow{UserId: 1}))
+
+	workflow, err = controller.GetWorkflow(1)
+	assert.Nil(t, err)
+	assert.Empty(t, workflow.Transitions)
+}
@@ -0,0 +1,304 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Webhook receives the events of the applications of a user, to which it
+// is subscribed. The secret signs the deliveries.
+type Webhook struct {
+	Id        int       `db:"id" json:"id"`
+	UserId    int       `db:"user_id" json:"userId"`
+	Url       string    `db:"url" json:"url"`
+	Secret    string    `db:"secret" json:"secret,omitempty"`
+	Events    []string  `db:"events" json:"events"`
+	Active    bool      `db:"active" json:"active"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// The states of a delivery. Pending deliveries are attempted again at
+// NextAttemptAt.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook. It keeps the
+// outcome of the latest attempt.
+type WebhookDelivery struct {
+	Id             int64           `db:"id" json:"id"`
+	WebhookId      int             `db:"webhook_id" json:"webhookId"`
+	EventId        int64           `db:"event_id" json:"eventId"`
+	Event          string          `db:"event" json:"event"`
+	Payload        json.RawMessage `db:"payload" json:"payload"`
+	EventCreatedAt time.Time       `db:"event_created_at" json:"eventCreatedAt"`
+	Status         string          `db:"status" json:"status"`
+	Attempts       int             `db:"attempts" json:"attempts"`
+	NextAttemptAt  *time.Time      `db:"next_attempt_at" json:"nextAttemptAt"`
+	LastAttemptAt  *time.Time      `db:"last_attempt_at" json:"lastAttemptAt"`
+	ResponseStatus *int            `db:"response_status" json:"responseStatus"`
+	Error          string          `db:"error" json:"error"`
+	CreatedAt      time.Time       `db:"created_at" json:"createdAt"`
+}
+
+// DeliverFunc attempts a delivery and returns it with the outcome of the
+// attempt, i.e. its new status, attempts, next attempt and response.
+type DeliverFunc func(webhook Webhook, delivery WebhookDelivery) WebhookDelivery
+
+type WebhookController struct {
+	Database *pgxpool.Pool
+	Context  context.Context
+}
+
+const webhookColumns = "id, user_id, url, secret, events, active, created_at"
+
+func webhookTargets(w *Webhook) []interface{} {
+	return []interface{}{&w.Id, &w.UserId, &w.Url, &w.Secret, &w.Events, &w.Active, &w.CreatedAt}
+}
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, e.event, e.payload, e.created_at, d.status, d.attempts,
+	d.next_attempt_at, d.last_attempt_at, d.response_status, d.error, d.created_at`
+
+func deliveryTargets(d *WebhookDelivery) []interface{} {
+	return []interface{}{&d.Id, &d.WebhookId, &d.EventId, &d.Event, &d.Payload, &d.EventCreatedAt, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastAttemptAt, &d.ResponseStatus, &d.Error, &d.CreatedAt}
+}
+
+func (c WebhookController) InsertWebhook(webhook Webhook) (int, error) {
+	id := -1
+	err := c.Database.QueryRow(c.Context,
+		"INSERT INTO webhook (user_id, url, secret, events, active) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		webhook.UserId, webhook.Url, webhook.Secret, webhook.Events, webhook.Active).Scan(&id)
+
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (c WebhookController) GetWebhooks(userId int) ([]Webhook, error) {
+	rows, err := c.Database.Query(c.Context, "SELECT "+webhookColumns+" FROM webhook WHERE user_id = $1 ORDER BY id", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []Webhook
+	for rows.Next() {
+		var webhook Webhook
+		if err := rows.Scan(webhookTargets(&webhook)...); err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (c WebhookController) GetWebhook(id int) (Webhook, error) {
+	var webhook Webhook
+	err := c.Database.QueryRow(c.Context, "SELECT "+webhookColumns+" FROM webhook WHERE id = $1", id).Scan(webhookTargets(&webhook)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Webhook{}, ErrNotFound
+	}
+
+	return webhook, err
+}
+
+// UpdateWebhook changes the url, the events and the activation of the
+// webhook. The secret is kept.
+func (c WebhookController) UpdateWebhook(webhook Webhook) error {
+	result, err := c.Database.Exec(c.Context, "UPDATE webhook SET url = $2, events = $3, active = $4 WHERE id = $1",
+		webhook.Id, webhook.Url, webhook.Events, webhook.Active)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes the webhook and its deliveries.
+func (c WebhookController) DeleteWebhook(id int) error {
+	result, err := c.Database.Exec(c.Context, "DELETE FROM webhook WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries returns the latest deliveries of the webhook, newest
+// first.
+func (c WebhookController) GetWebhookDeliveries(webhookId int, limit int) ([]WebhookDelivery, error) {
+	rows, err := c.Database.Query(c.Context,
+		`SELECT `+deliveryColumns+`
+		 FROM webhook_delivery d JOIN outbox_event e ON e.id = d.event_id
+		 WHERE d.webhook_id = $1
+		 ORDER BY d.id DESC
+		 LIMIT $2`, webhookId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		var delivery WebhookDelivery
+		if err := rows.Scan(deliveryTargets(&delivery)...); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// DispatchEvents creates a pending delivery of up to limit events from the
+// outbox for every active webhook of the user, which is subscribed to the
+// event, and returns the number of dispatched events. Like reminders, the
+// events are locked while they are dispatched, so every event is dispatched
+// by only one scheduler.
+func (c WebhookController) DispatchEvents(now time.Time, limit int) (int, error) {
+	dispatched := 0
+	err := c.Database.BeginFunc(c.Context, func(tx pgx.Tx) error {
+		rows, err := tx.Query(c.Context,
+			`SELECT id FROM outbox_event
+			 WHERE dispatched_at IS NULL
+			 ORDER BY id
+			 LIMIT $1
+			 FOR UPDATE SKIP LOCKED`, limit)
+		if err != nil {
+			return err
+		}
+
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+
+			ids = append(ids, id)
+		}
+
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if _, err := tx.Exec(c.Context,
+				`INSERT INTO webhook_delivery (webhook_id, event_id, next_attempt_at)
+				 SELECT w.id, e.id, $2 FROM outbox_event e JOIN webhook w ON w.user_id = e.user_id
+				 WHERE e.id = $1 AND w.active AND e.event = ANY(w.events)`, id, now); err != nil {
+				return err
+			}
+
+			if _, err := tx.Exec(c.Context, "UPDATE outbox_event SET dispatched_at = $2 WHERE id = $1", id, now); err != nil {
+				return err
+			}
+
+			dispatched++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return dispatched, nil
+}
+
+// deliveryLease is the time, for which a scheduler claims the deliveries it
+// attempts. It covers the attempts of a batch, which are limited by the
+// timeout of the sender.
+const deliveryLease = 15 * time.Minute
+
+// DeliverDueWebhooks attempts up to limit pending deliveries of active
+// webhooks, which are due at the given time, stores their outcome and
+// returns the number of attempted deliveries. The deliveries are claimed for
+// deliveryLease before they are attempted, so every attempt is made by only
+// one scheduler, while no transaction is kept open during the requests to
+// the webhooks.
+func (c WebhookController) DeliverDueWebhooks(now time.Time, limit int, deliver DeliverFunc) (int, error) {
+	rows, err := c.Database.Query(c.Context,
+		`WITH claimed AS (
+			UPDATE webhook_delivery SET locked_until = $3
+			WHERE id IN (
+				SELECT d.id FROM webhook_delivery d
+				JOIN webhook w ON w.id = d.webhook_id
+				WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.active
+				  AND (d.locked_until IS NULL OR d.locked_until <= $1)
+				ORDER BY d.next_attempt_at, d.id
+				LIMIT $2
+				FOR UPDATE OF d SKIP LOCKED)
+			RETURNING *)
+		 SELECT `+deliveryColumns+`, w.id, w.user_id, w.url, w.secret, w.events, w.active, w.created_at
+		 FROM claimed d
+		 JOIN outbox_event e ON e.id = d.event_id
+		 JOIN webhook w ON w.id = d.webhook_id
+		 ORDER BY d.next_attempt_at, d.id`, now, limit, now.Add(deliveryLease))
+	if err != nil {
+		return 0, err
+	}
+
+	var deliveries []WebhookDelivery
+	var webhooks []Webhook
+	for rows.Next() {
+		var delivery WebhookDelivery
+		var webhook Webhook
+		if err := rows.Scan(append(deliveryTargets(&delivery), webhookTargets(&webhook)...)...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		deliveries = append(deliveries, delivery)
+		webhooks = append(webhooks, webhook)
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for i, delivery := range deliveries {
+		delivery = deliver(webhooks[i], delivery)
+
+		_, err := c.Database.Exec(c.Context,
+			`UPDATE webhook_delivery
+			 SET status = $2, attempts = $3, next_attempt_at = $4, last_attempt_at = $5, response_status = $6, error = $7,
+			     locked_until = NULL
+			 WHERE id = $1`,
+			delivery.Id, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastAttemptAt,
+			delivery.ResponseStatus, delivery.Error)
+		if err != nil {
+			return attempted, err
+		}
+
+		attempted++
+	}
+
+	return attempted, nil
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookOutbox(t *testing.T) {
+	controller.CreateScheme()
+	webhooks := WebhookController{Database: controller.Database, Context: controller.Context}
+
+	webhookId, err := webhooks.InsertWebhook(Webhook{
+		UserId: testApplication.UserId,
+		Url:    "https://example.com/hook",
+		Secret: "secret",
+		Events: []string{EventApplicationCreated, EventApplicationStatusChanged, EventApplicationDeleted},
+		Active: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := testApplication
+	application.StatusId = 2
+	id, err := controller.InsertApplication(application)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application.Id = id
+	application.StatusId = 1
+	assert.Nil(t, controller.UpdateApplication(application))
+	assert.Nil(t, controller.DeleteApplication(id))
+	assert.ErrorIs(t, controller.DeleteApplication(id), ErrNotFound)
+
+	// The updated event has no subscriber.
+	now := time.Now()
+	dispatched, err := webhooks.DispatchEvents(now, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, dispatched)
+
+	var events []string
+	attempted, err := webhooks.DeliverDueWebhooks(now, 10, func(webhook Webhook, delivery WebhookDelivery) WebhookDelivery {
+		assert.Equal(t, webhookId, webhook.Id)
+		events = append(events, delivery.Event)
+
+		// The claim is committed before the attempt, so other schedulers
+		// skip the delivery.
+		claimed, err := webhooks.DeliverDueWebhooks(now, 10, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, claimed)
+
+		status := 200
+		delivery.Status = DeliverySucceeded
+		delivery.Attempts++
+		delivery.LastAttemptAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.ResponseStatus = &status
+		return delivery
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempted)
+	assert.Equal(t, []string{EventApplicationCreated, EventApplicationStatusChanged, EventApplicationDeleted}, events)
+
+	deliveries, err := webhooks.GetWebhookDeliveries(webhookId, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(deliveries))
+	assert.Equal(t, EventApplicationDeleted, deliveries[0].Event)
+	assert.Equal(t, DeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, 200, *deliveries[0].ResponseStatus)
+
+	dispatched, _ = webhooks.DispatchEvents(now, 10)
+	assert.Equal(t, 0, dispatched)
+}
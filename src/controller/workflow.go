@@ -0,0 +1,46 @@
+package controller
+
+import "errors"
+
+// ErrTransitionNotAllowed is returned, if the workflow of a user does not
+// contain a transition between two statuses.
+var ErrTransitionNotAllowed = errors.New("the status transition is not allowed")
+
+// ErrReopenRequired is returned, if a transition is only allowed, when the
+// application is reopened explicitly.
+var ErrReopenRequired = errors.New("the status transition requires to reopen the application")
+
+type StatusTransition struct {
+	FromStatusId   int  `db:"from_status_id" json:"fromStatusId"`
+	ToStatusId     int  `db:"to_status_id" json:"toStatusId"`
+	RequiresReopen bool `db:"requires_reopen" json:"requiresReopen"`
+}
+
+// Workflow defines the legal status transitions of the applications of a
+// user. A workflow without transitions allows every transition.
+type Workflow struct {
+	UserId      int                `json:"userId"`
+	Transitions []StatusTransition `json:"transitions"`
+}
+
+// CheckTransition returns nil, if an application may move from one status to
+// the other. Transitions marked with RequiresReopen need reopen to be set.
+func (w Workflow) CheckTransition(fromStatusId int, toStatusId int, reopen bool) error {
+	if len(w.Transitions) == 0 || fromStatusId == toStatusId {
+		return nil
+	}
+
+	for _, transition := range w.Transitions {
+		if transition.FromStatusId != fromStatusId || transition.ToStatusId != toStatusId {
+			continue
+		}
+
+		if transition.RequiresReopen && !reopen {
+			return ErrReopenRequired
+		}
+
+		return nil
+	}
+
+	return ErrTransitionNotAllowed
+}
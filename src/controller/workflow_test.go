@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTransitionWithoutWorkflow(t *testing.T) {
+	workflow := Workflow{UserId: 1}
+
+	assert.Nil(t, workflow.CheckTransition(3, 2, false))
+}
+
+func TestCheckTransition(t *testing.T) {
+	workflow := Workflow{UserId: 1, Transitions: []StatusTransition{
+		{FromStatusId: 2, ToStatusId: 3},
+		{FromStatusId: 3, ToStatusId: 2, RequiresReopen: true},
+	}}
+
+	assert.Nil(t, workflow.CheckTransition(2, 3, false))
+	assert.Nil(t, workflow.CheckTransition(2, 2, false))
+	assert.ErrorIs(t, workflow.CheckTransition(3, 2, false), ErrReopenRequired)
+	assert.Nil(t, workflow.CheckTransition(3, 2, true))
+	assert.ErrorIs(t, workflow.CheckTransition(2, 1, false), ErrTransitionNotAllowed)
+}
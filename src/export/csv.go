@@ -0,0 +1,63 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// formulaPrefixes are the first characters, which make spreadsheet apps
+// evaluate a cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Title
+	}
+
+	writer := &csvWriter{w: csv.NewWriter(w)}
+	return writer, writer.w.Write(header)
+}
+
+func (c *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value)
+		if _, ok := value.(string); ok {
+			record[i] = escapeFormula(record[i])
+		}
+	}
+
+	return c.w.Write(record)
+}
+
+// escapeFormula prefixes texts, which would be evaluated as formulas, when
+// the file is opened in a spreadsheet app, with a single quote. Numbers are
+// not escaped, so negative numbers stay numbers.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune(formulaPrefixes, rune(text[0])) {
+		return "'" + text
+	}
+
+	return text
+}
+
+// UnescapeFormula removes the single quote, which escapeFormula put in front
+// of a text, so exported CSV files can be imported again.
+func UnescapeFormula(text string) string {
+	if len(text) > 1 && text[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(text[1])) {
+		return text[1:]
+	}
+
+	return text
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
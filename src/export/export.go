@@ -0,0 +1,76 @@
+// Package export writes tables row by row as CSV, JSON or XLSX, so large
+// exports are streamed instead of being built in memory.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The supported formats.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatXLSX = "xlsx"
+)
+
+// Column is a column of a table. The key names the field of the objects in
+// JSON, the title is the header of CSV and XLSX.
+type Column struct {
+	Key   string
+	Title string
+}
+
+// Writer writes the rows of a table. The values of a row are strings,
+// integers, floats, booleans or nil and correspond to the columns. Close
+// completes the output, but does not close the underlying writer.
+type Writer interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// NewWriter creates a writer of the format, which writes the header, if the
+// format has one.
+func NewWriter(format string, w io.Writer, columns []Column) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatJSON:
+		return newJSONWriter(w, columns), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("the format %s is not supported", format)
+	}
+}
+
+// ContentType returns the media type of the format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// formatValue formats a value for the text based cells of CSV and XLSX.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,121 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testColumns = []Column{{Key: "id", Title: "Id"}, {Key: "jobTitle", Title: "Job title"}, {Key: "salary", Title: "Salary"}, {Key: "stale", Title: "Stale"}}
+
+func writeTable(t *testing.T, format string, rows ...[]interface{}) []byte {
+	var buffer bytes.Buffer
+	writer, err := NewWriter(format, &buffer, testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range rows {
+		assert.Nil(t, writer.WriteRow(row))
+	}
+
+	assert.Nil(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", io.Discard, testColumns)
+	assert.NotNil(t, err)
+}
+
+func TestCSV(t *testing.T) {
+	output := writeTable(t, FormatCSV,
+		[]interface{}{1, "Go Developer, Backend", float32(55000.5), false},
+		[]interface{}{2, "Rust Developer", nil, true})
+
+	assert.Equal(t, "Id,Job title,Salary,Stale\n1,\"Go Developer, Backend\",55000.5,false\n2,Rust Developer,,true\n", string(output))
+}
+
+func TestJSON(t *testing.T) {
+	assert.Equal(t, "[]\n", string(writeTable(t, FormatJSON)))
+
+	output := writeTable(t, FormatJSON,
+		[]interface{}{1, "Go Developer", float32(55000.5), false},
+		[]interface{}{2, "Rust Developer", nil, true})
+
+	var rows []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(output, &rows))
+	assert.Equal(t, []map[string]interface{}{
+		{"id": float64(1), "jobTitle": "Go Developer", "salary": 55000.5, "stale": false},
+		{"id": float64(2), "jobTitle": "Rust Developer", "salary": nil, "stale": true},
+	}, rows)
+}
+
+func TestXLSX(t *testing.T) {
+	output := writeTable(t, FormatXLSX, []interface{}{1, "R&D <Developer>", float32(55000.5), true})
+
+	archive, err := zip.NewReader(bytes.NewReader(output), int64(len(output)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	var sheet []byte
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, _ := file.Open()
+			sheet, _ = io.ReadAll(reader)
+			reader.Close()
+		}
+	}
+
+	assert.Equal(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, names)
+	assert.Contains(t, string(sheet), `<row><c t="inlineStr"><is><t xml:space="preserve">Id</t></is></c>`)
+	assert.Contains(t, string(sheet), `<row><c><v>1</v></c><c t="inlineStr"><is><t xml:space="preserve">R&amp;D &lt;Developer&gt;</t></is></c><c><v>55000.5</v></c><c t="b"><v>1</v></c></row>`)
+	assert.Contains(t, string(sheet), `</sheetData></worksheet>`)
+}
+
+func TestFormulasAreNotEvaluated(t *testing.T) {
+	output := writeTable(t, FormatCSV,
+		[]interface{}{-1, "=HYPERLINK(\"https://example.com\")", float64(-5.5), false},
+		[]interface{}{2, "+1", nil, true},
+		[]interface{}{3, "-1", nil, true},
+		[]interface{}{4, "@SUM(A1)", nil, true},
+		[]interface{}{5, "\tcmd", nil, true},
+		[]interface{}{6, "\rcmd", nil, true},
+		[]interface{}{7, "Go = Rust", nil, true})
+
+	assert.Equal(t, "Id,Job title,Salary,Stale\n"+
+		"-1,\"'=HYPERLINK(\"\"https://example.com\"\")\",-5.5,false\n"+
+		"2,'+1,,true\n"+
+		"3,'-1,,true\n"+
+		"4,'@SUM(A1),,true\n"+
+		"5,'\tcmd,,true\n"+
+		"6,\"'\rcmd\",,true\n"+
+		"7,Go = Rust,,true\n", string(output))
+
+	for _, text := range []string{"=1+1", "+1", "-1", "@SUM(A1)", "\tcmd", "\rcmd", "Go = Rust", "'quoted'", "'"} {
+		assert.Equal(t, text, UnescapeFormula(escapeFormula(text)))
+	}
+
+	output = writeTable(t, FormatXLSX, []interface{}{1, "=1+1", nil, false})
+	archive, err := zip.NewReader(bytes.NewReader(output), int64(len(output)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := archive.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	sheet, _ := io.ReadAll(reader)
+	assert.Contains(t, string(sheet), `<c t="inlineStr"><is><t xml:space="preserve">=1+1</t></is></c>`)
+	assert.NotContains(t, string(sheet), "<f>")
+}
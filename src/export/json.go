@@ -0,0 +1,63 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// jsonWriter writes an array of objects, one object per row. The array is
+// opened with the first row and closed by Close.
+type jsonWriter struct {
+	w       *bufio.Writer
+	columns []Column
+	rows    int
+}
+
+func newJSONWriter(w io.Writer, columns []Column) *jsonWriter {
+	return &jsonWriter{w: bufio.NewWriter(w), columns: columns}
+}
+
+func (j *jsonWriter) WriteRow(values []interface{}) error {
+	separator := ",\n"
+	if j.rows == 0 {
+		separator = "[\n"
+	}
+
+	if _, err := j.w.WriteString(separator + "{"); err != nil {
+		return err
+	}
+
+	for i, column := range j.columns {
+		key, _ := json.Marshal(column.Key)
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+
+		j.w.Write(key)
+		j.w.WriteByte(':')
+		j.w.Write(value)
+	}
+
+	j.rows++
+	_, err := j.w.WriteString("}")
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	end := "\n]\n"
+	if j.rows == 0 {
+		end = "[]\n"
+	}
+
+	if _, err := j.w.WriteString(end); err != nil {
+		return err
+	}
+
+	return j.w.Flush()
+}
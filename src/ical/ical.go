@@ -0,0 +1,136 @@
+// Package ical renders calendars in the iCalendar format of RFC 5545, which
+// calendar apps subscribe to.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of a calendar.
+const ContentType = "text/calendar; charset=utf-8"
+
+// productId identifies the service as the creator of the calendars.
+const productId = "-//Application Manager//Application Service//EN"
+
+// maxLineLength is the maximum length of a line in octets, excluding the
+// line break. Longer lines are folded.
+const maxLineLength = 75
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// Event is an event of a calendar. All-day events take the whole day of
+// Start, End is ignored for them. Calendar apps replace an event by another
+// one with the same Uid, so it must not change, when the event does.
+type Event struct {
+	Uid         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Summary     string
+	Description string
+	Location    string
+}
+
+// Calendar is a named list of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write renders the calendar. The stamp is the time the calendar was
+// created, it is the same for all events.
+func Write(w io.Writer, calendar Calendar, stamp time.Time) error {
+	lw := lineWriter{w: bufio.NewWriter(w)}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + productId)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if calendar.Name != "" {
+		lw.line("X-WR-CALNAME:" + escape(calendar.Name))
+	}
+
+	for _, event := range calendar.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + escape(event.Uid))
+		lw.line("DTSTAMP:" + stamp.UTC().Format(dateTimeLayout))
+
+		if event.AllDay {
+			lw.line("DTSTART;VALUE=DATE:" + event.Start.Format(dateLayout))
+			lw.line("DTEND;VALUE=DATE:" + event.Start.AddDate(0, 0, 1).Format(dateLayout))
+		} else {
+			lw.line("DTSTART:" + event.Start.UTC().Format(dateTimeLayout))
+			lw.line("DTEND:" + event.End.UTC().Format(dateTimeLayout))
+		}
+
+		lw.line("SUMMARY:" + escape(event.Summary))
+		if event.Description != "" {
+			lw.line("DESCRIPTION:" + escape(event.Description))
+		}
+
+		if event.Location != "" {
+			lw.line("LOCATION:" + escape(event.Location))
+		}
+
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+
+	return lw.w.Flush()
+}
+
+// escape escapes the characters, which have a special meaning in text
+// values.
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+// lineWriter writes content lines terminated by CRLF and folds lines, which
+// are longer than 75 octets, without splitting characters. It keeps the
+// first error.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(content string) {
+	limit := maxLineLength
+
+	for lw.err == nil && len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+
+		_, lw.err = lw.w.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+
+		// Continuation lines start with a space, which counts to their
+		// length.
+		limit = maxLineLength - 1
+	}
+
+	if lw.err == nil {
+		_, lw.err = lw.w.WriteString(content + "\r\n")
+	}
+}
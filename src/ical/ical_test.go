@@ -0,0 +1,73 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	stamp := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2022, 7, 4, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	var buffer bytes.Buffer
+	err := Write(&buffer, Calendar{
+		Name: "Applications",
+		Events: []Event{
+			{Uid: "submission-1@example.com", Start: time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC), AllDay: true, Summary: "Applied: Go Developer, Backend; Remote"},
+			{Uid: "interview-1@example.com", Start: start, End: start.Add(time.Hour), Summary: "Interview", Description: "Bring\nyour CV", Location: `C:\Office`},
+		},
+	}, stamp)
+	assert.Nil(t, err)
+
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Application Manager//Application Service//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Applications",
+		"BEGIN:VEVENT",
+		"UID:submission-1@example.com",
+		"DTSTAMP:20220701T120000Z",
+		"DTSTART;VALUE=DATE:20220701",
+		"DTEND;VALUE=DATE:20220702",
+		`SUMMARY:Applied: Go Developer\, Backend\; Remote`,
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:interview-1@example.com",
+		"DTSTAMP:20220701T120000Z",
+		"DTSTART:20220704T073000Z",
+		"DTEND:20220704T083000Z",
+		"SUMMARY:Interview",
+		`DESCRIPTION:Bring\nyour CV`,
+		`LOCATION:C:\\Office`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), buffer.String())
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("ä", 100)
+
+	var buffer bytes.Buffer
+	err := Write(&buffer, Calendar{Events: []Event{{Uid: "1", AllDay: true, Summary: summary}}}, time.Now())
+	assert.Nil(t, err)
+
+	var unfolded []string
+	for _, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+
+		if strings.HasPrefix(line, " ") {
+			unfolded[len(unfolded)-1] += line[1:]
+		} else {
+			unfolded = append(unfolded, line)
+		}
+	}
+
+	assert.Contains(t, unfolded, "SUMMARY:"+summary)
+}
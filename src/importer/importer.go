@@ -0,0 +1,149 @@
+// Package importer reads applications from the data exports of job boards,
+// e.g. the archive LinkedIn provides on request. The exports are uploaded by
+// the users and read locally, no job board is contacted.
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrTooLarge is returned, if the files of an archive exceed the size limit.
+var ErrTooLarge = errors.New("the export is too large")
+
+// Entry is an application read from an export. Only the job title, the
+// company name, the submission date and the job posting url of the
+// application are set. Errors holds the problems found, while the entry was
+// read. File is the file of the archive, the entry was read from, and Row
+// its line or position in the file.
+type Entry struct {
+	File        string
+	Row         int
+	Application controller.Application
+	Errors      []string
+}
+
+// Parser reads the files of the export of a source.
+type Parser interface {
+	// Matches reports whether a file of an archive belongs to the export.
+	Matches(name string) bool
+
+	// Parse reads the entries of a file.
+	Parse(reader io.Reader) ([]Entry, error)
+}
+
+var parsers = map[string]Parser{
+	"json":     jsonParser{},
+	"linkedin": linkedInParser{},
+}
+
+// Lookup returns the parser of a source.
+func Lookup(source string) (Parser, bool) {
+	parser, ok := parsers[source]
+	return parser, ok
+}
+
+// Sources returns the names of the supported sources in alphabetical order.
+func Sources() []string {
+	var sources []string
+	for source := range parsers {
+		sources = append(sources, source)
+	}
+
+	sort.Strings(sources)
+	return sources
+}
+
+// Read reads the entries from an uploaded export. A ZIP archive is searched
+// for the files matched by the parser, any other upload is parsed as a
+// single file. maxSize limits the uncompressed size of the files read from
+// an archive.
+func Read(parser Parser, content []byte, maxSize int64) ([]Entry, error) {
+	if !bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		return parser.Parse(bytes.NewReader(content))
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("the archive is not a valid ZIP file: %v", err)
+	}
+
+	var entries []Entry
+	found := false
+	remaining := maxSize
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !parser.Matches(path.Base(file.Name)) {
+			continue
+		}
+
+		found = true
+		data, err := readFile(file, remaining)
+		if err != nil {
+			return nil, err
+		}
+
+		remaining -= int64(len(data))
+		fileEntries, err := parser.Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file.Name, err)
+		}
+
+		for _, entry := range fileEntries {
+			entry.File = file.Name
+			entries = append(entries, entry)
+		}
+	}
+
+	if !found {
+		return nil, errors.New("the archive does not contain an export of the source")
+	}
+
+	return entries, nil
+}
+
+// readFile reads a file of an archive, which must not be larger than limit.
+// The size in the header of the archive is not trusted.
+func readFile(file *zip.File, limit int64) ([]byte, error) {
+	if file.UncompressedSize64 > uint64(limit) {
+		return nil, ErrTooLarge
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file.Name, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file.Name, err)
+	}
+
+	if int64(len(data)) > limit {
+		return nil, ErrTooLarge
+	}
+
+	return data, nil
+}
+
+// parseDate parses a date with the first matching layout. The time of day is
+// dropped, because submission dates are stored without it.
+func parseDate(value string, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%s is not a supported date", value)
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testLinkedInExport = `Application Date,Contact Email,Contact Phone Number,Company Name,Job Title,Job Url,Resume Name,Question And Answers
+"6/30/22, 10:15 AM",me@example.com,,ACME,Go Developer,https://www.linkedin.com/jobs/view/1,resume.pdf,
+"7/1/22, 9:00 PM",me@example.com,,Initech,,https://www.linkedin.com/jobs/view/2,,
+yesterday,me@example.com,,Globex,Rust Developer,,,
+`
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for name, content := range files {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file.Write([]byte(content))
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestLookup(t *testing.T) {
+	assert.Equal(t, []string{"json", "linkedin"}, Sources())
+
+	_, ok := Lookup("linkedin")
+	assert.True(t, ok)
+
+	_, ok = Lookup("monster")
+	assert.False(t, ok)
+}
+
+func TestLinkedIn(t *testing.T) {
+	parser, _ := Lookup("linkedin")
+	assert.True(t, parser.Matches("Job Applications.csv"))
+	assert.True(t, parser.Matches("Job Applications_1.csv"))
+	assert.False(t, parser.Matches("Saved Jobs.csv"))
+
+	entries, err := parser.Parse(strings.NewReader(testLinkedInExport))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(entries))
+
+	assert.Equal(t, 2, entries[0].Row)
+	assert.Equal(t, "Go Developer", entries[0].Application.JobTitle)
+	assert.Equal(t, "ACME", entries[0].Application.CompanyName)
+	assert.Equal(t, "https://www.linkedin.com/jobs/view/1", entries[0].Application.JobPostingUrl)
+	assert.Equal(t, time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC), entries[0].Application.SubmissionDate)
+	assert.Nil(t, entries[0].Errors)
+
+	assert.Equal(t, "", entries[1].Application.JobTitle)
+	assert.Equal(t, time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC), entries[1].Application.SubmissionDate)
+	assert.Equal(t, []string{"yesterday is not a supported date"}, entries[2].Errors)
+
+	_, err = parser.Parse(strings.NewReader("Name,Url\nACME,https://acme.com\n"))
+	assert.NotNil(t, err)
+}
+
+func TestJSON(t *testing.T) {
+	parser, _ := Lookup("json")
+	assert.True(t, parser.Matches("applications.JSON"))
+	assert.False(t, parser.Matches("applications.csv"))
+
+	entries, err := parser.Parse(strings.NewReader(`{"Jobs": [
+		{"Position": "Go Developer", "employer": " ACME ", "appliedAt": "2022-06-30T10:15:00+02:00", "link": "https://jobs.example.com/1"},
+		{"title": "Rust Developer", "company": "Initech", "date": "2022-07-01", "salary": 60000},
+		{"title": 5, "date": "30.06.2022"}
+	]}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, 1, entries[0].Row)
+	assert.Equal(t, "Go Developer", entries[0].Application.JobTitle)
+	assert.Equal(t, "ACME", entries[0].Application.CompanyName)
+	assert.Equal(t, "https://jobs.example.com/1", entries[0].Application.JobPostingUrl)
+	assert.Equal(t, time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC), entries[0].Application.SubmissionDate)
+	assert.Equal(t, time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC), entries[1].Application.SubmissionDate)
+	assert.Equal(t, "", entries[2].Application.JobTitle)
+	assert.Equal(t, []string{"30.06.2022 is not a supported date"}, entries[2].Errors)
+
+	entries, err = parser.Parse(strings.NewReader(`[{"jobTitle": "Go Developer"}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, "Go Developer", entries[0].Application.JobTitle)
+
+	_, err = parser.Parse(strings.NewReader(`{"title": "Go Developer"}`))
+	assert.NotNil(t, err)
+}
+
+func TestReadArchive(t *testing.T) {
+	parser, _ := Lookup("linkedin")
+	archive := zipArchive(t, map[string]string{
+		"Jobs/Job Applications.csv": testLinkedInExport,
+		"Jobs/Saved Jobs.csv":       "Saved Date,Job Url\n",
+		"Profile.csv":               "First Name,Last Name\n",
+	})
+
+	entries, err := Read(parser, archive, 1<<20)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, "Jobs/Job Applications.csv", entries[0].File)
+	assert.Equal(t, "Go Developer", entries[0].Application.JobTitle)
+
+	_, err = Read(parser, archive, 100)
+	assert.Equal(t, ErrTooLarge, err)
+
+	_, err = Read(parser, zipArchive(t, map[string]string{"Profile.csv": "First Name\n"}), 1<<20)
+	assert.NotNil(t, err)
+}
+
+func TestReadFile(t *testing.T) {
+	parser, _ := Lookup("linkedin")
+	entries, err := Read(parser, []byte(testLinkedInExport), 1<<20)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, "", entries[0].File)
+}
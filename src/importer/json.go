@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// jsonParser reads the applications from the JSON exports of job boards and
+// tracking tools. A file is either an array of objects or an object with
+// the array in its applications or jobs field. The fields of the objects are
+// matched by the common names in jsonFields, ignoring the case.
+type jsonParser struct{}
+
+var jsonFields = struct {
+	company, title, date, url []string
+}{
+	company: []string{"company", "companyName", "employer", "organization"},
+	title:   []string{"title", "jobTitle", "position", "role"},
+	date:    []string{"appliedAt", "appliedOn", "appliedDate", "applicationDate", "date"},
+	url:     []string{"url", "jobUrl", "jobPostingUrl", "link"},
+}
+
+var jsonDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+func (jsonParser) Matches(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".json")
+}
+
+func (jsonParser) Parse(reader io.Reader) ([]Entry, error) {
+	var content json.RawMessage
+	if err := json.NewDecoder(reader).Decode(&content); err != nil {
+		return nil, fmt.Errorf("the file is not a valid JSON file: %v", err)
+	}
+
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(content, &objects); err != nil {
+		var wrapper map[string]json.RawMessage
+		if err := json.Unmarshal(content, &wrapper); err != nil {
+			return nil, errors.New("the file must contain an array of applications")
+		}
+
+		var keys []string
+		for key := range wrapper {
+			keys = append(keys, key)
+		}
+
+		key, ok := jsonKey(keys, "applications", "jobs")
+		if !ok || json.Unmarshal(wrapper[key], &objects) != nil {
+			return nil, errors.New("the file must contain an array of applications")
+		}
+	}
+
+	entries := make([]Entry, len(objects))
+	for i, object := range objects {
+		entries[i].Row = i + 1
+		entries[i].Application.CompanyName = jsonString(object, jsonFields.company...)
+		entries[i].Application.JobTitle = jsonString(object, jsonFields.title...)
+		entries[i].Application.JobPostingUrl = jsonString(object, jsonFields.url...)
+
+		if date := jsonString(object, jsonFields.date...); date != "" {
+			var err error
+			entries[i].Application.SubmissionDate, err = parseDate(date, jsonDateLayouts...)
+			if err != nil {
+				entries[i].Errors = append(entries[i].Errors, err.Error())
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// jsonKey returns the key, which matches the first of the fields found among
+// the keys.
+func jsonKey(keys []string, fields ...string) (string, bool) {
+	for _, field := range fields {
+		for _, key := range keys {
+			if strings.EqualFold(key, field) {
+				return key, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// jsonString returns the first of the fields as a trimmed string. Values,
+// which are not strings, are ignored.
+func jsonString(object map[string]interface{}, fields ...string) string {
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	key, _ := jsonKey(keys, fields...)
+	value, _ := object[key].(string)
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// linkedInParser reads the job applications from the data export of
+// LinkedIn. The archive stores them in Jobs/Job Applications.csv, large
+// exports are split into Job Applications_1.csv and so on.
+type linkedInParser struct{}
+
+// linkedInDateLayouts are the formats of the application dates, e.g.
+// 6/30/22, 10:15 AM.
+var linkedInDateLayouts = []string{"1/2/06, 3:04 PM", "1/2/2006, 3:04 PM", "1/2/06", "2006-01-02"}
+
+func (linkedInParser) Matches(name string) bool {
+	return strings.HasPrefix(name, "Job Applications") && strings.HasSuffix(name, ".csv")
+}
+
+func (linkedInParser) Parse(reader io.Reader) ([]Entry, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.New("the file must start with a header")
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	if _, ok := columns["job title"]; !ok {
+		return nil, errors.New("the file is not a LinkedIn export of job applications")
+	}
+
+	var entries []Entry
+	for {
+		values, err := csvReader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("the file is not a valid CSV file: %v", err)
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(values) {
+				return strings.TrimSpace(values[i])
+			}
+
+			return ""
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		entry := Entry{Row: line}
+		entry.Application.JobTitle = value("job title")
+		entry.Application.CompanyName = value("company name")
+		entry.Application.JobPostingUrl = value("job url")
+
+		if date := value("application date"); date != "" {
+			entry.Application.SubmissionDate, err = parseDate(date, linkedInDateLayouts...)
+			if err != nil {
+				entry.Errors = append(entry.Errors, err.Error())
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/migrations"
+	"flhansen/application-manager/application-service/src/notify"
 	"flhansen/application-manager/application-service/src/service"
+	"flhansen/application-manager/application-service/src/storage"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v4/pgxpool"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,6 +24,7 @@ func main() {
 
 func runApplication() int {
 	configPath := flag.String("config", "", "Path to configuration file")
+	migrate := flag.String("migrate", "", "Migrate the database (up or down) and exit")
 	flag.Parse()
 
 	var serviceConfig service.ApplicationServiceConfig
@@ -44,6 +51,33 @@ func runApplication() int {
 		serviceConfig.Database.Username = os.Getenv("APPMAN_DATABASE_USERNAME")
 		serviceConfig.Database.Password = os.Getenv("APPMAN_DATABASE_PASSWORD")
 		serviceConfig.Database.Database = os.Getenv("APPMAN_DATABASE_NAME")
+		serviceConfig.Storage = os.Getenv("APPMAN_STORAGE")
+		serviceConfig.AutoMigrate, _ = strconv.ParseBool(os.Getenv("APPMAN_AUTO_MIGRATE"))
+		serviceConfig.Documents.Blobs = storage.BlobStoreConfig{
+			Type:            os.Getenv("APPMAN_BLOB_STORE"),
+			Directory:       os.Getenv("APPMAN_BLOB_DIRECTORY"),
+			Endpoint:        os.Getenv("APPMAN_S3_ENDPOINT"),
+			Bucket:          os.Getenv("APPMAN_S3_BUCKET"),
+			Region:          os.Getenv("APPMAN_S3_REGION"),
+			AccessKeyId:     os.Getenv("APPMAN_S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("APPMAN_S3_SECRET_ACCESS_KEY"),
+		}
+		serviceConfig.Documents.MaxFileSize, _ = strconv.ParseInt(os.Getenv("APPMAN_DOCUMENTS_MAX_FILE_SIZE"), 10, 64)
+		serviceConfig.Documents.UserQuota, _ = strconv.ParseInt(os.Getenv("APPMAN_DOCUMENTS_USER_QUOTA"), 10, 64)
+		serviceConfig.Scheduler.Interval, _ = time.ParseDuration(os.Getenv("APPMAN_SCHEDULER_INTERVAL"))
+		serviceConfig.Scheduler.BatchSize, _ = strconv.Atoi(os.Getenv("APPMAN_SCHEDULER_BATCH_SIZE"))
+		serviceConfig.Email = notify.SMTPConfig{
+			Host:     os.Getenv("APPMAN_SMTP_HOST"),
+			Username: os.Getenv("APPMAN_SMTP_USERNAME"),
+			Password: os.Getenv("APPMAN_SMTP_PASSWORD"),
+			From:     os.Getenv("APPMAN_SMTP_FROM"),
+		}
+		serviceConfig.Email.Port, _ = strconv.Atoi(os.Getenv("APPMAN_SMTP_PORT"))
+		serviceConfig.Events.Retention, _ = time.ParseDuration(os.Getenv("APPMAN_EVENT_RETENTION"))
+	}
+
+	if *migrate != "" {
+		return runMigrations(serviceConfig.Database, *migrate)
 	}
 
 	s, err := service.NewService(serviceConfig)
@@ -59,3 +93,37 @@ func runApplication() int {
 
 	return 0
 }
+
+func runMigrations(dbConfig controller.DbConfig, direction string) int {
+	db, err := pgxpool.Connect(context.Background(), dbConfig.ConnectionString())
+	if err != nil {
+		fmt.Printf("An error occured while connecting to the database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		fmt.Printf("An error occured while loading the migrations: %v\n", err)
+		return 1
+	}
+
+	var version int
+	switch direction {
+	case "up":
+		version, err = migrator.Up()
+	case "down":
+		version, err = migrator.Down(1)
+	default:
+		fmt.Printf("Unknown migration direction %s, expected up or down\n", direction)
+		return 1
+	}
+
+	if err != nil {
+		fmt.Printf("An error occured while migrating the database: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("The database is at version %d\n", version)
+	return 0
+}
@@ -268,3 +268,25 @@ func TestRunApplicationStartError(t *testing.T) {
 		assert.Equal(t, 1, exitCode)
 	}
 }
+
+func TestRunApplicationMigrateConnectionError(t *testing.T) {
+	oldArgs := os.Args
+	defer func() {
+		os.Args = oldArgs
+	}()
+
+	testEnvCloser := setTestEnv(map[string]string{
+		"APPMAN_DATABASE_HOST":     "localhost",
+		"APPMAN_DATABASE_PORT":     "1234",
+		"APPMAN_DATABASE_USERNAME": "test",
+		"APPMAN_DATABASE_PASSWORD": "test",
+		"APPMAN_DATABASE_NAME":     "test",
+	})
+
+	t.Cleanup(testEnvCloser)
+
+	flag.CommandLine = flag.NewFlagSet("flags set", flag.ExitOnError)
+	os.Args = []string{"flags set", "-migrate", "up"}
+
+	assert.Equal(t, 1, runApplication())
+}
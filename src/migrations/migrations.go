@@ -0,0 +1,248 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// advisoryLockId identifies the lock, which serializes migrations of
+// concurrently starting replicas.
+const advisoryLockId int64 = 7007200100
+
+var fileNamePattern = regexp.MustCompile(`^(\d{4})_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load reads all migrations embedded in the binary, sorted by version.
+func Load() ([]Migration, error) {
+	return load(files, "sql")
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %04d has conflicting names %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d needs both an up and a down file", migration.Version)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration %04d is missing", i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+type Migrator struct {
+	Database   *pgxpool.Pool
+	Context    context.Context
+	Migrations []Migration
+}
+
+func NewMigrator(db *pgxpool.Pool) (Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return Migrator{}, err
+	}
+
+	return Migrator{
+		Database:   db,
+		Context:    context.Background(),
+		Migrations: migrations,
+	}, nil
+}
+
+// withLock runs fn on a single connection, while holding the migration
+// advisory lock. Other replicas block until the lock is released.
+func (m Migrator) withLock(fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.Database.Acquire(m.Context)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(m.Context, "SELECT pg_advisory_lock($1)", advisoryLockId); err != nil {
+		return err
+	}
+	defer conn.Exec(m.Context, "SELECT pg_advisory_unlock($1)", advisoryLockId)
+
+	if _, err := conn.Exec(m.Context,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now())`); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func currentVersion(ctx context.Context, conn *pgxpool.Conn) (int, error) {
+	var version int
+	err := conn.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
+// apply runs a single migration step and its bookkeeping in one transaction.
+func (m Migrator) apply(conn *pgxpool.Conn, script string, bookkeeping string, migration Migration) error {
+	return conn.BeginFunc(m.Context, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(m.Context, script); err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err := tx.Exec(m.Context, bookkeeping, migration.Version, migration.Name)
+		return err
+	})
+}
+
+// Up applies all pending migrations and returns the resulting version.
+func (m Migrator) Up() (int, error) {
+	var version int
+	err := m.withLock(func(conn *pgxpool.Conn) error {
+		var err error
+		if version, err = currentVersion(m.Context, conn); err != nil {
+			return err
+		}
+
+		for _, migration := range m.Migrations {
+			if migration.Version <= version {
+				continue
+			}
+
+			if err := m.apply(conn, migration.Up, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration); err != nil {
+				return err
+			}
+
+			version = migration.Version
+		}
+
+		return nil
+	})
+
+	return version, err
+}
+
+// Down rolls back the given number of migrations and returns the resulting
+// version.
+func (m Migrator) Down(steps int) (int, error) {
+	var version int
+	err := m.withLock(func(conn *pgxpool.Conn) error {
+		var err error
+		if version, err = currentVersion(m.Context, conn); err != nil {
+			return err
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.Migrations[i]
+			if migration.Version > version {
+				continue
+			}
+
+			if err := m.apply(conn, migration.Down, "DELETE FROM schema_migrations WHERE version = $1 AND name = $2", migration); err != nil {
+				return err
+			}
+
+			version = migration.Version - 1
+			steps--
+		}
+
+		return nil
+	})
+
+	return version, err
+}
+
+// Version returns the version of the latest applied migration.
+func (m Migrator) Version() (int, error) {
+	var version int
+	err := m.withLock(func(conn *pgxpool.Conn) error {
+		var err error
+		version, err = currentVersion(m.Context, conn)
+		return err
+	})
+
+	return version, err
+}
+
+// Reset drops everything the migrations created, regardless of the recorded
+// version, and migrates up again. This destroys all data and is only meant
+// for tests.
+func (m Migrator) Reset() error {
+	err := m.withLock(func(conn *pgxpool.Conn) error {
+		for i := len(m.Migrations) - 1; i >= 0; i-- {
+			if _, err := conn.Exec(m.Context, m.Migrations[i].Down); err != nil {
+				return err
+			}
+		}
+
+		_, err := conn.Exec(m.Context, "DELETE FROM schema_migrations")
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = m.Up()
+	return err
+}
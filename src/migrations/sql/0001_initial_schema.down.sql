@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS application CASCADE;
+DROP TABLE IF EXISTS application_status CASCADE;
+DROP TABLE IF EXISTS work_type CASCADE;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS work_type (
+    id SERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS application_status (
+    id SERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS application (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    job_title VARCHAR(255) NOT NULL,
+    work_type_id INTEGER NOT NULL,
+    company_name VARCHAR(255) NOT NULL,
+    submission_date DATE,
+    status_id INTEGER NOT NULL,
+    wanted_salary REAL,
+    accepted_salary REAL,
+    start_date DATE,
+    commentary VARCHAR(500),
+
+    FOREIGN KEY (work_type_id) REFERENCES work_type (id)
+        ON DELETE SET DEFAULT,
+    FOREIGN KEY (status_id) REFERENCES application_status (id)
+        ON DELETE SET DEFAULT
+);
+
+-- Databases created before the migrations existed already contain the
+-- default types, so they are only inserted if missing.
+INSERT INTO work_type (name) SELECT 'Remote' WHERE NOT EXISTS (SELECT 1 FROM work_type WHERE name = 'Remote');
+INSERT INTO work_type (name) SELECT 'OnSite' WHERE NOT EXISTS (SELECT 1 FROM work_type WHERE name = 'OnSite');
+INSERT INTO work_type (name) SELECT 'Hybrid' WHERE NOT EXISTS (SELECT 1 FROM work_type WHERE name = 'Hybrid');
+
+INSERT INTO application_status (name) SELECT 'Accepted' WHERE NOT EXISTS (SELECT 1 FROM application_status WHERE name = 'Accepted');
+INSERT INTO application_status (name) SELECT 'Pending' WHERE NOT EXISTS (SELECT 1 FROM application_status WHERE name = 'Pending');
+INSERT INTO application_status (name) SELECT 'Declined' WHERE NOT EXISTS (SELECT 1 FROM application_status WHERE name = 'Declined');
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS application_search_vector_idx;
+ALTER TABLE IF EXISTS application DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,9 @@
+-- The 'simple' configuration does not stem, because the applications are
+-- written in different languages.
+ALTER TABLE application ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(job_title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(company_name, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(commentary, '')), 'C')
+) STORED;
+
+CREATE INDEX application_search_vector_idx ON application USING GIN (search_vector);
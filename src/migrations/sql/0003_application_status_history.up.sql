@@ -0,0 +1,20 @@
+CREATE TABLE application_status_history (
+    id SERIAL PRIMARY KEY NOT NULL,
+    application_id INTEGER NOT NULL,
+    old_status_id INTEGER,
+    new_status_id INTEGER NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    changed_by INTEGER NOT NULL,
+
+    FOREIGN KEY (application_id) REFERENCES application (id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (old_status_id) REFERENCES application_status (id),
+    FOREIGN KEY (new_status_id) REFERENCES application_status (id)
+);
+
+CREATE INDEX application_status_history_application_idx
+    ON application_status_history (application_id, changed_at);
+
+-- Existing applications start their history with the current status.
+INSERT INTO application_status_history (application_id, old_status_id, new_status_id, changed_at, changed_by)
+    SELECT id, NULL, status_id, coalesce(submission_date::timestamptz, now()), user_id FROM application;
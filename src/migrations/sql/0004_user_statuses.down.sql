@@ -0,0 +1,8 @@
+-- Statuses defined by users are kept, because applications may still
+-- reference them. They become visible to everyone.
+DROP INDEX IF EXISTS application_status_user_idx;
+ALTER TABLE IF EXISTS application_status
+    DROP COLUMN IF EXISTS user_id,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS color,
+    DROP COLUMN IF EXISTS terminal;
@@ -0,0 +1,12 @@
+-- Statuses without a user are the global defaults, which are used by all
+-- users, who did not define their own pipeline.
+ALTER TABLE application_status
+    ADD COLUMN user_id INTEGER,
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN color VARCHAR(7) NOT NULL DEFAULT '',
+    ADD COLUMN terminal BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE application_status SET position = id;
+UPDATE application_status SET terminal = true WHERE name IN ('Accepted', 'Declined');
+
+CREATE INDEX application_status_user_idx ON application_status (user_id, position);
@@ -0,0 +1,14 @@
+-- Users without transitions have no workflow, so every transition is legal.
+CREATE TABLE status_transition (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    from_status_id INTEGER NOT NULL,
+    to_status_id INTEGER NOT NULL,
+    requires_reopen BOOLEAN NOT NULL DEFAULT false,
+
+    UNIQUE (user_id, from_status_id, to_status_id),
+    FOREIGN KEY (from_status_id) REFERENCES application_status (id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (to_status_id) REFERENCES application_status (id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+CREATE TABLE interview (
+    id SERIAL PRIMARY KEY NOT NULL,
+    application_id INTEGER NOT NULL,
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    duration_minutes INTEGER NOT NULL DEFAULT 0 CHECK (duration_minutes >= 0),
+    type VARCHAR(16) NOT NULL CHECK (type IN ('phone', 'video', 'onsite')),
+    interviewers TEXT[] NOT NULL DEFAULT '{}',
+    location TEXT NOT NULL DEFAULT '',
+    outcome VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (outcome IN ('pending', 'passed', 'failed', 'cancelled')),
+    notes TEXT NOT NULL DEFAULT '',
+
+    FOREIGN KEY (application_id) REFERENCES application (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX interview_application_idx ON interview (application_id, scheduled_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS application_contact;
+DROP TABLE IF EXISTS contact;
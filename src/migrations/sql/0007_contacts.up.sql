@@ -0,0 +1,25 @@
+CREATE TABLE contact (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(64) NOT NULL DEFAULT '',
+    role VARCHAR(255) NOT NULL DEFAULT '',
+    linkedin_url VARCHAR(500) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX contact_user_idx ON contact (user_id);
+
+CREATE TABLE application_contact (
+    application_id INTEGER NOT NULL,
+    contact_id INTEGER NOT NULL,
+
+    PRIMARY KEY (application_id, contact_id),
+    FOREIGN KEY (application_id) REFERENCES application (id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (contact_id) REFERENCES contact (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX application_contact_contact_idx ON application_contact (contact_id);
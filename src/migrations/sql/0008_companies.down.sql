@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS application DROP COLUMN IF EXISTS company_id;
+DROP TABLE IF EXISTS company;
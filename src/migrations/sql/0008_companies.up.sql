@@ -0,0 +1,45 @@
+-- Companies are owned by a user. The normalised name ignores case,
+-- punctuation and legal forms, so "ACME", "Acme Inc." and "acme" are the same
+-- company.
+CREATE TABLE company (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL,
+    website VARCHAR(500) NOT NULL DEFAULT '',
+    industry VARCHAR(255) NOT NULL DEFAULT '',
+    size VARCHAR(64) NOT NULL DEFAULT '',
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+
+    UNIQUE (user_id, normalized_name)
+);
+
+ALTER TABLE application
+    ADD COLUMN company_id INTEGER REFERENCES company (id) ON DELETE SET NULL;
+
+CREATE INDEX application_company_idx ON application (company_id);
+
+-- Must match controller.NormalizeCompanyName.
+CREATE OR REPLACE FUNCTION pg_temp.normalize_company_name(name TEXT) RETURNS TEXT AS $$
+    SELECT regexp_replace(
+        trim(regexp_replace(lower(name), '[^[:alnum:]]+', ' ', 'g')),
+        '( (inc|incorporated|ltd|limited|llc|gmbh|corp|corporation|co|company|ag|plc|sa|se|bv))+$', '')
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Fold the existing free-text names into companies. The alphabetically first
+-- spelling of a company becomes its name.
+INSERT INTO company (user_id, name, normalized_name)
+SELECT DISTINCT ON (user_id, normalized_name) user_id, company_name, normalized_name
+FROM (
+    SELECT user_id, company_name, pg_temp.normalize_company_name(company_name) AS normalized_name
+    FROM application
+) names
+WHERE normalized_name <> ''
+ORDER BY user_id, normalized_name, company_name;
+
+UPDATE application
+SET company_id = company.id
+FROM company
+WHERE company.user_id = application.user_id
+  AND company.normalized_name = pg_temp.normalize_company_name(application.company_name);
@@ -0,0 +1,18 @@
+-- The metadata of files attached to applications. The contents are kept in a
+-- blob store under the blob key.
+CREATE TABLE attachment (
+    id SERIAL PRIMARY KEY NOT NULL,
+    application_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    blob_key VARCHAR(500) NOT NULL UNIQUE,
+    uploaded_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (application_id) REFERENCES application (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX attachment_application_idx ON attachment (application_id);
+CREATE INDEX attachment_user_idx ON attachment (user_id);
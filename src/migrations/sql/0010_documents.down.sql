@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS application DROP COLUMN IF EXISTS document_version_id;
+DROP TABLE IF EXISTS document_version;
+DROP TABLE IF EXISTS document;
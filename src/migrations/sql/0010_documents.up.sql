@@ -0,0 +1,32 @@
+-- The document library of a user. Every upload of a document creates a new,
+-- numbered version. Applications reference the version, which was sent.
+CREATE TABLE document (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(32) NOT NULL CHECK (kind IN ('cv', 'cover_letter', 'other')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX document_user_idx ON document (user_id);
+
+CREATE TABLE document_version (
+    id SERIAL PRIMARY KEY NOT NULL,
+    document_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    blob_key VARCHAR(500) NOT NULL UNIQUE,
+    uploaded_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE (document_id, version),
+    FOREIGN KEY (document_id) REFERENCES document (id)
+        ON DELETE CASCADE
+);
+
+-- Versions, which were sent with an application, cannot be deleted.
+ALTER TABLE application
+    ADD COLUMN document_version_id INTEGER REFERENCES document_version (id) ON DELETE RESTRICT;
+
+CREATE INDEX application_document_version_idx ON application (document_version_id);
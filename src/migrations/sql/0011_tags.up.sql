@@ -0,0 +1,22 @@
+-- Tags are defined per user. Their names are unique regardless of case.
+CREATE TABLE tag (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX tag_user_name_idx ON tag (user_id, lower(name));
+
+CREATE TABLE application_tag (
+    application_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+
+    PRIMARY KEY (application_id, tag_id),
+    FOREIGN KEY (application_id) REFERENCES application (id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tag (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX application_tag_tag_idx ON application_tag (tag_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS application_custom_field;
+DROP TABLE IF EXISTS custom_field;
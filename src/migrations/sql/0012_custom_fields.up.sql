@@ -0,0 +1,26 @@
+-- Custom fields are defined per user. The values are stored as JSON, so
+-- values of the same field can be compared and sorted.
+CREATE TABLE custom_field (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    key VARCHAR(64) NOT NULL,
+    label VARCHAR(255) NOT NULL DEFAULT '',
+    type VARCHAR(16) NOT NULL CHECK (type IN ('text', 'number', 'date', 'enum', 'boolean')),
+    options TEXT[] NOT NULL DEFAULT '{}',
+
+    UNIQUE (user_id, key)
+);
+
+CREATE TABLE application_custom_field (
+    application_id INTEGER NOT NULL,
+    field_id INTEGER NOT NULL,
+    value JSONB NOT NULL,
+
+    PRIMARY KEY (application_id, field_id),
+    FOREIGN KEY (application_id) REFERENCES application (id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (field_id) REFERENCES custom_field (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX application_custom_field_field_idx ON application_custom_field (field_id, value);
@@ -0,0 +1,16 @@
+-- Reminders are fired once by the scheduler of one of the replicas, which
+-- sets fired_at.
+CREATE TABLE reminder (
+    id SERIAL PRIMARY KEY NOT NULL,
+    application_id INTEGER NOT NULL,
+    remind_at TIMESTAMPTZ NOT NULL,
+    message VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    fired_at TIMESTAMPTZ,
+
+    FOREIGN KEY (application_id) REFERENCES application (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX reminder_application_idx ON reminder (application_id);
+CREATE INDEX reminder_due_idx ON reminder (remind_at) WHERE fired_at IS NULL;
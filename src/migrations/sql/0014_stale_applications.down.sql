@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS application
+    DROP COLUMN IF EXISTS stale,
+    DROP COLUMN IF EXISTS status_changed_at;
+
+ALTER TABLE IF EXISTS application_status
+    DROP COLUMN IF EXISTS stale_status_id,
+    DROP COLUMN IF EXISTS stale_after_days;
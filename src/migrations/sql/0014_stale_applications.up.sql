@@ -0,0 +1,20 @@
+-- Applications, which stay in a status for stale_after_days, are marked as
+-- stale or, if stale_status_id is set, moved to that status.
+ALTER TABLE application_status
+    ADD COLUMN stale_after_days INTEGER CHECK (stale_after_days > 0),
+    ADD COLUMN stale_status_id INTEGER REFERENCES application_status (id)
+        ON DELETE SET NULL;
+
+-- Pending applications without a reply for 30 days are stale by default.
+UPDATE application_status SET stale_after_days = 30 WHERE user_id IS NULL AND name = 'Pending';
+
+ALTER TABLE application
+    ADD COLUMN status_changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN stale BOOLEAN NOT NULL DEFAULT false;
+
+-- Existing applications entered their status with the latest transition or,
+-- if there was none, when they were submitted.
+UPDATE application SET status_changed_at = coalesce(
+    (SELECT max(changed_at) FROM application_status_history
+     WHERE application_id = application.id AND old_status_id IS NOT NULL),
+    least(submission_date::timestamptz, now()));
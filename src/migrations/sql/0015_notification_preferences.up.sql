@@ -0,0 +1,11 @@
+-- Users opt out of single notification events. Emails are only sent to users,
+-- who entered an address. The scheduler of one of the replicas sends the
+-- weekly digest and sets digest_sent_at.
+CREATE TABLE notification_preference (
+    user_id INTEGER PRIMARY KEY NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    disabled_events TEXT[] NOT NULL DEFAULT '{}',
+    digest_sent_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX notification_preference_digest_idx ON notification_preference (digest_sent_at) WHERE email <> '';
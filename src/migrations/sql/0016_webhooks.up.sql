@@ -0,0 +1,49 @@
+CREATE TABLE webhook (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    url VARCHAR(2000) NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    events TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhook_user_idx ON webhook (user_id);
+
+-- Events are written in the same transaction as the change of the
+-- application. The scheduler fans them out to the webhooks of the user and
+-- deletes events, to which no webhook is subscribed. The application is not
+-- referenced, because events outlive deleted applications.
+CREATE TABLE outbox_event (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    application_id INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    dispatched_at TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_event_pending_idx ON outbox_event (id) WHERE dispatched_at IS NULL;
+
+CREATE TABLE webhook_delivery (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    webhook_id INTEGER NOT NULL,
+    event_id BIGINT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,
+    error VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (webhook_id) REFERENCES webhook (id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (event_id) REFERENCES outbox_event (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_delivery_webhook_idx ON webhook_delivery (webhook_id, id);
+CREATE INDEX webhook_delivery_due_idx ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS outbox_event_created_idx;
+DROP INDEX IF EXISTS outbox_event_user_idx;
+DROP TRIGGER IF EXISTS outbox_event_notify ON outbox_event;
+DROP FUNCTION IF EXISTS notify_outbox_event();
@@ -0,0 +1,16 @@
+-- The outbox doubles as the log of the event stream. Events are kept for a
+-- retention period instead of being deleted, once they are dispatched. Every
+-- replica listens on the outbox_event channel, which carries the id of the
+-- user, and wakes up the streams of the user.
+CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('outbox_event', NEW.user_id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_event_notify AFTER INSERT ON outbox_event
+    FOR EACH ROW EXECUTE PROCEDURE notify_outbox_event();
+
+CREATE INDEX outbox_event_user_idx ON outbox_event (user_id, id);
+CREATE INDEX outbox_event_created_idx ON outbox_event (created_at);
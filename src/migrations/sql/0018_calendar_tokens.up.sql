@@ -0,0 +1,8 @@
+-- The calendar feed of a user is fetched by calendar apps, which cannot send
+-- the JWT of the user, so it is protected by a secret token in the url.
+-- Rotating the token replaces it.
+CREATE TABLE calendar_token (
+    user_id INTEGER PRIMARY KEY NOT NULL,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS audit_log_user_idx;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,12 @@
+-- The audit log records actions, which have to be accounted for, e.g. the
+-- erasure of the data of a user. The records outlive the data, so their
+-- details must not contain personal data.
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_user_idx ON audit_log (user_id);
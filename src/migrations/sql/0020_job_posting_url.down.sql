@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS application DROP COLUMN IF EXISTS job_posting_url;
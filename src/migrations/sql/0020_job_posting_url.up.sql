@@ -0,0 +1,2 @@
+-- The url of the job posting, to which an application responds.
+ALTER TABLE application ADD COLUMN job_posting_url VARCHAR(2000) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS job_posting_snapshot_user_url_idx;
+DROP TABLE IF EXISTS job_posting_snapshot;
@@ -0,0 +1,14 @@
+-- Snapshots of the pages of job postings, which were fetched to draft
+-- applications, so the postings can be read after they were taken down. The
+-- contents are kept in a blob store under the blob key.
+CREATE TABLE job_posting_snapshot (
+    id SERIAL PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    url VARCHAR(2000) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    blob_key VARCHAR(500) NOT NULL UNIQUE,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX job_posting_snapshot_user_url_idx ON job_posting_snapshot (user_id, url);
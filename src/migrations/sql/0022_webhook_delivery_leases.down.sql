@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS webhook_delivery DROP COLUMN IF EXISTS locked_until;
@@ -0,0 +1,4 @@
+-- Deliveries are claimed by a scheduler until locked_until, so they are
+-- attempted outside of a transaction. Deliveries of a scheduler, which
+-- stopped during an attempt, are attempted again after the lease expired.
+ALTER TABLE webhook_delivery ADD COLUMN locked_until TIMESTAMPTZ;
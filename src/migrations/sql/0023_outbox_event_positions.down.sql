@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS outbox_event_user_position_idx;
+DROP TRIGGER IF EXISTS outbox_event_position ON outbox_event;
+DROP FUNCTION IF EXISTS position_outbox_event();
+ALTER TABLE IF EXISTS outbox_event DROP COLUMN IF EXISTS position;
+DROP SEQUENCE IF EXISTS outbox_event_position_seq;
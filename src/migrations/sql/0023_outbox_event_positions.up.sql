@@ -0,0 +1,26 @@
+-- The event stream resumes after the position of the last event it sent. Ids
+-- are taken, when the events are written, but transactions commit in any
+-- order, so a stream reading after the greatest id it has seen could miss an
+-- event with a smaller id, which commits later. Positions are taken while
+-- the transaction commits instead, and the commits of events are serialized
+-- by an advisory lock, so events become visible in the order of their
+-- positions.
+CREATE SEQUENCE outbox_event_position_seq;
+
+ALTER TABLE outbox_event ADD COLUMN position BIGINT;
+UPDATE outbox_event SET position = id;
+SELECT setval('outbox_event_position_seq', COALESCE(MAX(position), 0) + 1, false) FROM outbox_event;
+
+CREATE OR REPLACE FUNCTION position_outbox_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('outbox_event_position'));
+    UPDATE outbox_event SET position = nextval('outbox_event_position_seq') WHERE id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER outbox_event_position AFTER INSERT ON outbox_event
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE position_outbox_event();
+
+CREATE INDEX outbox_event_user_position_idx ON outbox_event (user_id, position);
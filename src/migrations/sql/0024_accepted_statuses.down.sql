@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS application_status DROP COLUMN IF EXISTS accepted;
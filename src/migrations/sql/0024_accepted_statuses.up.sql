@@ -0,0 +1,5 @@
+-- The start dates of applications in an accepted status are shown in the
+-- calendar feed. Statuses named Accepted were used so far.
+ALTER TABLE application_status ADD COLUMN accepted BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE application_status SET accepted = true WHERE lower(name) = 'accepted';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS reminder_next_attempt_idx;
+
+ALTER TABLE IF EXISTS reminder
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,10 @@
+-- Reminders are claimed by a scheduler until locked_until and fired outside
+-- of a transaction. Reminders, which could not be delivered, are retried at
+-- next_attempt_at with a growing delay, so they do not hold up the reminders
+-- due after them.
+ALTER TABLE reminder
+    ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMPTZ,
+    ADD COLUMN locked_until TIMESTAMPTZ;
+
+CREATE INDEX reminder_next_attempt_idx ON reminder ((coalesce(next_attempt_at, remind_at))) WHERE fired_at IS NULL;
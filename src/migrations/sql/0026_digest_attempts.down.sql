@@ -0,0 +1,4 @@
+ALTER TABLE IF EXISTS notification_preference
+    DROP COLUMN IF EXISTS digest_locked_until,
+    DROP COLUMN IF EXISTS digest_next_attempt_at,
+    DROP COLUMN IF EXISTS digest_attempts;
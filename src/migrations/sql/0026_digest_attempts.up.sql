@@ -0,0 +1,8 @@
+-- Digests are claimed by a scheduler until digest_locked_until and sent
+-- outside of a transaction. Digests, which could not be delivered, are
+-- retried at digest_next_attempt_at with a growing delay, so they do not hold
+-- up the digests of other users.
+ALTER TABLE notification_preference
+    ADD COLUMN digest_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN digest_next_attempt_at TIMESTAMPTZ,
+    ADD COLUMN digest_locked_until TIMESTAMPTZ;
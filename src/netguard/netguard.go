@@ -0,0 +1,67 @@
+// Package netguard keeps the requests to urls provided by users away from the
+// loopback, private and link-local networks of the service.
+package netguard
+
+import (
+	"net"
+	"syscall"
+	"time"
+)
+
+// Dialer creates a dialer, which refuses to connect to addresses, which are
+// not public, with the forbidden error. The check happens after the name of
+// the host is resolved, so it covers redirects and names resolving to
+// private addresses as well. Private networks should only be allowed for
+// tests.
+func Dialer(allowPrivateNetworks bool, forbidden error) *net.Dialer {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivateNetworks {
+		dialer.Control = func(network string, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !IsPublic(ip) {
+				return forbidden
+			}
+
+			return nil
+		}
+	}
+
+	return dialer
+}
+
+// sharedNetworks are not covered by the checks of net.IP, but reach hosts of
+// the provider: the shared address space of carrier-grade NATs and the NAT64
+// prefix, which embeds IPv4 addresses including private ones.
+var sharedNetworks = []*net.IPNet{
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("64:ff9b::/96"),
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+
+	return network
+}
+
+// IsPublic reports whether the address is reachable on the internet.
+func IsPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+
+	for _, network := range sharedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
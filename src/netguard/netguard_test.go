@@ -0,0 +1,43 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublic(t *testing.T) {
+	assert.True(t, IsPublic(net.ParseIP("93.184.216.34")))
+	assert.True(t, IsPublic(net.ParseIP("2606:2800:220:1:248:1893:25c8:1946")))
+	assert.False(t, IsPublic(net.ParseIP("127.0.0.1")))
+	assert.False(t, IsPublic(net.ParseIP("10.0.0.1")))
+	assert.False(t, IsPublic(net.ParseIP("192.168.1.1")))
+	assert.False(t, IsPublic(net.ParseIP("169.254.169.254")))
+	assert.False(t, IsPublic(net.ParseIP("0.0.0.0")))
+	assert.False(t, IsPublic(net.ParseIP("::1")))
+	assert.False(t, IsPublic(net.ParseIP("fe80::1")))
+	assert.False(t, IsPublic(net.ParseIP("100.64.0.1")))
+	assert.False(t, IsPublic(net.ParseIP("100.127.255.254")))
+	assert.True(t, IsPublic(net.ParseIP("100.128.0.1")))
+	assert.False(t, IsPublic(net.ParseIP("64:ff9b::a00:1")))
+	assert.False(t, IsPublic(net.ParseIP("64:ff9b::5db8:d822")))
+}
+
+func TestDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	forbidden := errors.New("forbidden")
+	_, err = Dialer(false, forbidden).DialContext(context.Background(), "tcp", listener.Addr().String())
+	assert.ErrorIs(t, err, forbidden)
+
+	conn, err := Dialer(true, forbidden).DialContext(context.Background(), "tcp", listener.Addr().String())
+	assert.Nil(t, err)
+	conn.Close()
+}
@@ -0,0 +1,278 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*
+var templateFiles embed.FS
+
+var templateFuncs = map[string]interface{}{
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+}
+
+// The HTML templates escape the values of applications, the plain text
+// templates are used for the alternative without markup.
+var (
+	htmlTemplates = htmltemplate.Must(htmltemplate.New("").Funcs(templateFuncs).ParseFS(templateFiles, "templates/*.html"))
+	textTemplates = texttemplate.Must(texttemplate.New("").Funcs(templateFuncs).ParseFS(templateFiles, "templates/*.txt"))
+)
+
+// Email is a rendered notification. Text is the plain text alternative of
+// the HTML body.
+type Email struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// RenderEmail renders the notification from the templates of its kind.
+func RenderEmail(notification Notification) (Email, error) {
+	name := "event"
+	if notification.Digest != nil {
+		name = "digest"
+	}
+
+	var html, text bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", notification); err != nil {
+		return Email{}, err
+	}
+
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", notification); err != nil {
+		return Email{}, err
+	}
+
+	return Email{Subject: emailSubject(notification), Text: text.String(), HTML: html.String()}, nil
+}
+
+func emailSubject(notification Notification) string {
+	if notification.Digest != nil {
+		return "Your weekly application digest"
+	}
+
+	application := fmt.Sprintf("%s at %s", notification.Application.JobTitle, notification.Application.CompanyName)
+	switch notification.Event {
+	case EventReminder:
+		return "Reminder: " + application
+	case EventStale:
+		return "No progress: " + application
+	case EventStatusChanged:
+		return "Status changed: " + application
+	}
+
+	return "Update: " + application
+}
+
+// SMTPConfig configures the server, through which emails are sent. The
+// connection is upgraded to TLS, if the server supports it. With a username,
+// the server must support TLS, so the credentials are never sent in plain
+// text. Zero values select the defaults.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+
+	// From is the sender of the emails, e.g.
+	// "Application Manager <noreply@example.com>".
+	From string
+
+	// Timeout limits the delivery of a single email.
+	Timeout time.Duration
+}
+
+// ErrTLSRequired is returned, if credentials are configured, but the server
+// does not support TLS.
+var ErrTLSRequired = errors.New("the SMTP server does not support STARTTLS, which is required for authentication")
+
+const (
+	defaultSMTPPort    = 587
+	defaultSMTPTimeout = 30 * time.Second
+)
+
+func (c SMTPConfig) address() string {
+	port := c.Port
+	if port <= 0 {
+		port = defaultSMTPPort
+	}
+
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+func (c SMTPConfig) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return defaultSMTPTimeout
+	}
+
+	return c.Timeout
+}
+
+// EmailNotifier sends the notifications as emails via SMTP. Notifications
+// without an email address are dropped.
+type EmailNotifier struct {
+	Config SMTPConfig
+}
+
+func NewEmailNotifier(config SMTPConfig) EmailNotifier {
+	return EmailNotifier{Config: config}
+}
+
+func (n EmailNotifier) Notify(ctx context.Context, notification Notification) error {
+	if notification.Email == "" {
+		return nil
+	}
+
+	from, err := mail.ParseAddress(n.Config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	// The address is stored by the user, so it is formatted again instead of
+	// being written to the header as is.
+	recipient, err := mail.ParseAddress(notification.Email)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	to := &mail.Address{Address: recipient.Address}
+
+	email, err := RenderEmail(notification)
+	if err != nil {
+		return err
+	}
+
+	message, err := buildMessage(from.String(), to.String(), email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return n.send(ctx, from.Address, to.Address, message)
+}
+
+// buildMessage creates a multipart message, which contains the plain text
+// and the HTML version of the email.
+func buildMessage(from string, to string, email Email, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", email.Text},
+		{"text/html; charset=utf-8", email.HTML},
+	}
+
+	for _, part := range parts {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(partWriter)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	// The subject contains user input, which is encoded, if it contains
+	// line breaks or other characters not allowed in headers.
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// send delivers the message through the configured server.
+func (n EmailNotifier) send(ctx context.Context, from string, to string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, n.Config.timeout())
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.Config.address())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, n.Config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.Config.Host}); err != nil {
+			return err
+		}
+	} else if n.Config.Username != "" {
+		return ErrTLSRequired
+	}
+
+	if n.Config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.Config.Username, n.Config.Password, n.Config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := data.Write(message); err != nil {
+		return err
+	}
+
+	if err := data.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,238 @@
+package notify
+
+import (
+	"context"
+	"flhansen/application-manager/application-service/src/controller"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMail is a mail received by the fake SMTP server.
+type fakeMail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// fakeSMTPServer accepts all mails and keeps them in memory. It implements
+// just enough of SMTP for net/smtp.
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	mails []fakeMail
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeSMTPServer) config() SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	return SMTPConfig{Host: host, Port: portNumber, From: "Application Manager <noreply@example.com>"}
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	text := textproto.NewConn(conn)
+	defer text.Close()
+
+	var current fakeMail
+	text.PrintfLine("220 localhost fake SMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "MAIL":
+			current = fakeMail{From: strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")}
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			current.To = append(current.To, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 Go ahead")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+
+			current.Data = string(data)
+			s.mu.Lock()
+			s.mails = append(s.mails, current)
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) received() []fakeMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]fakeMail{}, s.mails...)
+}
+
+// readParts returns the bodies of the multipart message by content type.
+func readParts(t *testing.T, message *mail.Message) map[string]string {
+	_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, _ := io.ReadAll(part)
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[mediaType] = string(content)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	notifier := NewEmailNotifier(server.config())
+
+	err := notifier.Notify(context.Background(), Notification{
+		Event:       EventStatusChanged,
+		UserId:      1,
+		Email:       "jane@example.com",
+		Application: controller.Application{Id: 2, JobTitle: "Go <Developer>", CompanyName: "ACME", SubmissionDate: time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC)},
+		Message:     "Status changed from Pending to Accepted",
+	})
+	assert.Nil(t, err)
+
+	mails := server.received()
+	assert.Equal(t, 1, len(mails))
+	assert.Equal(t, "noreply@example.com", mails[0].From)
+	assert.Equal(t, []string{"jane@example.com"}, mails[0].To)
+
+	message, err := mail.ReadMessage(strings.NewReader(mails[0].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Status changed: Go <Developer> at ACME", message.Header.Get("Subject"))
+	assert.Equal(t, "<jane@example.com>", message.Header.Get("To"))
+
+	parts := readParts(t, message)
+	assert.Contains(t, parts["text/plain"], "Status changed from Pending to Accepted")
+	assert.Contains(t, parts["text/plain"], "Position:  Go <Developer>")
+	assert.Contains(t, parts["text/plain"], "Submitted: 2022-06-30")
+	assert.Contains(t, parts["text/html"], "<strong>Go &lt;Developer&gt;</strong>")
+}
+
+func TestEmailNotifierSkipsUsersWithoutEmail(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	notifier := NewEmailNotifier(server.config())
+
+	assert.Nil(t, notifier.Notify(context.Background(), Notification{Event: EventReminder, UserId: 1}))
+	assert.Empty(t, server.received())
+}
+
+func TestEmailNotifierFailsWithoutServer(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	config := server.config()
+	server.listener.Close()
+
+	err := NewEmailNotifier(config).Notify(context.Background(), Notification{Event: EventReminder, UserId: 1, Email: "jane@example.com"})
+	assert.NotNil(t, err)
+}
+
+func TestRenderDigest(t *testing.T) {
+	since := time.Date(2022, 6, 24, 0, 0, 0, 0, time.UTC)
+	application := controller.Application{JobTitle: "Go Developer", CompanyName: "ACME", SubmissionDate: since.AddDate(0, 0, 2), StatusChangedAt: since.AddDate(0, 0, 3)}
+
+	email, err := RenderEmail(Notification{
+		Event:  EventDigest,
+		UserId: 1,
+		Digest: &Digest{
+			Since:     since,
+			Until:     since.AddDate(0, 0, 7),
+			Statuses:  []StatusCount{{Status: "Pending", Count: 3}},
+			Submitted: []controller.Application{application},
+			Stale:     []controller.Application{application},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Your weekly application digest", email.Subject)
+	assert.Contains(t, email.Text, "Your applications from 2022-06-24 to 2022-07-01.")
+	assert.Contains(t, email.Text, "Pending: 3")
+	assert.Contains(t, email.Text, "- Go Developer at ACME on 2022-06-26")
+	assert.Contains(t, email.Text, "- Go Developer at ACME since 2022-06-27")
+	assert.NotContains(t, email.Text, "Status changed:")
+	assert.Contains(t, email.HTML, "<h3>Submitted</h3>")
+	assert.NotContains(t, email.HTML, "<h3>Status changed</h3>")
+}
+
+func TestEmailNotifierRejectsInvalidRecipients(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	notifier := NewEmailNotifier(server.config())
+
+	err := notifier.Notify(context.Background(), Notification{Event: EventReminder, UserId: 1, Email: "jane@example.com\r\nBcc: eve@example.com"})
+	assert.NotNil(t, err)
+	assert.Empty(t, server.received())
+}
+
+func TestEmailNotifierRequiresTLSForAuthentication(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	config := server.config()
+	config.Username = "jane"
+	config.Password = "secret"
+
+	err := NewEmailNotifier(config).Notify(context.Background(), Notification{Event: EventReminder, UserId: 1, Email: "jane@example.com"})
+	assert.ErrorIs(t, err, ErrTLSRequired)
+	assert.Empty(t, server.received())
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryNotifier collects the notifications in memory. It is meant for
+// tests.
+type MemoryNotifier struct {
+	mu            sync.Mutex
+	notifications []Notification
+
+	// Err is returned by Notify instead of recording the notification, if
+	// it is set.
+	Err error
+}
+
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{}
+}
+
+func (n *MemoryNotifier) Notify(ctx context.Context, notification Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.Err != nil {
+		return n.Err
+	}
+
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+// Notifications returns the recorded notifications in the order they were
+// delivered.
+func (n *MemoryNotifier) Notifications() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]Notification{}, n.notifications...)
+}
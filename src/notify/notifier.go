@@ -0,0 +1,91 @@
+// Package notify delivers notifications about applications to their owners.
+package notify
+
+import (
+	"context"
+	"flhansen/application-manager/application-service/src/controller"
+	"log"
+	"time"
+)
+
+const (
+	// EventReminder is the event of a notification about a due reminder.
+	EventReminder = "reminder"
+
+	// EventStale is the event of a notification about an application,
+	// which has been in its status for too long.
+	EventStale = "stale"
+
+	// EventStatusChanged is the event of a notification about an
+	// application, which entered another status.
+	EventStatusChanged = "status_changed"
+
+	// EventDigest is the event of the weekly summary of the applications
+	// of a user.
+	EventDigest = "digest"
+)
+
+// Events lists all events, about which users can be notified.
+var Events = []string{EventReminder, EventStale, EventStatusChanged, EventDigest}
+
+// Notification informs a user about an event concerning one of their
+// applications. Digest notifications summarize all applications of the user
+// instead. Email is the address of the user, if it is known.
+type Notification struct {
+	Event       string
+	UserId      int
+	Email       string
+	Application controller.Application
+	Message     string
+	Digest      *Digest
+}
+
+// Digest summarizes the applications of a user over a period.
+type Digest struct {
+	Since time.Time
+	Until time.Time
+
+	// Statuses counts the applications by status.
+	Statuses []StatusCount
+
+	// Submitted contains the applications submitted within the period,
+	// Changed the applications, which entered another status within the
+	// period, and Stale the applications currently marked as stale.
+	Submitted []controller.Application
+	Changed   []controller.Application
+	Stale     []controller.Application
+}
+
+// StatusCount is the number of applications in a status.
+type StatusCount struct {
+	Status string
+	Count  int
+}
+
+// Notifier delivers notifications. An error means, that the notification was
+// not delivered and should be retried.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes the notifications to a log.
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+// NewLogNotifier creates a notifier writing to the standard logger.
+func NewLogNotifier() LogNotifier {
+	return LogNotifier{Logger: log.Default()}
+}
+
+func (n LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	if notification.Digest != nil {
+		n.Logger.Printf("%s for user %d: %s", notification.Event, notification.UserId, notification.Message)
+		return nil
+	}
+
+	n.Logger.Printf("%s for user %d, application %d (%s at %s): %s", notification.Event, notification.UserId,
+		notification.Application.Id, notification.Application.JobTitle, notification.Application.CompanyName, notification.Message)
+
+	return nil
+}
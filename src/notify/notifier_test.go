@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogNotifier(t *testing.T) {
+	var buffer bytes.Buffer
+	notifier := LogNotifier{Logger: log.New(&buffer, "", 0)}
+
+	err := notifier.Notify(context.Background(), Notification{
+		Event:       EventReminder,
+		UserId:      1,
+		Application: controller.Application{Id: 2, JobTitle: "Go Developer", CompanyName: "ACME"},
+		Message:     "Follow up",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "reminder for user 1, application 2 (Go Developer at ACME): Follow up\n", buffer.String())
+
+	buffer.Reset()
+	notifier.Notify(context.Background(), Notification{Event: EventDigest, UserId: 1, Message: "2 submitted", Digest: &Digest{}})
+	assert.Equal(t, "digest for user 1: 2 submitted\n", buffer.String())
+}
+
+func TestMemoryNotifier(t *testing.T) {
+	notifier := NewMemoryNotifier()
+
+	assert.Nil(t, notifier.Notify(context.Background(), Notification{Event: EventReminder, UserId: 1}))
+	assert.Equal(t, 1, len(notifier.Notifications()))
+
+	notifier.Err = errors.New("unavailable")
+	assert.NotNil(t, notifier.Notify(context.Background(), Notification{Event: EventReminder, UserId: 1}))
+	assert.Equal(t, 1, len(notifier.Notifications()))
+}
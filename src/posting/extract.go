@@ -0,0 +1,243 @@
+package posting
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Posting holds the details of a job found on its page. Details, which were
+// not found, are empty.
+type Posting struct {
+	Title    string  `json:"title"`
+	Company  string  `json:"company"`
+	Location string  `json:"location"`
+	Remote   bool    `json:"remote"`
+	Salary   *Salary `json:"salary,omitempty"`
+}
+
+// Salary is the advertised salary range. Unit is the period, to which the
+// amounts refer, e.g. YEAR or HOUR.
+type Salary struct {
+	Currency string  `json:"currency"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Unit     string  `json:"unit"`
+}
+
+var (
+	jsonLDPattern    = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+	metaPattern      = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attributePattern = regexp.MustCompile(`(?s)([a-zA-Z:_-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	titlePattern     = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// Extract finds the details of the job on the page. The schema.org
+// JobPosting data is preferred, the OpenGraph tags and the title of the page
+// are used for the title and the company, if there is none.
+func Extract(content []byte) Posting {
+	var posting Posting
+
+	for _, match := range jsonLDPattern.FindAllSubmatch(content, -1) {
+		var data interface{}
+		if json.Unmarshal(match[1], &data) != nil {
+			continue
+		}
+
+		if job := findJobPosting(data); job != nil {
+			posting = jobPosting(job)
+			break
+		}
+	}
+
+	if posting.Title == "" {
+		title := metaTags(content)["og:title"]
+		if title == "" {
+			if match := titlePattern.FindSubmatch(content); match != nil {
+				title = clean(string(match[1]))
+			}
+		}
+
+		// Many job boards title their pages like "Go Developer at ACME".
+		if i := strings.LastIndex(title, " at "); i > 0 && posting.Company == "" {
+			posting.Company = strings.TrimSpace(title[i+4:])
+			title = title[:i]
+		}
+
+		posting.Title = strings.TrimSpace(title)
+	}
+
+	return posting
+}
+
+// metaTags returns the contents of the meta tags by their property or name.
+func metaTags(content []byte) map[string]string {
+	tags := map[string]string{}
+
+	for _, tag := range metaPattern.FindAll(content, -1) {
+		attributes := map[string]string{}
+		for _, attribute := range attributePattern.FindAllSubmatch(tag, -1) {
+			attributes[strings.ToLower(string(attribute[1]))] = string(attribute[2]) + string(attribute[3]) + string(attribute[4])
+		}
+
+		key := attributes["property"]
+		if key == "" {
+			key = attributes["name"]
+		}
+
+		key = strings.ToLower(key)
+		if _, ok := tags[key]; key != "" && !ok {
+			tags[key] = clean(attributes["content"])
+		}
+	}
+
+	return tags
+}
+
+// findJobPosting searches the JSON-LD data for an object of the type
+// JobPosting. The data may be an array or a graph of objects.
+func findJobPosting(data interface{}) map[string]interface{} {
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if job := findJobPosting(item); job != nil {
+				return job
+			}
+		}
+	case map[string]interface{}:
+		if hasType(value, "JobPosting") {
+			return value
+		}
+
+		return findJobPosting(value["@graph"])
+	}
+
+	return nil
+}
+
+func hasType(object map[string]interface{}, name string) bool {
+	switch value := object["@type"].(type) {
+	case string:
+		return value == name
+	case []interface{}:
+		for _, item := range value {
+			if item == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func jobPosting(job map[string]interface{}) Posting {
+	posting := Posting{
+		Title:   text(job["title"]),
+		Company: name(job["hiringOrganization"]),
+		Remote:  strings.EqualFold(text(job["jobLocationType"]), "TELECOMMUTE"),
+	}
+
+	var locations []string
+	for _, place := range list(job["jobLocation"]) {
+		if location := address(place); location != "" {
+			locations = append(locations, location)
+		}
+	}
+
+	posting.Location = strings.Join(locations, "; ")
+
+	if salary, ok := job["baseSalary"].(map[string]interface{}); ok {
+		posting.Salary = &Salary{Currency: text(salary["currency"])}
+
+		switch value := salary["value"].(type) {
+		case map[string]interface{}:
+			posting.Salary.Min = number(value["minValue"])
+			posting.Salary.Max = number(value["maxValue"])
+			posting.Salary.Unit = text(value["unitText"])
+
+			if amount := number(value["value"]); amount != 0 && posting.Salary.Min == 0 && posting.Salary.Max == 0 {
+				posting.Salary.Min, posting.Salary.Max = amount, amount
+			}
+		default:
+			posting.Salary.Min = number(value)
+			posting.Salary.Max = posting.Salary.Min
+		}
+
+		if posting.Salary.Min == 0 && posting.Salary.Max == 0 {
+			posting.Salary = nil
+		}
+	}
+
+	return posting
+}
+
+// address formats the address of a Place as locality, region and country.
+func address(place interface{}) string {
+	object, ok := place.(map[string]interface{})
+	if !ok {
+		return text(place)
+	}
+
+	postal, ok := object["address"].(map[string]interface{})
+	if !ok {
+		return text(object["address"])
+	}
+
+	var parts []string
+	for _, part := range []string{text(postal["addressLocality"]), text(postal["addressRegion"]), name(postal["addressCountry"])} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// name returns the name of an object like an Organization, which may be
+// given as a plain string as well.
+func name(value interface{}) string {
+	if object, ok := value.(map[string]interface{}); ok {
+		return text(object["name"])
+	}
+
+	return text(value)
+}
+
+func list(value interface{}) []interface{} {
+	if items, ok := value.([]interface{}); ok {
+		return items
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	return []interface{}{value}
+}
+
+func text(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return clean(s)
+	}
+
+	return ""
+}
+
+func number(value interface{}) float64 {
+	switch n := value.(type) {
+	case float64:
+		return n
+	case string:
+		parsed, _ := strconv.ParseFloat(strings.ReplaceAll(n, ",", ""), 64)
+		return parsed
+	}
+
+	return 0
+}
+
+// clean decodes the entities and collapses the whitespace of a text.
+func clean(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
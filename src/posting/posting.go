@@ -0,0 +1,128 @@
+// Package posting fetches the pages of job postings and extracts the details
+// of the jobs from their schema.org JobPosting data and OpenGraph tags.
+package posting
+
+import (
+	"context"
+	"errors"
+	"flhansen/application-manager/application-service/src/netguard"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNotHTML is returned, if the url does not lead to an HTML page.
+	ErrNotHTML = errors.New("the job posting is not an HTML page")
+
+	// ErrTooLarge is returned, if the page exceeds the maximum size.
+	ErrTooLarge = errors.New("the job posting is too large")
+
+	// ErrForbiddenAddress is returned, if the url leads to a loopback,
+	// private or link-local address.
+	ErrForbiddenAddress = errors.New("the job posting is not hosted on a public address")
+)
+
+const (
+	// defaultMaxSize limits the size of a page in bytes.
+	defaultMaxSize = 2 << 20
+
+	// maxRedirects limits the number of redirects followed to the page.
+	maxRedirects = 5
+)
+
+// Page is the fetched page of a job posting. Url is the url of the page
+// after redirects.
+type Page struct {
+	Url         string
+	ContentType string
+	Content     []byte
+	FetchedAt   time.Time
+}
+
+// Fetcher fetches the page of a job posting.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (Page, error)
+}
+
+// HTTPFetcher fetches job postings over HTTP. The urls are provided by the
+// users, so unless private networks are allowed, the fetcher refuses to
+// connect to addresses, which are not public, including redirects.
+type HTTPFetcher struct {
+	Client  *http.Client
+	MaxSize int64
+}
+
+// NewHTTPFetcher creates a fetcher, which gives up on a posting after ten
+// seconds. Private networks should only be allowed for tests.
+func NewHTTPFetcher(allowPrivateNetworks bool) HTTPFetcher {
+	dialer := netguard.Dialer(allowPrivateNetworks, ErrForbiddenAddress)
+
+	return HTTPFetcher{
+		Client: &http.Client{
+			Timeout: 10 * time.Second,
+			// Proxies are not used, because they would bypass the check of
+			// the addresses.
+			Transport: &http.Transport{
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: 5 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("cannot follow a redirect to %s", req.URL.Scheme)
+				}
+
+				return nil
+			},
+		},
+		MaxSize: defaultMaxSize,
+	}
+}
+
+func (f HTTPFetcher) Fetch(ctx context.Context, url string) (Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Page{}, err
+	}
+
+	req.Header.Set("User-Agent", "application-manager-postings")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return Page{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Page{}, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return Page{}, ErrNotHTML
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, f.MaxSize+1))
+	if err != nil {
+		return Page{}, err
+	}
+
+	if int64(len(content)) > f.MaxSize {
+		return Page{}, ErrTooLarge
+	}
+
+	return Page{
+		Url:         resp.Request.URL.String(),
+		ContentType: resp.Header.Get("Content-Type"),
+		Content:     content,
+		FetchedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,139 @@
+package posting
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Careers</title>
+	<meta property="og:title" content="Ignored">
+	<script type="application/ld+json">
+	{
+		"@context": "https://schema.org",
+		"@graph": [
+			{"@type": "Organization", "name": "Other"},
+			{
+				"@type": "JobPosting",
+				"title": "Go Developer &amp; SRE",
+				"hiringOrganization": {"@type": "Organization", "name": "ACME"},
+				"jobLocation": [
+					{"@type": "Place", "address": {"@type": "PostalAddress", "addressLocality": "Berlin", "addressCountry": {"@type": "Country", "name": "DE"}}},
+					{"@type": "Place", "address": {"@type": "PostalAddress", "addressLocality": "Hamburg", "addressRegion": "HH"}}
+				],
+				"jobLocationType": "TELECOMMUTE",
+				"baseSalary": {
+					"@type": "MonetaryAmount",
+					"currency": "EUR",
+					"value": {"@type": "QuantitativeValue", "minValue": 60000, "maxValue": "80,000", "unitText": "YEAR"}
+				}
+			}
+		]
+	}
+	</script>
+</head>
+<body></body>
+</html>`
+
+func TestExtractJobPosting(t *testing.T) {
+	posting := Extract([]byte(testPage))
+
+	assert.Equal(t, "Go Developer & SRE", posting.Title)
+	assert.Equal(t, "ACME", posting.Company)
+	assert.Equal(t, "Berlin, DE; Hamburg, HH", posting.Location)
+	assert.True(t, posting.Remote)
+	assert.Equal(t, &Salary{Currency: "EUR", Min: 60000, Max: 80000, Unit: "YEAR"}, posting.Salary)
+}
+
+func TestExtractOpenGraph(t *testing.T) {
+	posting := Extract([]byte(`<html><head>
+		<script type="application/ld+json">{"@type": "WebPage"}</script>
+		<script type="application/ld+json">not json</script>
+		<meta content='Go Developer at ACME &amp; Co' property='og:title' />
+		<title>Jobs</title>
+	</head></html>`))
+
+	assert.Equal(t, Posting{Title: "Go Developer", Company: "ACME & Co"}, posting)
+
+	posting = Extract([]byte(`<html><head><title>
+		Rust Developer
+	</title></head></html>`))
+	assert.Equal(t, Posting{Title: "Rust Developer"}, posting)
+
+	assert.Equal(t, Posting{}, Extract([]byte("plain text")))
+}
+
+func TestExtractSingleSalary(t *testing.T) {
+	posting := Extract([]byte(`<script type="application/ld+json">[{
+		"@type": ["JobPosting"],
+		"title": "Go Developer",
+		"hiringOrganization": "ACME",
+		"jobLocation": {"@type": "Place", "address": "Berlin"},
+		"baseSalary": {"@type": "MonetaryAmount", "currency": "USD", "value": {"value": 40, "unitText": "HOUR"}}
+	}]</script>`))
+
+	assert.Equal(t, "ACME", posting.Company)
+	assert.Equal(t, "Berlin", posting.Location)
+	assert.False(t, posting.Remote)
+	assert.Equal(t, &Salary{Currency: "USD", Min: 40, Max: 40, Unit: "HOUR"}, posting.Salary)
+}
+
+func TestFetch(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/jobs/1", http.StatusFound)
+		case "/jobs/1":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(testPage))
+		case "/large":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(strings.Repeat("a", 101)))
+		case "/feed":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("{}"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher(true)
+	page, err := fetcher.Fetch(context.Background(), server.URL+"/moved")
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/jobs/1", page.Url)
+	assert.Equal(t, "text/html; charset=utf-8", page.ContentType)
+	assert.Equal(t, testPage, string(page.Content))
+	assert.False(t, page.FetchedAt.IsZero())
+	assert.Equal(t, "application-manager-postings", userAgent)
+
+	_, err = fetcher.Fetch(context.Background(), server.URL+"/feed")
+	assert.ErrorIs(t, err, ErrNotHTML)
+
+	_, err = fetcher.Fetch(context.Background(), server.URL+"/missing")
+	assert.NotNil(t, err)
+
+	fetcher.MaxSize = 100
+	_, err = fetcher.Fetch(context.Background(), server.URL+"/large")
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestFetchRefusesPrivateNetworks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPFetcher(false).Fetch(context.Background(), server.URL)
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+}
@@ -2,49 +2,290 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"flhansen/application-manager/application-service/src/controller"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
 
+// dateLayout is the format of dates in query parameters.
+const dateLayout = "2006-01-02"
+
+// parseIds parses a comma separated list of ids. Repeated query parameters
+// are accepted as well.
+func parseIds(values []string) ([]int, error) {
+	var ids []int
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part == "" {
+				continue
+			}
+
+			id, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, err
+			}
+
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func parseApplicationQuery(values url.Values) (controller.ApplicationQuery, error) {
+	var query controller.ApplicationQuery
+	var err error
+
+	if limit := values.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 1 {
+			return query, fmt.Errorf("limit must be a positive number")
+		}
+
+		if query.Limit > controller.MaxPageSize {
+			query.Limit = controller.MaxPageSize
+		}
+	}
+
+	query.Cursor = values.Get("cursor")
+	query.Company = values.Get("company")
+
+	if query.Sort, err = controller.ParseSort(values.Get("sort")); err != nil {
+		return query, err
+	}
+
+	if query.StatusIds, err = parseIds(values["statusId"]); err != nil {
+		return query, fmt.Errorf("statusId must be a list of numbers")
+	}
+
+	if query.WorkTypeIds, err = parseIds(values["workTypeId"]); err != nil {
+		return query, fmt.Errorf("workTypeId must be a list of numbers")
+	}
+
+	if from := values.Get("submissionDateFrom"); from != "" {
+		if query.SubmissionDateFrom, err = time.Parse(dateLayout, from); err != nil {
+			return query, fmt.Errorf("submissionDateFrom must be a date like 2022-06-30")
+		}
+	}
+
+	if to := values.Get("submissionDateTo"); to != "" {
+		if query.SubmissionDateTo, err = time.Parse(dateLayout, to); err != nil {
+			return query, fmt.Errorf("submissionDateTo must be a date like 2022-06-30")
+		}
+	}
+
+	query.Tags = controller.ParseTags(strings.Join(values["tags"], ","))
+
+	if stale := values.Get("stale"); stale != "" {
+		value, err := strconv.ParseBool(stale)
+		if err != nil {
+			return query, fmt.Errorf("stale must be true or false")
+		}
+
+		query.Stale = &value
+	}
+
+	switch values.Get("tagMatch") {
+	case "", "any":
+	case "all":
+		query.MatchAllTags = true
+	default:
+		return query, fmt.Errorf("tagMatch must be any or all")
+	}
+
+	return query, nil
+}
+
 func (s ApplicationService) handleGetApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	query, err := parseApplicationQuery(r.URL.Query())
+	if err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// We don't need to check, if userId is not a number, because the
 	// authorization middleware does this check for us
 	userId, _ := strconv.Atoi(p.ByName("userId"))
-	applications, _ := s.ApplicationController.GetApplications(userId)
+	err = s.parseCustomFieldQuery(userId, r.URL.Query(), &query)
+
+	var page controller.ApplicationPage
+	if err == nil {
+		page, err = s.ApplicationController.QueryApplications(userId, query)
+	}
+
+	if err == nil {
+		err = s.withDetails(page.Applications)
+	}
+
+	if err != nil {
+		if errors.Is(err, controller.ErrInvalidQuery) {
+			ApiResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ApiResponse(w, "Could not fetch applications", http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor interface{}
+	if page.NextCursor != "" {
+		nextCursor = page.NextCursor
+	}
 
 	fmt.Fprint(w, NewApiResponseObject(200, "Fetched all applications", map[string]interface{}{
-		"applications": applications,
+		"applications": page.Applications,
+		"nextCursor":   nextCursor,
+		"total":        page.Total,
+	}))
+}
+
+func (s ApplicationService) handleSearchApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		ApiResponse(w, "The search query must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			ApiResponse(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+
+		if limit > controller.MaxPageSize {
+			limit = controller.MaxPageSize
+		}
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	results, err := s.ApplicationController.SearchApplications(userId, query, limit)
+	if err != nil {
+		ApiResponse(w, "Could not search applications", http.StatusInternalServerError)
+		return
+	}
+
+	applications := make([]controller.Application, len(results))
+	for i, result := range results {
+		applications[i] = result.Application
+	}
+
+	if err := s.withDetails(applications); err != nil {
+		ApiResponse(w, "Could not search applications", http.StatusInternalServerError)
+		return
+	}
+
+	for i := range results {
+		results[i].Application = applications[i]
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Searched applications", map[string]interface{}{
+		"results": results,
 	}))
 }
 
 func (s ApplicationService) handleGetApplication(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	applications := []controller.Application{application}
+	if err := s.withDetails(applications); err != nil {
+		ApiResponse(w, "Could not fetch application", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched application", map[string]interface{}{
+		"application": applications[0],
+	}))
+}
+
+// withDetails fills in the data of the applications, which is not stored in
+// the application table.
+func (s ApplicationService) withDetails(applications []controller.Application) error {
+	if err := s.withDocumentVersions(applications); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, application := range applications {
+		applications[i].DaysInStatus = daysBetween(application.StatusChangedAt, now)
+	}
+
+	return s.withCustomFields(applications)
+}
+
+// daysBetween returns the number of full days from one time to another.
+func daysBetween(from time.Time, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+
+	return int(to.Sub(from) / (24 * time.Hour))
+}
+
+// ownedApplication fetches the application referenced by the id parameter
+// and makes sure, it belongs to the requesting user. Otherwise an error
+// response is written and false is returned.
+func (s ApplicationService) ownedApplication(w http.ResponseWriter, p httprouter.Params) (controller.Application, bool) {
 	applicationId, err := strconv.Atoi(p.ByName("id"))
 	if err != nil {
 		ApiResponse(w, "Error while parsing the application id", http.StatusBadRequest)
-		return
+		return controller.Application{}, false
 	}
 
 	application, err := s.ApplicationController.GetApplication(applicationId)
 	if err != nil {
 		ApiResponse(w, "This application does not exist", http.StatusBadRequest)
-		return
+		return controller.Application{}, false
 	}
 
 	userId, _ := strconv.Atoi(p.ByName("userId"))
 	if application.UserId != userId {
 		ApiResponse(w, "You are not allowed to get information about this application", http.StatusUnauthorized)
+		return controller.Application{}, false
+	}
+
+	return application, true
+}
+
+func (s ApplicationService) handleGetStatusHistory(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
 		return
 	}
 
-	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched application", map[string]interface{}{
-		"application": application,
+	history, err := s.ApplicationController.GetStatusHistory(application.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch the status history", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched status history", map[string]interface{}{
+		"history": history,
 	}))
 }
 
+// isJobPostingUrlValid reports whether the job posting url of an application
+// is empty or an absolute http or https url, which fits into its column.
+func isJobPostingUrlValid(jobPostingUrl string) bool {
+	if jobPostingUrl == "" {
+		return true
+	}
+
+	target, err := url.Parse(jobPostingUrl)
+	return err == nil && (target.Scheme == "http" || target.Scheme == "https") && target.Host != "" && len(jobPostingUrl) <= 2000
+}
+
 func (s ApplicationService) handleCreateApplication(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 	var applicationRequest controller.Application
 	if err := json.NewDecoder(r.Body).Decode(&applicationRequest); err != nil {
@@ -55,6 +296,29 @@ func (s ApplicationService) handleCreateApplication(w http.ResponseWriter, r *ht
 	// Make sure that the application is assigned to the requesting user.
 	applicationRequest.UserId, _ = strconv.Atoi(p.ByName("userId"))
 
+	if !s.isStatusUsable(applicationRequest.StatusId, applicationRequest.UserId) {
+		ApiResponse(w, "You cannot use a status of another user", http.StatusBadRequest)
+		return
+	}
+
+	if !isJobPostingUrlValid(applicationRequest.JobPostingUrl) {
+		ApiResponse(w, "The job posting url must be an absolute http or https url of up to 2000 characters", http.StatusBadRequest)
+		return
+	}
+
+	if !s.resolveCompany(w, &applicationRequest) {
+		return
+	}
+
+	if !s.isDocumentVersionUsable(applicationRequest) {
+		ApiResponse(w, "The document version does not exist", http.StatusBadRequest)
+		return
+	}
+
+	if !s.parseCustomFields(w, &applicationRequest) {
+		return
+	}
+
 	id, err := s.ApplicationController.InsertApplication(applicationRequest)
 	if err != nil {
 		ApiResponse(w, "Could not create application", http.StatusInternalServerError)
@@ -62,8 +326,11 @@ func (s ApplicationService) handleCreateApplication(w http.ResponseWriter, r *ht
 	}
 
 	newApplication, _ := s.ApplicationController.GetApplication(id)
+	applications := []controller.Application{newApplication}
+	s.withDetails(applications)
+
 	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Application created", map[string]interface{}{
-		"application": newApplication,
+		"application": applications[0],
 	}))
 }
 
@@ -74,7 +341,38 @@ func (s ApplicationService) handleDeleteApplication(w http.ResponseWriter, r *ht
 		return
 	}
 
-	s.ApplicationController.DeleteApplication(applicationId)
+	application, err := s.ApplicationController.GetApplication(applicationId)
+	if err != nil {
+		ApiResponse(w, "This application does not exist", http.StatusBadRequest)
+		return
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if application.UserId != userId {
+		ApiResponse(w, "You cannot delete an application from another user", http.StatusUnauthorized)
+		return
+	}
+
+	attachments, err := s.AttachmentController.GetAttachments(applicationId)
+	if err != nil {
+		ApiResponse(w, "Could not delete application", http.StatusInternalServerError)
+		return
+	}
+
+	// The interviews and attachments of the application are deleted as well.
+	if err := s.ApplicationController.DeleteApplication(applicationId); err != nil {
+		if errors.Is(err, controller.ErrNotFound) {
+			ApiResponse(w, "This application does not exist", http.StatusBadRequest)
+			return
+		}
+
+		ApiResponse(w, "Could not delete application", http.StatusInternalServerError)
+		return
+	}
+
+	for _, attachment := range attachments {
+		s.deleteBlobs(r.Context(), attachment.BlobKey)
+	}
 
 	ApiResponse(w, "Application deleted", http.StatusOK)
 }
@@ -98,7 +396,45 @@ func (s ApplicationService) handleUpdateApplication(w http.ResponseWriter, r *ht
 		return
 	}
 
-	s.ApplicationController.UpdateApplication(applicationRequest)
+	// The owner cannot be changed, the acting user is recorded as the owner
+	// in the status history.
+	applicationRequest.UserId = userId
+
+	if !s.isStatusUsable(applicationRequest.StatusId, userId) {
+		ApiResponse(w, "You cannot use a status of another user", http.StatusBadRequest)
+		return
+	}
+
+	if !isJobPostingUrlValid(applicationRequest.JobPostingUrl) {
+		ApiResponse(w, "The job posting url must be an absolute http or https url of up to 2000 characters", http.StatusBadRequest)
+		return
+	}
+
+	if !s.resolveCompany(w, &applicationRequest) {
+		return
+	}
+
+	if !s.isDocumentVersionUsable(applicationRequest) {
+		ApiResponse(w, "The document version does not exist", http.StatusBadRequest)
+		return
+	}
+
+	if !s.parseCustomFields(w, &applicationRequest) {
+		return
+	}
+
+	// Reopening an application is only possible through its transitions
+	// endpoint.
+	if !s.checkTransition(w, userId, application.StatusId, applicationRequest.StatusId, false) {
+		return
+	}
+
+	if err := s.ApplicationController.UpdateApplicationFromStatus(applicationRequest, application.StatusId); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+
+	s.notifyStatusChange(applicationRequest, application.StatusId)
 	ApiResponse(w, "Application updated", http.StatusOK)
 }
 
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// handleExportUserData downloads a ZIP archive with a JSON file for every
+// table, which contains the rows of the user. The files of the attachments
+// and documents are downloaded through their own endpoints.
+func (s ApplicationService) handleExportUserData(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+
+	tables, err := s.AccountController.ExportUserData(userId)
+	if err != nil {
+		ApiResponse(w, "Could not export the data of the user", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="user-data.zip"`)
+
+	archive := zip.NewWriter(w)
+	for _, table := range tables {
+		file, err := archive.Create(table.Name + ".json")
+		if err == nil {
+			var data []byte
+			if data, err = json.MarshalIndent(table.Rows, "", "  "); err == nil {
+				_, err = file.Write(data)
+			}
+		}
+
+		if err != nil {
+			log.Printf("Could not export the data of user %d: %v", userId, err)
+			panic(http.ErrAbortHandler)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		log.Printf("Could not export the data of user %d: %v", userId, err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// handleEraseUserData erases all data of the user. The rows are deleted in a
+// single transaction, which records the erasure in the audit log, the files
+// are deleted afterwards. The account itself is managed by the auth service.
+func (s ApplicationService) handleEraseUserData(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+
+	erased, err := s.AccountController.EraseUserData(userId)
+	if err != nil {
+		ApiResponse(w, "Could not erase the data of the user", http.StatusInternalServerError)
+		return
+	}
+
+	s.deleteBlobs(r.Context(), erased.BlobKeys...)
+	log.Printf("Erased the data of user %d", userId)
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "User data erased", map[string]interface{}{
+		"rows": erased.Rows,
+	}))
+}
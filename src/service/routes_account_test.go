@@ -0,0 +1,84 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/controller"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteExportUserData(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Go Developer"})
+	store.InsertApplication(controller.Application{UserId: 2, WorkTypeId: 1, StatusId: 2, JobTitle: "Rust Developer"})
+	store.InsertTag(controller.Tag{UserId: 1, Name: "Go"})
+
+	resp, _ := serveTestRequest(t, s, http.MethodGet, "/api/me/export", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/zip", resp.Header().Get("Content-Type"))
+
+	archive, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]map[string]interface{}{}
+	for _, file := range archive.File {
+		reader, _ := file.Open()
+		content, _ := io.ReadAll(reader)
+		reader.Close()
+
+		var rows []map[string]interface{}
+		assert.Nil(t, json.Unmarshal(content, &rows), file.Name)
+		files[file.Name] = rows
+	}
+
+	assert.Contains(t, files, "calendar_token.json")
+	assert.Contains(t, files, "audit_log.json")
+	assert.Equal(t, 1, len(files["application.json"]))
+	assert.Equal(t, float64(applicationId), files["application.json"][0]["id"])
+	assert.Equal(t, "Go Developer", files["application.json"][0]["job_title"])
+	assert.Equal(t, 1, len(files["application_status_history.json"]))
+	assert.Equal(t, 1, len(files["tag.json"]))
+	assert.Empty(t, files["contact.json"])
+}
+
+func TestRouteEraseUserData(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Go Developer"})
+	otherId, _ := store.InsertApplication(controller.Application{UserId: 2, WorkTypeId: 1, StatusId: 2, JobTitle: "Rust Developer"})
+	store.InsertAttachment(controller.Attachment{ApplicationId: applicationId, UserId: 1, FileName: "cv.pdf", Size: 2, BlobKey: "cv"})
+	s.Blobs.Put(context.Background(), "cv", strings.NewReader("cv"), 2, "application/pdf")
+
+	resp, res := serveTestRequest(t, s, http.MethodDelete, "/api/me", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(1), res["rows"].(map[string]interface{})["application"])
+	assert.Equal(t, float64(1), res["rows"].(map[string]interface{})["attachment"])
+
+	_, err := store.GetApplication(applicationId)
+	assert.ErrorIs(t, err, controller.ErrNotFound)
+
+	_, err = s.Blobs.Get(context.Background(), "cv")
+	assert.NotNil(t, err)
+
+	_, err = store.GetApplication(otherId)
+	assert.Nil(t, err)
+
+	// The erasure is recorded in the audit log, which is kept.
+	tables, _ := store.ExportUserData(1)
+	for _, table := range tables {
+		if table.Name == "audit_log" {
+			assert.Equal(t, 1, len(table.Rows))
+			assert.Contains(t, string(table.Rows[0]), controller.AuditUserErased)
+		} else {
+			assert.Empty(t, table.Rows, table.Name)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/storage"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// allowedContentTypes are the sniffed content types, which may be uploaded.
+var allowedContentTypes = map[string]bool{
+	"application/pdf":           true,
+	"text/plain; charset=utf-8": true,
+	"application/zip":           true,
+	"image/png":                 true,
+	"image/jpeg":                true,
+}
+
+// zipContentTypes refine the content type of office documents, which are
+// sniffed as zip archives.
+var zipContentTypes = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".odt":  "application/vnd.oasis.opendocument.text",
+}
+
+// sniffContentType detects the content type of the file from its content.
+// The content type sent by the client is ignored.
+func sniffContentType(fileName string, content []byte) (string, bool) {
+	contentType := http.DetectContentType(content)
+	if !allowedContentTypes[contentType] {
+		return contentType, false
+	}
+
+	if contentType == "application/zip" {
+		if refined, ok := zipContentTypes[strings.ToLower(filepath.Ext(fileName))]; ok {
+			return refined, true
+		}
+	}
+
+	return contentType, true
+}
+
+// newBlobKey creates a random key below the prefix and the user, so file
+// names never end up in the blob store.
+func newBlobKey(prefix string, userId int) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%d/%s", prefix, userId, hex.EncodeToString(random)), nil
+}
+
+// deleteBlobs removes the contents of deleted files. Failures only leave
+// orphaned blobs behind, so they are logged instead of reported.
+func (s ApplicationService) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.Blobs.Delete(ctx, key); err != nil {
+			log.Printf("Could not delete blob %s: %v", key, err)
+		}
+	}
+}
+
+// ownedAttachment fetches the attachment referenced by the documentId
+// parameter and makes sure, it belongs to the application of the requesting
+// user. Otherwise an error response is written and false is returned.
+func (s ApplicationService) ownedAttachment(w http.ResponseWriter, p httprouter.Params) (controller.Attachment, bool) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return controller.Attachment{}, false
+	}
+
+	attachmentId, err := strconv.Atoi(p.ByName("documentId"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the document id", http.StatusBadRequest)
+		return controller.Attachment{}, false
+	}
+
+	attachment, err := s.AttachmentController.GetAttachment(attachmentId)
+	if err != nil || attachment.ApplicationId != application.Id {
+		ApiResponse(w, "This document does not exist", http.StatusBadRequest)
+		return controller.Attachment{}, false
+	}
+
+	return attachment, true
+}
+
+func (s ApplicationService) handleGetAttachments(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	attachments, err := s.AttachmentController.GetAttachments(application.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch documents", http.StatusInternalServerError)
+		return
+	}
+
+	if attachments == nil {
+		attachments = []controller.Attachment{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched documents", map[string]interface{}{
+		"documents": attachments,
+	}))
+}
+
+// upload is a file uploaded as multipart/form-data together with the other
+// form values of the request.
+type upload struct {
+	fileName    string
+	contentType string
+	content     []byte
+	values      url.Values
+}
+
+// maxFormValueSize limits the size of the form values next to the file.
+const maxFormValueSize = 1024
+
+// storageUsage sums up the sizes of all files of the user, including the
+// snapshots of job postings.
+func (s ApplicationService) storageUsage(userId int) (int64, error) {
+	attachmentUsage, err := s.AttachmentController.GetAttachmentUsage(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	documentUsage, err := s.DocumentController.GetDocumentUsage(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	postingUsage, err := s.PostingController.GetPostingSnapshotUsage(userId)
+	return attachmentUsage + documentUsage + postingUsage, err
+}
+
+// readUpload reads the file part and the form values of a multipart request.
+// Files larger than the maximum file size are rejected before they are read
+// completely. The content type of the file is sniffed and the quota of the
+// user is checked. Otherwise an error response is written and false is
+// returned.
+func (s ApplicationService) readUpload(w http.ResponseWriter, r *http.Request, userId int) (upload, bool) {
+	maxFileSize := s.Config.Documents.maxFileSize()
+
+	// Leave some room for the other parts and headers of the request.
+	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		ApiResponse(w, "The document must be uploaded as multipart/form-data", http.StatusBadRequest)
+		return upload{}, false
+	}
+
+	result := upload{values: url.Values{}}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			ApiResponse(w, "Could not read the uploaded document", http.StatusBadRequest)
+			return upload{}, false
+		}
+
+		if part.FormName() != "file" {
+			value, err := io.ReadAll(io.LimitReader(part, maxFormValueSize))
+			if err != nil {
+				ApiResponse(w, "Could not read the uploaded document", http.StatusBadRequest)
+				return upload{}, false
+			}
+
+			result.values.Add(part.FormName(), string(value))
+			continue
+		}
+
+		result.fileName = strings.TrimSpace(filepath.Base(filepath.Clean("/" + part.FileName())))
+		if result.fileName == "" || result.fileName == "/" || len(result.fileName) > 255 {
+			ApiResponse(w, "The file name must contain 1 to 255 characters", http.StatusBadRequest)
+			return upload{}, false
+		}
+
+		result.content, err = io.ReadAll(io.LimitReader(part, maxFileSize+1))
+		if int64(len(result.content)) > maxFileSize {
+			ApiResponse(w, fmt.Sprintf("The document must not be larger than %d bytes", maxFileSize), http.StatusRequestEntityTooLarge)
+			return upload{}, false
+		}
+
+		if err != nil {
+			ApiResponse(w, "Could not read the uploaded document", http.StatusBadRequest)
+			return upload{}, false
+		}
+	}
+
+	if result.content == nil {
+		ApiResponse(w, "The request does not contain a file", http.StatusBadRequest)
+		return upload{}, false
+	}
+
+	var ok bool
+	if result.contentType, ok = sniffContentType(result.fileName, result.content); !ok {
+		ApiResponse(w, fmt.Sprintf("Documents of type %s are not supported", result.contentType), http.StatusUnsupportedMediaType)
+		return upload{}, false
+	}
+
+	usage, err := s.storageUsage(userId)
+	if err != nil {
+		ApiResponse(w, "Could not upload document", http.StatusInternalServerError)
+		return upload{}, false
+	}
+
+	if usage+int64(len(result.content)) > s.Config.Documents.userQuota() {
+		ApiResponse(w, "The document exceeds your storage quota", http.StatusRequestEntityTooLarge)
+		return upload{}, false
+	}
+
+	return result, true
+}
+
+// storeUpload writes the content of the upload to the blob store.
+func (s ApplicationService) storeUpload(r *http.Request, key string, file upload) error {
+	return s.Blobs.Put(r.Context(), key, bytes.NewReader(file.content), int64(len(file.content)), file.contentType)
+}
+
+func (s ApplicationService) handleUploadAttachment(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	file, ok := s.readUpload(w, r, application.UserId)
+	if !ok {
+		return
+	}
+
+	attachment := controller.Attachment{
+		ApplicationId: application.Id,
+		UserId:        application.UserId,
+		FileName:      file.fileName,
+		ContentType:   file.contentType,
+		Size:          int64(len(file.content)),
+	}
+
+	var err error
+	if attachment.BlobKey, err = newBlobKey("attachments", attachment.UserId); err != nil {
+		ApiResponse(w, "Could not upload document", http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.storeUpload(r, attachment.BlobKey, file); err != nil {
+		ApiResponse(w, "Could not store document", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := s.AttachmentController.InsertAttachment(attachment)
+	if err != nil {
+		s.deleteBlobs(r.Context(), attachment.BlobKey)
+		ApiResponse(w, "Could not upload document", http.StatusInternalServerError)
+		return
+	}
+
+	newAttachment, _ := s.AttachmentController.GetAttachment(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Document uploaded", map[string]interface{}{
+		"document": newAttachment,
+	}))
+}
+
+// serveBlob sends the content of a file as a download.
+func (s ApplicationService) serveBlob(w http.ResponseWriter, r *http.Request, key string, fileName string, contentType string, size int64) {
+	blob, err := s.Blobs.Get(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			ApiResponse(w, "The content of this document is missing", http.StatusNotFound)
+			return
+		}
+
+		ApiResponse(w, "Could not download document", http.StatusInternalServerError)
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	io.Copy(w, blob)
+}
+
+func (s ApplicationService) handleDownloadAttachment(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	attachment, ok := s.ownedAttachment(w, p)
+	if !ok {
+		return
+	}
+
+	s.serveBlob(w, r, attachment.BlobKey, attachment.FileName, attachment.ContentType, attachment.Size)
+}
+
+func (s ApplicationService) handleDeleteAttachment(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	attachment, ok := s.ownedAttachment(w, p)
+	if !ok {
+		return
+	}
+
+	if err := s.AttachmentController.DeleteAttachment(attachment.Id); err != nil {
+		if errors.Is(err, controller.ErrNotFound) {
+			ApiResponse(w, "This document does not exist", http.StatusBadRequest)
+			return
+		}
+
+		ApiResponse(w, "Could not delete document", http.StatusInternalServerError)
+		return
+	}
+
+	s.deleteBlobs(r.Context(), attachment.BlobKey)
+	ApiResponse(w, "Document deleted", http.StatusOK)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/auth"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/storage"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPdf = "%PDF-1.4\n%curriculum vitae"
+
+// serveTestUpload uploads a file as multipart/form-data authenticated as the
+// given user.
+func serveTestUpload(t *testing.T, s ApplicationService, path string, userId int, fileName string, content string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", fileName)
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	token, err := auth.GenerateToken(userId, "testuser", jwt.SigningMethodHS256, s.Config.Jwt.SignKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Add("Authorization", token)
+	recorder := httptest.NewRecorder()
+	s.Router.ServeHTTP(recorder, req)
+
+	var res map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &res)
+
+	return recorder, res
+}
+
+func TestRouteAttachments(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	path := fmt.Sprintf("/api/applications/%d/documents", applicationId)
+
+	resp, res := serveTestUpload(t, s, path, 1, "cv.pdf", testPdf)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	document := res["document"].(map[string]interface{})
+	assert.Equal(t, "application/pdf", document["contentType"])
+	assert.Equal(t, "cv.pdf", document["fileName"])
+	assert.Nil(t, document["blobKey"])
+	documentPath := fmt.Sprintf("%s/%v", path, document["id"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, path, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["documents"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, documentPath, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, testPdf, resp.Body.String())
+	assert.Equal(t, "application/pdf", resp.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=cv.pdf`, resp.Header().Get("Content-Disposition"))
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, documentPath, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 0, s.Blobs.(*storage.MemoryBlobStore).Len())
+}
+
+func TestRouteUploadAttachmentNotOwner(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	resp, _ := serveTestUpload(t, s, fmt.Sprintf("/api/applications/%d/documents", applicationId), 2, "cv.pdf", testPdf)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestRouteUploadAttachmentUnsupportedType(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	// The file name does not matter, the content is sniffed.
+	resp, _ := serveTestUpload(t, s, fmt.Sprintf("/api/applications/%d/documents", applicationId), 1, "cv.pdf", "<html><script>alert(1)</script></html>")
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+}
+
+func TestRouteUploadAttachmentLimits(t *testing.T) {
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
+		Jwt:       JwtConfig{SignKey: []byte("supersecretsignkey")},
+		Documents: DocumentConfig{MaxFileSize: 32, UserQuota: 48},
+	}, MemoryRepositories(store))
+
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	path := fmt.Sprintf("/api/applications/%d/documents", applicationId)
+
+	resp, _ := serveTestUpload(t, s, path, 1, "large.pdf", testPdf+string(make([]byte, 32)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+
+	resp, _ = serveTestUpload(t, s, path, 1, "first.pdf", testPdf)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res := serveTestUpload(t, s, path, 1, "second.pdf", testPdf)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	assert.Contains(t, res["message"], "quota")
+}
+
+func TestRouteDeleteApplicationDeletesBlobs(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	serveTestUpload(t, s, fmt.Sprintf("/api/applications/%d/documents", applicationId), 1, "cv.pdf", testPdf)
+
+	resp, _ := serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/applications/%d", applicationId), 1, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 0, s.Blobs.(*storage.MemoryBlobStore).Len())
+}
+
+func TestSniffContentType(t *testing.T) {
+	contentType, ok := sniffContentType("cv.docx", []byte("PK\x03\x04word/document.xml"))
+	assert.True(t, ok)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", contentType)
+
+	contentType, ok = sniffContentType("notes.txt", []byte("Just some notes"))
+	assert.True(t, ok)
+	assert.Equal(t, "text/plain; charset=utf-8", contentType)
+
+	_, ok = sniffContentType("setup.exe", []byte("MZ\x90\x00\x03\x00\x00\x00"))
+	assert.False(t, ok)
+}
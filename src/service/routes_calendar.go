@@ -0,0 +1,227 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/ical"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// calendarExtension is appended to the token in the url of a feed, so
+	// calendar apps recognise the format.
+	calendarExtension = ".ics"
+
+	// uidDomain makes the UIDs of the events globally unique.
+	uidDomain = "application-manager"
+)
+
+// newCalendarToken creates a random token of 64 hex characters.
+func newCalendarToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+func calendarResponse(token string) map[string]interface{} {
+	return map[string]interface{}{
+		"token": token,
+		"url":   "/api/calendar/" + token + calendarExtension,
+	}
+}
+
+func applicationTitle(application controller.Application) string {
+	if application.CompanyName == "" {
+		return application.JobTitle
+	}
+
+	return fmt.Sprintf("%s at %s", application.JobTitle, application.CompanyName)
+}
+
+// buildCalendar collects the submission dates, the start dates of
+// applications in an accepted status, the interviews and the reminders of
+// the user. The UIDs are derived from the ids, so updates replace the events
+// in calendar apps.
+func (s ApplicationService) buildCalendar(userId int) (ical.Calendar, error) {
+	calendar := ical.Calendar{Name: "Applications"}
+
+	applications, err := s.ApplicationController.GetApplications(userId)
+	if err != nil {
+		return calendar, err
+	}
+
+	statuses, err := s.TypesController.GetUsableStatuses(userId)
+	if err != nil {
+		return calendar, err
+	}
+
+	accepted := map[int]bool{}
+	for _, status := range statuses {
+		accepted[status.Id] = status.Accepted
+	}
+
+	byId := map[int]controller.Application{}
+	for _, application := range applications {
+		byId[application.Id] = application
+
+		if !application.SubmissionDate.IsZero() {
+			calendar.Events = append(calendar.Events, ical.Event{
+				Uid:         fmt.Sprintf("application-%d-submission@%s", application.Id, uidDomain),
+				Start:       application.SubmissionDate,
+				AllDay:      true,
+				Summary:     "Applied: " + applicationTitle(application),
+				Description: application.Commentary,
+			})
+		}
+
+		if !application.StartDate.IsZero() && accepted[application.StatusId] {
+			calendar.Events = append(calendar.Events, ical.Event{
+				Uid:     fmt.Sprintf("application-%d-start@%s", application.Id, uidDomain),
+				Start:   application.StartDate,
+				AllDay:  true,
+				Summary: "Start: " + applicationTitle(application),
+			})
+		}
+	}
+
+	interviews, err := s.InterviewController.GetUserInterviews(userId)
+	if err != nil {
+		return calendar, err
+	}
+
+	for _, interview := range interviews {
+		if interview.Outcome == controller.OutcomeCancelled {
+			continue
+		}
+
+		duration := time.Duration(interview.DurationMinutes) * time.Minute
+		if duration == 0 {
+			duration = time.Hour
+		}
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			Uid:         fmt.Sprintf("interview-%d@%s", interview.Id, uidDomain),
+			Start:       interview.ScheduledAt,
+			End:         interview.ScheduledAt.Add(duration),
+			Summary:     fmt.Sprintf("Interview (%s): %s", interview.Type, applicationTitle(byId[interview.ApplicationId])),
+			Description: interview.Notes,
+			Location:    interview.Location,
+		})
+	}
+
+	reminders, err := s.ReminderController.GetUserReminders(userId)
+	if err != nil {
+		return calendar, err
+	}
+
+	for _, reminder := range reminders {
+		calendar.Events = append(calendar.Events, ical.Event{
+			Uid:         fmt.Sprintf("reminder-%d@%s", reminder.Id, uidDomain),
+			Start:       reminder.RemindAt,
+			End:         reminder.RemindAt.Add(15 * time.Minute),
+			Summary:     "Follow up: " + applicationTitle(byId[reminder.ApplicationId]),
+			Description: reminder.Message,
+		})
+	}
+
+	return calendar, nil
+}
+
+// handleGetCalendarFeed renders the calendar of the owner of the token. It
+// is not authenticated, the token is the secret.
+func (s ApplicationService) handleGetCalendarFeed(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	token := strings.TrimSuffix(p.ByName("token"), calendarExtension)
+	if token == p.ByName("token") {
+		ApiResponse(w, "This calendar does not exist", http.StatusNotFound)
+		return
+	}
+
+	userId, err := s.CalendarController.GetCalendarTokenUser(token)
+	if err != nil {
+		if errors.Is(err, controller.ErrNotFound) {
+			ApiResponse(w, "This calendar does not exist", http.StatusNotFound)
+			return
+		}
+
+		ApiResponse(w, "Could not fetch calendar", http.StatusInternalServerError)
+		return
+	}
+
+	calendar, err := s.buildCalendar(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch calendar", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="applications.ics"`)
+	if err := ical.Write(w, calendar, time.Now()); err != nil {
+		log.Printf("Could not write calendar of user %d: %v", userId, err)
+	}
+}
+
+func (s ApplicationService) handleGetCalendarToken(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	token, err := s.CalendarController.GetCalendarToken(userId)
+	if err != nil {
+		if errors.Is(err, controller.ErrNotFound) {
+			ApiResponse(w, "The calendar feed is not enabled", http.StatusBadRequest)
+			return
+		}
+
+		ApiResponse(w, "Could not fetch calendar token", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched calendar token", map[string]interface{}{
+		"calendar": calendarResponse(token),
+	}))
+}
+
+// handleRotateCalendarToken enables the calendar feed of the user or
+// replaces its token, so the previous url stops working.
+func (s ApplicationService) handleRotateCalendarToken(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+
+	token, err := newCalendarToken()
+	if err == nil {
+		err = s.CalendarController.SaveCalendarToken(userId, token)
+	}
+
+	if err != nil {
+		ApiResponse(w, "Could not create calendar token", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Calendar token created", map[string]interface{}{
+		"calendar": calendarResponse(token),
+	}))
+}
+
+// handleDeleteCalendarToken disables the calendar feed of the user.
+func (s ApplicationService) handleDeleteCalendarToken(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if err := s.CalendarController.DeleteCalendarToken(userId); err != nil {
+		if errors.Is(err, controller.ErrNotFound) {
+			ApiResponse(w, "The calendar feed is not enabled", http.StatusBadRequest)
+			return
+		}
+
+		ApiResponse(w, "Could not delete calendar token", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Calendar token deleted", http.StatusOK)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"flhansen/application-manager/application-service/src/controller"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteCalendar(t *testing.T) {
+	s, store := newTestService()
+
+	resp, _ := serveTestRequest(t, s, http.MethodGet, "/api/calendar/token", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/calendar/token", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	calendar := res["calendar"].(map[string]interface{})
+	token := calendar["token"].(string)
+	assert.Equal(t, 64, len(token))
+	assert.Equal(t, "/api/calendar/"+token+".ics", calendar["url"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/calendar/token", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, token, res["calendar"].(map[string]interface{})["token"])
+
+	accepted, _ := store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer", CompanyName: "ACME",
+		SubmissionDate: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		StartDate:      time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+	})
+	pending, _ := store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Rust Developer",
+		SubmissionDate: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC),
+		StartDate:      time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+	})
+	store.InsertApplication(controller.Application{UserId: 2, WorkTypeId: 1, StatusId: 2, JobTitle: "Other", SubmissionDate: time.Now()})
+	store.InsertInterview(controller.Interview{
+		ApplicationId: pending, ScheduledAt: time.Date(2022, 6, 10, 14, 0, 0, 0, time.UTC), DurationMinutes: 30,
+		Type: controller.InterviewPhone, Outcome: controller.OutcomePending,
+	})
+	store.InsertReminder(controller.Reminder{ApplicationId: pending, RemindAt: time.Date(2022, 6, 20, 9, 0, 0, 0, time.UTC), Message: "Call back"})
+
+	feed := httptest.NewRecorder()
+	s.Router.ServeHTTP(feed, httptest.NewRequest(http.MethodGet, "/api/calendar/"+token+".ics", nil))
+	assert.Equal(t, http.StatusOK, feed.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", feed.Header().Get("Content-Type"))
+
+	body := feed.Body.String()
+	assert.Equal(t, 5, strings.Count(body, "BEGIN:VEVENT"))
+	assert.Contains(t, body, "UID:application-"+strconv.Itoa(accepted)+"-submission@application-manager\r\n")
+	assert.Contains(t, body, "SUMMARY:Applied: Go Developer at ACME\r\n")
+	assert.Contains(t, body, "UID:application-"+strconv.Itoa(accepted)+"-start@application-manager\r\nDTSTAMP:")
+	assert.Contains(t, body, "DTSTART;VALUE=DATE:20220801\r\n")
+	assert.NotContains(t, body, "application-"+strconv.Itoa(pending)+"-start@")
+	assert.Contains(t, body, "DTSTART:20220610T140000Z\r\nDTEND:20220610T143000Z\r\n")
+	assert.Contains(t, body, "DESCRIPTION:Call back\r\n")
+	assert.NotContains(t, body, "Other")
+
+	// Rotating the token disables the previous url.
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/calendar/token", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	feed = httptest.NewRecorder()
+	s.Router.ServeHTTP(feed, httptest.NewRequest(http.MethodGet, "/api/calendar/"+token+".ics", nil))
+	assert.Equal(t, http.StatusNotFound, feed.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, "/api/calendar/token", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, "/api/calendar/token", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteCalendarShowsStartDatesOfAcceptedStatuses(t *testing.T) {
+	s, store := newTestService()
+
+	// The start date is shown for the flag, not for the name of a status.
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, strings.NewReader(`{"name": "Hired", "terminal": true, "accepted": true}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	hiredId := int(res["status"].(map[string]interface{})["id"].(float64))
+	assert.Equal(t, true, res["status"].(map[string]interface{})["accepted"])
+
+	resp, res = serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, strings.NewReader(`{"name": "Accepted"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	acceptedId := int(res["status"].(map[string]interface{})["id"].(float64))
+
+	hired, _ := store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: hiredId, JobTitle: "Go Developer",
+		StartDate: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+	})
+	named, _ := store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: acceptedId, JobTitle: "Rust Developer",
+		StartDate: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	calendar, err := s.buildCalendar(1)
+	assert.Nil(t, err)
+
+	var uids []string
+	for _, event := range calendar.Events {
+		uids = append(uids, event.Uid)
+	}
+
+	assert.Contains(t, uids, "application-"+strconv.Itoa(hired)+"-start@application-manager")
+	assert.NotContains(t, uids, "application-"+strconv.Itoa(named)+"-start@application-manager")
+}
+
+func TestRouteCalendarShowsStartDatesOfGlobalAcceptedStatus(t *testing.T) {
+	s, store := newTestService()
+
+	// Applications may stay in a global status after the user defined own
+	// statuses.
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, strings.NewReader(`{"name": "Applied"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	accepted, _ := store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer",
+		StartDate: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	calendar, err := s.buildCalendar(1)
+	assert.Nil(t, err)
+
+	var uids []string
+	for _, event := range calendar.Events {
+		uids = append(uids, event.Uid)
+	}
+
+	assert.Contains(t, uids, "application-"+strconv.Itoa(accepted)+"-start@application-manager")
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func validateCompany(company controller.Company) error {
+	if controller.NormalizeCompanyName(company.Name) == "" || len(company.Name) > 255 {
+		return fmt.Errorf("The name of a company must contain 1 to 255 characters")
+	}
+
+	if len(company.Website) > 500 || len(company.Industry) > 255 || len(company.Size) > 64 || len(company.Location) > 255 {
+		return fmt.Errorf("The company details are too long")
+	}
+
+	return nil
+}
+
+// resolveCompany makes sure, that the company referenced by the application
+// belongs to its owner and takes over the name of the company. Applications
+// without a company are assigned by their company name in the repository.
+func (s ApplicationService) resolveCompany(w http.ResponseWriter, application *controller.Application) bool {
+	if application.CompanyId == nil {
+		return true
+	}
+
+	company, err := s.CompanyController.GetCompany(*application.CompanyId)
+	if err != nil {
+		ApiResponse(w, "This company does not exist", http.StatusBadRequest)
+		return false
+	}
+
+	if company.UserId != application.UserId {
+		ApiResponse(w, "You cannot use a company of another user", http.StatusBadRequest)
+		return false
+	}
+
+	application.CompanyName = company.Name
+	return true
+}
+
+// ownedCompany fetches the company referenced by the id parameter and makes
+// sure, it belongs to the requesting user. Otherwise an error response is
+// written and false is returned.
+func (s ApplicationService) ownedCompany(w http.ResponseWriter, p httprouter.Params) (controller.Company, bool) {
+	companyId, err := strconv.Atoi(p.ByName("id"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the company id", http.StatusBadRequest)
+		return controller.Company{}, false
+	}
+
+	company, err := s.CompanyController.GetCompany(companyId)
+	if err != nil {
+		ApiResponse(w, "This company does not exist", http.StatusBadRequest)
+		return controller.Company{}, false
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if company.UserId != userId {
+		ApiResponse(w, "You are not allowed to access this company", http.StatusUnauthorized)
+		return controller.Company{}, false
+	}
+
+	return company, true
+}
+
+func (s ApplicationService) handleGetCompanies(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	companies, err := s.CompanyController.GetCompanies(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch companies", http.StatusInternalServerError)
+		return
+	}
+
+	if companies == nil {
+		companies = []controller.Company{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched companies", map[string]interface{}{
+		"companies": companies,
+	}))
+}
+
+func (s ApplicationService) handleCreateCompany(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	var company controller.Company
+	if err := json.NewDecoder(r.Body).Decode(&company); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateCompany(company); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	company.UserId, _ = strconv.Atoi(p.ByName("userId"))
+
+	id, err := s.CompanyController.InsertCompany(company)
+	if err != nil {
+		if errors.Is(err, controller.ErrAlreadyExists) {
+			ApiResponse(w, "A company with this name already exists", http.StatusConflict)
+			return
+		}
+
+		ApiResponse(w, "Could not create company", http.StatusInternalServerError)
+		return
+	}
+
+	newCompany, _ := s.CompanyController.GetCompany(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Company created", map[string]interface{}{
+		"company": newCompany,
+	}))
+}
+
+func (s ApplicationService) handleGetCompany(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	company, ok := s.ownedCompany(w, p)
+	if !ok {
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched company", map[string]interface{}{
+		"company": company,
+	}))
+}
+
+func (s ApplicationService) handleUpdateCompany(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	company, ok := s.ownedCompany(w, p)
+	if !ok {
+		return
+	}
+
+	var companyRequest controller.Company
+	if err := json.NewDecoder(r.Body).Decode(&companyRequest); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateCompany(companyRequest); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	companyRequest.Id = company.Id
+	companyRequest.UserId = company.UserId
+
+	if err := s.CompanyController.UpdateCompany(companyRequest); err != nil {
+		if errors.Is(err, controller.ErrAlreadyExists) {
+			ApiResponse(w, "A company with this name already exists", http.StatusConflict)
+			return
+		}
+
+		ApiResponse(w, "Could not update company", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Company updated", http.StatusOK)
+}
+
+func (s ApplicationService) handleDeleteCompany(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	company, ok := s.ownedCompany(w, p)
+	if !ok {
+		return
+	}
+
+	if err := s.CompanyController.DeleteCompany(company.Id); err != nil {
+		ApiResponse(w, "Could not delete company", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Company deleted", http.StatusOK)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteCompanies(t *testing.T) {
+	s, _ := newTestService()
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/companies", 1, bytes.NewBufferString(`{"name": "Acme Inc.", "industry": "Anvils"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	companyId := res["company"].(map[string]interface{})["id"]
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/companies", 1, bytes.NewBufferString(`{"name": "ACME"}`))
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, fmt.Sprintf("/api/companies/%v", companyId), 1, bytes.NewBufferString(`{"name": "Acme Corporation"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/companies", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["companies"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/companies/%v", companyId), 2, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/companies/%v", companyId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestRouteCreateApplicationWithCompanyName(t *testing.T) {
+	s, store := newTestService()
+
+	requestBuffer := new(bytes.Buffer)
+	json.NewEncoder(requestBuffer).Encode(controller.Application{WorkTypeId: 1, StatusId: 2, CompanyName: "Acme Inc."})
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/applications", 1, requestBuffer)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	companyId := res["application"].(map[string]interface{})["companyId"]
+	assert.NotNil(t, companyId)
+
+	companies, _ := store.GetCompanies(1)
+	assert.Equal(t, 1, len(companies))
+}
+
+func TestRouteCreateApplicationWithCompanyId(t *testing.T) {
+	s, store := newTestService()
+	ownCompanyId, _ := store.InsertCompany(controller.Company{UserId: 1, Name: "ACME"})
+	otherCompanyId, _ := store.InsertCompany(controller.Company{UserId: 2, Name: "Initech"})
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"WorkTypeId": 1, "StatusId": 2, "companyId": %d}`, ownCompanyId)))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "ACME", res["application"].(map[string]interface{})["CompanyName"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"WorkTypeId": 1, "StatusId": 2, "companyId": %d}`, otherCompanyId)))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
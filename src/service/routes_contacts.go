@@ -0,0 +1,235 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func validateContact(contact controller.Contact) error {
+	if strings.TrimSpace(contact.Name) == "" || len(contact.Name) > 255 {
+		return fmt.Errorf("The name of a contact must contain 1 to 255 characters")
+	}
+
+	if len(contact.Email) > 255 || len(contact.Phone) > 64 || len(contact.Role) > 255 || len(contact.LinkedInUrl) > 500 {
+		return fmt.Errorf("The contact details are too long")
+	}
+
+	return nil
+}
+
+// ownedContact fetches the contact referenced by the given parameter and
+// makes sure, it belongs to the requesting user. Otherwise an error response
+// is written and false is returned.
+func (s ApplicationService) ownedContact(w http.ResponseWriter, p httprouter.Params, param string) (controller.Contact, bool) {
+	contactId, err := strconv.Atoi(p.ByName(param))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the contact id", http.StatusBadRequest)
+		return controller.Contact{}, false
+	}
+
+	contact, err := s.ContactController.GetContact(contactId)
+	if err != nil {
+		ApiResponse(w, "This contact does not exist", http.StatusBadRequest)
+		return controller.Contact{}, false
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if contact.UserId != userId {
+		ApiResponse(w, "You are not allowed to access this contact", http.StatusUnauthorized)
+		return controller.Contact{}, false
+	}
+
+	return contact, true
+}
+
+func (s ApplicationService) handleGetContacts(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	contacts, err := s.ContactController.GetContacts(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch contacts", http.StatusInternalServerError)
+		return
+	}
+
+	if contacts == nil {
+		contacts = []controller.Contact{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched contacts", map[string]interface{}{
+		"contacts": contacts,
+	}))
+}
+
+func (s ApplicationService) handleCreateContact(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	var contact controller.Contact
+	if err := json.NewDecoder(r.Body).Decode(&contact); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateContact(contact); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contact.UserId, _ = strconv.Atoi(p.ByName("userId"))
+
+	id, err := s.ContactController.InsertContact(contact)
+	if err != nil {
+		ApiResponse(w, "Could not create contact", http.StatusInternalServerError)
+		return
+	}
+
+	newContact, _ := s.ContactController.GetContact(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Contact created", map[string]interface{}{
+		"contact": newContact,
+	}))
+}
+
+func (s ApplicationService) handleGetContact(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	contact, ok := s.ownedContact(w, p, "id")
+	if !ok {
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched contact", map[string]interface{}{
+		"contact": contact,
+	}))
+}
+
+func (s ApplicationService) handleUpdateContact(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	contact, ok := s.ownedContact(w, p, "id")
+	if !ok {
+		return
+	}
+
+	var contactRequest controller.Contact
+	if err := json.NewDecoder(r.Body).Decode(&contactRequest); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateContact(contactRequest); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contactRequest.Id = contact.Id
+	contactRequest.UserId = contact.UserId
+
+	if err := s.ContactController.UpdateContact(contactRequest); err != nil {
+		ApiResponse(w, "Could not update contact", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Contact updated", http.StatusOK)
+}
+
+func (s ApplicationService) handleDeleteContact(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	contact, ok := s.ownedContact(w, p, "id")
+	if !ok {
+		return
+	}
+
+	if err := s.ContactController.DeleteContact(contact.Id); err != nil {
+		ApiResponse(w, "Could not delete contact", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Contact deleted", http.StatusOK)
+}
+
+func (s ApplicationService) handleGetContactApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	contact, ok := s.ownedContact(w, p, "id")
+	if !ok {
+		return
+	}
+
+	applications, err := s.ContactController.GetContactApplications(contact.Id)
+	if err == nil {
+		err = s.withDetails(applications)
+	}
+
+	if err != nil {
+		ApiResponse(w, "Could not fetch the applications of the contact", http.StatusInternalServerError)
+		return
+	}
+
+	if applications == nil {
+		applications = []controller.Application{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched applications", map[string]interface{}{
+		"applications": applications,
+	}))
+}
+
+func (s ApplicationService) handleGetApplicationContacts(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	contacts, err := s.ContactController.GetApplicationContacts(application.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch contacts", http.StatusInternalServerError)
+		return
+	}
+
+	if contacts == nil {
+		contacts = []controller.Contact{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched contacts", map[string]interface{}{
+		"contacts": contacts,
+	}))
+}
+
+func (s ApplicationService) handleLinkContact(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	contact, ok := s.ownedContact(w, p, "contactId")
+	if !ok {
+		return
+	}
+
+	if err := s.ContactController.LinkContact(application.Id, contact.Id); err != nil {
+		ApiResponse(w, "Could not link contact", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Contact linked", http.StatusOK)
+}
+
+func (s ApplicationService) handleUnlinkContact(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	contact, ok := s.ownedContact(w, p, "contactId")
+	if !ok {
+		return
+	}
+
+	if err := s.ContactController.UnlinkContact(application.Id, contact.Id); err != nil {
+		if errors.Is(err, controller.ErrNotFound) {
+			ApiResponse(w, "The contact is not linked to this application", http.StatusBadRequest)
+			return
+		}
+
+		ApiResponse(w, "Could not unlink contact", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Contact unlinked", http.StatusOK)
+}
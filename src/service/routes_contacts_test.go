@@ -0,0 +1,57 @@
+package service
+
+import (
+	"bytes"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteContacts(t *testing.T) {
+	s, store := newTestService()
+	firstId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	secondId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/contacts", 1, bytes.NewBufferString(`{"name": "Rita Recruiter", "role": "Recruiter"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	contactId := res["contact"].(map[string]interface{})["id"]
+
+	for _, applicationId := range []int{firstId, secondId} {
+		resp, _ = serveTestRequest(t, s, http.MethodPut, fmt.Sprintf("/api/applications/%d/contacts/%v", applicationId, contactId), 1, nil)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	}
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/contacts/%v/applications", contactId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 2, len(res["applications"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/applications/%d/contacts/%v", secondId, contactId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/contacts", firstId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "Recruiter", res["contacts"].([]interface{})[0].(map[string]interface{})["role"])
+}
+
+func TestRouteCreateContactWithoutName(t *testing.T) {
+	s, _ := newTestService()
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/contacts", 1, bytes.NewBufferString(`{"email": "rita@example.com"}`))
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteLinkContactOfAnotherUser(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	contactId, _ := store.InsertContact(controller.Contact{UserId: 2, Name: "Rita Recruiter"})
+
+	resp, _ := serveTestRequest(t, s, http.MethodPut, fmt.Sprintf("/api/applications/%d/contacts/%d", applicationId, contactId), 1, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/contacts/%d", contactId), 1, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
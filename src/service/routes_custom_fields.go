@@ -0,0 +1,271 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// customFieldsByKey returns the custom field definitions of a user by key.
+func (s ApplicationService) customFieldsByKey(userId int) (map[string]controller.CustomField, error) {
+	fields, err := s.CustomFieldController.GetCustomFields(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := map[string]controller.CustomField{}
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	return byKey, nil
+}
+
+// parseCustomFields validates the custom field values of the application
+// against the definitions of its owner and normalizes them. Null values are
+// dropped. Otherwise an error response is written and false is returned.
+func (s ApplicationService) parseCustomFields(w http.ResponseWriter, application *controller.Application) bool {
+	if application.CustomFields == nil {
+		return true
+	}
+
+	fields, err := s.customFieldsByKey(application.UserId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch custom fields", http.StatusInternalServerError)
+		return false
+	}
+
+	values := map[string]interface{}{}
+	for key, value := range application.CustomFields {
+		field, ok := fields[key]
+		if !ok {
+			ApiResponse(w, fmt.Sprintf("The custom field %s does not exist", key), http.StatusBadRequest)
+			return false
+		}
+
+		if value == nil {
+			continue
+		}
+
+		if values[key], err = field.ParseValue(value); err != nil {
+			ApiResponse(w, err.Error(), http.StatusBadRequest)
+			return false
+		}
+	}
+
+	application.CustomFields = values
+	return true
+}
+
+// parseCustomFieldQuery adds the custom field filters given as query
+// parameters like customFields.visaSponsorship=true to the query and makes
+// sure, that the custom fields used for sorting exist.
+func (s ApplicationService) parseCustomFieldQuery(userId int, values url.Values, query *controller.ApplicationQuery) error {
+	var keys []string
+	for name := range values {
+		if strings.HasPrefix(name, controller.CustomFieldPrefix) {
+			keys = append(keys, name)
+		}
+	}
+
+	for _, field := range query.Sort {
+		if strings.HasPrefix(field.Field, controller.CustomFieldPrefix) {
+			keys = append(keys, field.Field)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	fields, err := s.customFieldsByKey(userId)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range keys {
+		key := strings.TrimPrefix(name, controller.CustomFieldPrefix)
+		if _, ok := fields[key]; !ok {
+			return fmt.Errorf("%w: unknown custom field %s", controller.ErrInvalidQuery, key)
+		}
+	}
+
+	for name, texts := range values {
+		key := strings.TrimPrefix(name, controller.CustomFieldPrefix)
+		if key == name {
+			continue
+		}
+
+		for _, text := range texts {
+			value, err := fields[key].ParseFilterValue(text)
+			if err != nil {
+				return fmt.Errorf("%w: %v", controller.ErrInvalidQuery, err)
+			}
+
+			query.CustomFields = append(query.CustomFields, controller.CustomFieldFilter{Key: key, Value: value})
+		}
+	}
+
+	return nil
+}
+
+// withCustomFields fills in the custom field values of the applications.
+func (s ApplicationService) withCustomFields(applications []controller.Application) error {
+	if len(applications) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(applications))
+	for i, application := range applications {
+		ids[i] = application.Id
+	}
+
+	values, err := s.CustomFieldController.GetCustomFieldValues(ids)
+	if err != nil {
+		return err
+	}
+
+	for i, application := range applications {
+		applications[i].CustomFields = values[application.Id]
+		if applications[i].CustomFields == nil {
+			applications[i].CustomFields = map[string]interface{}{}
+		}
+	}
+
+	return nil
+}
+
+// ownedCustomField fetches the custom field referenced by the id parameter
+// and makes sure, it belongs to the requesting user. Otherwise an error
+// response is written and false is returned.
+func (s ApplicationService) ownedCustomField(w http.ResponseWriter, p httprouter.Params) (controller.CustomField, bool) {
+	fieldId, err := strconv.Atoi(p.ByName("id"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the custom field id", http.StatusBadRequest)
+		return controller.CustomField{}, false
+	}
+
+	field, err := s.CustomFieldController.GetCustomField(fieldId)
+	if err != nil {
+		ApiResponse(w, "This custom field does not exist", http.StatusBadRequest)
+		return controller.CustomField{}, false
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if field.UserId != userId {
+		ApiResponse(w, "You are not allowed to access this custom field", http.StatusUnauthorized)
+		return controller.CustomField{}, false
+	}
+
+	return field, true
+}
+
+func (s ApplicationService) handleGetCustomFields(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	fields, err := s.CustomFieldController.GetCustomFields(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch custom fields", http.StatusInternalServerError)
+		return
+	}
+
+	if fields == nil {
+		fields = []controller.CustomField{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched custom fields", map[string]interface{}{
+		"customFields": fields,
+	}))
+}
+
+func (s ApplicationService) handleCreateCustomField(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	var field controller.CustomField
+	if err := json.NewDecoder(r.Body).Decode(&field); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := field.Validate(); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	field.UserId, _ = strconv.Atoi(p.ByName("userId"))
+
+	id, err := s.CustomFieldController.InsertCustomField(field)
+	if err != nil {
+		if errors.Is(err, controller.ErrAlreadyExists) {
+			ApiResponse(w, "A custom field with this key already exists", http.StatusConflict)
+			return
+		}
+
+		ApiResponse(w, "Could not create custom field", http.StatusInternalServerError)
+		return
+	}
+
+	newField, _ := s.CustomFieldController.GetCustomField(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Custom field created", map[string]interface{}{
+		"customField": newField,
+	}))
+}
+
+func (s ApplicationService) handleGetCustomField(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	field, ok := s.ownedCustomField(w, p)
+	if !ok {
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched custom field", map[string]interface{}{
+		"customField": field,
+	}))
+}
+
+// handleUpdateCustomField changes the label and the options of a custom
+// field. The key and the type are kept.
+func (s ApplicationService) handleUpdateCustomField(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	field, ok := s.ownedCustomField(w, p)
+	if !ok {
+		return
+	}
+
+	var fieldRequest controller.CustomField
+	if err := json.NewDecoder(r.Body).Decode(&fieldRequest); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	field.Label = fieldRequest.Label
+	field.Options = fieldRequest.Options
+
+	if err := field.Validate(); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.CustomFieldController.UpdateCustomField(field); err != nil {
+		ApiResponse(w, "Could not update custom field", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Custom field updated", http.StatusOK)
+}
+
+func (s ApplicationService) handleDeleteCustomField(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	field, ok := s.ownedCustomField(w, p)
+	if !ok {
+		return
+	}
+
+	if err := s.CustomFieldController.DeleteCustomField(field.Id); err != nil {
+		ApiResponse(w, "Could not delete custom field", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Custom field deleted", http.StatusOK)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteCustomFields(t *testing.T) {
+	s, _ := newTestService()
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/customfields", 1,
+		bytes.NewBufferString(`{"key": "stack", "label": "Tech stack", "type": "enum", "options": ["go", "java"]}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	fieldId := res["customField"].(map[string]interface{})["id"]
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/customfields", 1, bytes.NewBufferString(`{"key": "stack", "type": "text"}`))
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/customfields", 1, bytes.NewBufferString(`{"key": "team size", "type": "number"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, fmt.Sprintf("/api/customfields/%v", fieldId), 1,
+		bytes.NewBufferString(`{"label": "Stack", "options": ["go", "java", "rust"]}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/customfields/%v", fieldId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "enum", res["customField"].(map[string]interface{})["type"])
+	assert.Equal(t, 3, len(res["customField"].(map[string]interface{})["options"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/customfields/%v", fieldId), 2, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/customfields", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["customFields"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/customfields/%v", fieldId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestRouteApplicationCustomFields(t *testing.T) {
+	s, store := newTestService()
+	store.InsertCustomField(controller.CustomField{UserId: 1, Key: "teamSize", Type: controller.FieldNumber})
+	store.InsertCustomField(controller.CustomField{UserId: 1, Key: "deadline", Type: controller.FieldDate})
+	store.InsertCustomField(controller.CustomField{UserId: 2, Key: "visa", Type: controller.FieldBoolean})
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+		bytes.NewBufferString(`{"WorkTypeId": 1, "StatusId": 2, "customFields": {"visa": true}}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+		bytes.NewBufferString(`{"WorkTypeId": 1, "StatusId": 2, "customFields": {"teamSize": "large"}}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+		bytes.NewBufferString(`{"WorkTypeId": 1, "StatusId": 2, "customFields": {"teamSize": 12, "deadline": "2022-07-01"}}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	application := res["application"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"teamSize": float64(12), "deadline": "2022-07-01"}, application["customFields"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+		bytes.NewBufferString(`{"WorkTypeId": 1, "StatusId": 2, "customFields": {"teamSize": 4}}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, "/api/applications", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"Id": %v, "WorkTypeId": 1, "StatusId": 2, "customFields": {"teamSize": 20, "deadline": null}}`, application["Id"])))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%v", application["Id"]), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, map[string]interface{}{"teamSize": float64(20)}, res["application"].(map[string]interface{})["customFields"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/applications?customFields.teamSize=20", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(1), res["total"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/applications?sort=customFields.teamSize", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	applications := res["applications"].([]interface{})
+	assert.Equal(t, float64(2), applications[0].(map[string]interface{})["Id"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications?customFields.teamSize=many", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications?sort=customFields.visa", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
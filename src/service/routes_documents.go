@@ -0,0 +1,328 @@
+package service
+
+import (
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// withDocumentVersions fills in the document versions referenced by the
+// applications.
+func (s ApplicationService) withDocumentVersions(applications []controller.Application) error {
+	var ids []int
+	for _, application := range applications {
+		if application.DocumentVersionId != nil {
+			ids = append(ids, *application.DocumentVersionId)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	versions, err := s.DocumentController.GetDocumentVersionsByIds(ids)
+	if err != nil {
+		return err
+	}
+
+	for i, application := range applications {
+		if application.DocumentVersionId == nil {
+			continue
+		}
+
+		if version, ok := versions[*application.DocumentVersionId]; ok {
+			applications[i].DocumentVersion = &version
+		}
+	}
+
+	return nil
+}
+
+// isDocumentVersionUsable checks, that the referenced document version
+// belongs to a document of the user.
+func (s ApplicationService) isDocumentVersionUsable(application controller.Application) bool {
+	if application.DocumentVersionId == nil {
+		return true
+	}
+
+	version, err := s.DocumentController.GetDocumentVersion(*application.DocumentVersionId)
+	if err != nil {
+		return false
+	}
+
+	document, err := s.DocumentController.GetDocument(version.DocumentId)
+	return err == nil && document.UserId == application.UserId
+}
+
+// ownedDocument fetches the document referenced by the id parameter and
+// makes sure, it belongs to the requesting user. Otherwise an error response
+// is written and false is returned.
+func (s ApplicationService) ownedDocument(w http.ResponseWriter, p httprouter.Params) (controller.Document, bool) {
+	documentId, err := strconv.Atoi(p.ByName("id"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the document id", http.StatusBadRequest)
+		return controller.Document{}, false
+	}
+
+	document, err := s.DocumentController.GetDocument(documentId)
+	if err != nil {
+		ApiResponse(w, "This document does not exist", http.StatusBadRequest)
+		return controller.Document{}, false
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if document.UserId != userId {
+		ApiResponse(w, "You are not allowed to access this document", http.StatusUnauthorized)
+		return controller.Document{}, false
+	}
+
+	return document, true
+}
+
+// ownedDocumentVersion fetches the version referenced by the version number
+// in the version parameter of an owned document.
+func (s ApplicationService) ownedDocumentVersion(w http.ResponseWriter, p httprouter.Params) (controller.DocumentVersion, bool) {
+	document, ok := s.ownedDocument(w, p)
+	if !ok {
+		return controller.DocumentVersion{}, false
+	}
+
+	number, err := strconv.Atoi(p.ByName("version"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the version number", http.StatusBadRequest)
+		return controller.DocumentVersion{}, false
+	}
+
+	versions, err := s.DocumentController.GetDocumentVersions(document.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch the versions of the document", http.StatusInternalServerError)
+		return controller.DocumentVersion{}, false
+	}
+
+	for _, version := range versions {
+		if version.Version == number {
+			return version, true
+		}
+	}
+
+	ApiResponse(w, "This version does not exist", http.StatusBadRequest)
+	return controller.DocumentVersion{}, false
+}
+
+func (s ApplicationService) handleGetDocuments(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	documents, err := s.DocumentController.GetDocuments(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch documents", http.StatusInternalServerError)
+		return
+	}
+
+	if documents == nil {
+		documents = []controller.Document{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched documents", map[string]interface{}{
+		"documents": documents,
+	}))
+}
+
+// handleCreateDocument creates a document from an upload, which contains the
+// first version of the file and the name and kind of the document.
+func (s ApplicationService) handleCreateDocument(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	file, ok := s.readUpload(w, r, userId)
+	if !ok {
+		return
+	}
+
+	document := controller.Document{
+		UserId: userId,
+		Name:   strings.TrimSpace(file.values.Get("name")),
+		Kind:   file.values.Get("kind"),
+	}
+
+	if document.Name == "" {
+		document.Name = file.fileName
+	}
+
+	if document.Kind == "" {
+		document.Kind = controller.DocumentOther
+	}
+
+	if len(document.Name) > 255 {
+		ApiResponse(w, "The name of a document must contain 1 to 255 characters", http.StatusBadRequest)
+		return
+	}
+
+	if document.Kind != controller.DocumentCV && document.Kind != controller.DocumentCoverLetter && document.Kind != controller.DocumentOther {
+		ApiResponse(w, "The kind of a document must be cv, cover_letter or other", http.StatusBadRequest)
+		return
+	}
+
+	version := controller.DocumentVersion{
+		FileName:    file.fileName,
+		ContentType: file.contentType,
+		Size:        int64(len(file.content)),
+	}
+
+	var err error
+	if version.BlobKey, err = newBlobKey("documents", userId); err != nil {
+		ApiResponse(w, "Could not upload document", http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.storeUpload(r, version.BlobKey, file); err != nil {
+		ApiResponse(w, "Could not store document", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := s.DocumentController.InsertDocument(document, version)
+	if err != nil {
+		s.deleteBlobs(r.Context(), version.BlobKey)
+		ApiResponse(w, "Could not create document", http.StatusInternalServerError)
+		return
+	}
+
+	newDocument, _ := s.DocumentController.GetDocument(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Document created", map[string]interface{}{
+		"document": newDocument,
+	}))
+}
+
+func (s ApplicationService) handleGetDocument(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	document, ok := s.ownedDocument(w, p)
+	if !ok {
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched document", map[string]interface{}{
+		"document": document,
+	}))
+}
+
+func (s ApplicationService) handleDeleteDocument(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	document, ok := s.ownedDocument(w, p)
+	if !ok {
+		return
+	}
+
+	versions, err := s.DocumentController.GetDocumentVersions(document.Id)
+	if err != nil {
+		ApiResponse(w, "Could not delete document", http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.DocumentController.DeleteDocument(document.Id); err != nil {
+		if errors.Is(err, controller.ErrInUse) {
+			ApiResponse(w, "A version of this document was sent with an application", http.StatusConflict)
+			return
+		}
+
+		ApiResponse(w, "Could not delete document", http.StatusInternalServerError)
+		return
+	}
+
+	for _, version := range versions {
+		s.deleteBlobs(r.Context(), version.BlobKey)
+	}
+
+	ApiResponse(w, "Document deleted", http.StatusOK)
+}
+
+func (s ApplicationService) handleGetDocumentVersions(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	document, ok := s.ownedDocument(w, p)
+	if !ok {
+		return
+	}
+
+	versions, err := s.DocumentController.GetDocumentVersions(document.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch the versions of the document", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched document versions", map[string]interface{}{
+		"versions": versions,
+	}))
+}
+
+func (s ApplicationService) handleCreateDocumentVersion(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	document, ok := s.ownedDocument(w, p)
+	if !ok {
+		return
+	}
+
+	file, ok := s.readUpload(w, r, document.UserId)
+	if !ok {
+		return
+	}
+
+	version := controller.DocumentVersion{
+		DocumentId:  document.Id,
+		FileName:    file.fileName,
+		ContentType: file.contentType,
+		Size:        int64(len(file.content)),
+	}
+
+	var err error
+	if version.BlobKey, err = newBlobKey("documents", document.UserId); err != nil {
+		ApiResponse(w, "Could not upload document", http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.storeUpload(r, version.BlobKey, file); err != nil {
+		ApiResponse(w, "Could not store document", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := s.DocumentController.InsertDocumentVersion(version)
+	if err != nil {
+		s.deleteBlobs(r.Context(), version.BlobKey)
+		ApiResponse(w, "Could not create document version", http.StatusInternalServerError)
+		return
+	}
+
+	newVersion, _ := s.DocumentController.GetDocumentVersion(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Document version created", map[string]interface{}{
+		"version": newVersion,
+	}))
+}
+
+func (s ApplicationService) handleDownloadDocumentVersion(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	version, ok := s.ownedDocumentVersion(w, p)
+	if !ok {
+		return
+	}
+
+	s.serveBlob(w, r, version.BlobKey, version.FileName, version.ContentType, version.Size)
+}
+
+func (s ApplicationService) handleGetVersionApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	version, ok := s.ownedDocumentVersion(w, p)
+	if !ok {
+		return
+	}
+
+	applications, err := s.DocumentController.GetVersionApplications(version.Id)
+	if err == nil {
+		err = s.withDetails(applications)
+	}
+
+	if err != nil {
+		ApiResponse(w, "Could not fetch the applications of the version", http.StatusInternalServerError)
+		return
+	}
+
+	if applications == nil {
+		applications = []controller.Application{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched applications", map[string]interface{}{
+		"applications": applications,
+	}))
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bytes"
+	"flhansen/application-manager/application-service/src/auth"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestDocument uploads the first version of a CV and returns the id of
+// the document.
+func createTestDocument(t *testing.T, s ApplicationService, userId int) interface{} {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("name", "My CV")
+	writer.WriteField("kind", "cv")
+	part, _ := writer.CreateFormFile("file", "cv-v1.pdf")
+	part.Write([]byte(testPdf))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	token, _ := auth.GenerateToken(userId, "testuser", jwt.SigningMethodHS256, s.Config.Jwt.SignKey)
+	req.Header.Add("Authorization", token)
+
+	recorder := httptest.NewRecorder()
+	s.Router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatal(recorder.Body.String())
+	}
+
+	documents, _ := s.DocumentController.GetDocuments(userId)
+	return documents[len(documents)-1].Id
+}
+
+func TestRouteDocumentVersions(t *testing.T) {
+	s, store := newTestService()
+	documentId := createTestDocument(t, s, 1)
+	path := fmt.Sprintf("/api/documents/%v/versions", documentId)
+
+	resp, res := serveTestUpload(t, s, path, 1, "cv-v2.pdf", testPdf+" v2")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	version := res["version"].(map[string]interface{})
+	assert.Equal(t, 2.0, version["version"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/documents/%v", documentId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 2.0, res["document"].(map[string]interface{})["latestVersion"])
+	assert.Equal(t, "cv", res["document"].(map[string]interface{})["kind"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, path+"/1", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, testPdf, resp.Body.String())
+
+	// Send the second version with an application.
+	resp, res = serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"WorkTypeId": 1, "StatusId": 2, "documentVersionId": %v}`, version["id"])))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 2.0, res["application"].(map[string]interface{})["documentVersion"].(map[string]interface{})["version"])
+
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, path+"/2/applications", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["applications"].([]interface{})))
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, path+"/1/applications", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, res["applications"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/documents/%v", documentId), 1, nil)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+func TestRouteDocumentNotOwner(t *testing.T) {
+	s, _ := newTestService()
+	documentId := createTestDocument(t, s, 1)
+
+	resp, _ := serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/documents/%v/versions/1", documentId), 2, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	versions, _ := s.DocumentController.GetDocumentVersions(documentId.(int))
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/applications", 2,
+		bytes.NewBufferString(fmt.Sprintf(`{"WorkTypeId": 1, "StatusId": 2, "documentVersionId": %d}`, versions[0].Id)))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteDeleteDocument(t *testing.T) {
+	s, _ := newTestService()
+	documentId := createTestDocument(t, s, 1)
+
+	resp, _ := serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/documents/%v", documentId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res := serveTestRequest(t, s, http.MethodGet, "/api/documents", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, res["documents"])
+}
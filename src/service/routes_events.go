@@ -0,0 +1,236 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// EventConfig configures the event stream. Zero values select the defaults.
+type EventConfig struct {
+	// Retention is the time events are kept for streams, which resume after
+	// a disconnect, and for the delivery log of the webhooks.
+	Retention time.Duration
+
+	// Heartbeat is the time between two comments sent to idle streams. The
+	// streams look for missed events on every heartbeat as well.
+	Heartbeat time.Duration
+}
+
+const (
+	defaultEventRetention = 7 * 24 * time.Hour
+	defaultEventHeartbeat = 15 * time.Second
+
+	// eventBatchSize limits the number of events read at once by a stream.
+	eventBatchSize = 100
+
+	// listenRetryInterval is the time between two attempts to listen for
+	// events, after the connection to the database failed.
+	listenRetryInterval = 5 * time.Second
+)
+
+func (c EventConfig) retention() time.Duration {
+	if c.Retention <= 0 {
+		return defaultEventRetention
+	}
+
+	return c.Retention
+}
+
+func (c EventConfig) heartbeat() time.Duration {
+	if c.Heartbeat <= 0 {
+		return defaultEventHeartbeat
+	}
+
+	return c.Heartbeat
+}
+
+// eventBroker wakes up the streams of a user, when events of the user were
+// written. Wakeups are coalesced, the streams read the events themselves.
+type eventBroker struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan struct{}]bool
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{subscribers: map[int]map[chan struct{}]bool{}}
+}
+
+func (b *eventBroker) subscribe(userId int) chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wakeup := make(chan struct{}, 1)
+	if b.subscribers[userId] == nil {
+		b.subscribers[userId] = map[chan struct{}]bool{}
+	}
+
+	b.subscribers[userId][wakeup] = true
+	return wakeup
+}
+
+func (b *eventBroker) unsubscribe(userId int, wakeup chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers[userId], wakeup)
+	if len(b.subscribers[userId]) == 0 {
+		delete(b.subscribers, userId)
+	}
+}
+
+// publish wakes up the streams of the user without blocking.
+func (b *eventBroker) publish(userId int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for wakeup := range b.subscribers[userId] {
+		select {
+		case wakeup <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ListenEvents wakes up the streams of the users, whose events were written
+// by any replica, until the context is cancelled. Listening is retried, if
+// the connection to the database fails.
+func (s ApplicationService) ListenEvents(ctx context.Context) {
+	for {
+		err := s.EventController.ListenEvents(ctx, s.events.publish)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("Could not listen for events: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+// lastEventId returns the id of the last event received by the client, which
+// is the position of the event in the outbox. The EventSource API sends it in
+// the Last-Event-ID header on reconnects, the query parameter allows to
+// resume a new connection.
+func lastEventId(r *http.Request) (int64, bool, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+
+	if value == "" {
+		return 0, false, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false, fmt.Errorf("the last event id must be a positive number")
+	}
+
+	return id, true, nil
+}
+
+// writeEvent writes the event in the server-sent events format with its
+// position as the id. The payload is compacted, because a data line must not
+// contain line breaks.
+func writeEvent(w http.ResponseWriter, event controller.OutboxEvent) error {
+	var data bytes.Buffer
+	if err := json.Compact(&data, event.Payload); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Position, event.Event, data.Bytes())
+	return err
+}
+
+// handleEventStream streams the events of the applications of the user as
+// server-sent events. Without a last event id only new events are sent. The
+// stream continues after the position of the last event it sent, so events,
+// which commit after events with a greater id, are not missed.
+func (s ApplicationService) handleEventStream(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		ApiResponse(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	afterPosition, resume, err := lastEventId(r)
+	if err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+
+	// Subscribe before reading the events, so no event is missed in between.
+	wakeup := s.events.subscribe(userId)
+	defer s.events.unsubscribe(userId, wakeup)
+
+	if !resume {
+		if afterPosition, err = s.EventController.GetLatestEventPosition(); err != nil {
+			ApiResponse(w, "Could not fetch events", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", listenRetryInterval.Milliseconds())
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(s.Config.Events.heartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		events, err := s.EventController.GetEventsAfter(userId, afterPosition, eventBatchSize)
+		if err != nil {
+			// The client reconnects with the id of the last event it received.
+			log.Printf("Could not fetch events of user %d: %v", userId, err)
+			return
+		}
+
+		for _, event := range events {
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+
+			afterPosition = event.Position
+		}
+
+		if len(events) > 0 {
+			flusher.Flush()
+		}
+
+		if len(events) == eventBatchSize {
+			continue
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-wakeup:
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+
+			flusher.Flush()
+		}
+	}
+}
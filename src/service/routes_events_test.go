@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/auth"
+	"flhansen/application-manager/application-service/src/controller"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// openEventStream connects to the event stream of the user.
+func openEventStream(t *testing.T, s ApplicationService, server *httptest.Server, userId int, lastEventId string) *bufio.Reader {
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/events", nil)
+
+	token, err := auth.GenerateToken(userId, "testuser", jwt.SigningMethodHS256, s.Config.Jwt.SignKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Add("Authorization", token)
+	if lastEventId != "" {
+		req.Header.Add("Last-Event-ID", lastEventId)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent reads the fields of the next event from the stream. Comments and
+// messages without an event are skipped.
+func readEvent(t *testing.T, stream *bufio.Reader) map[string]string {
+	fields := map[string]string{}
+
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if fields["event"] != "" {
+				return fields
+			}
+
+			fields = map[string]string{}
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		name, value, _ := strings.Cut(line, ": ")
+		fields[name] = value
+	}
+}
+
+func TestRouteEventStream(t *testing.T) {
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
+		Jwt:    JwtConfig{SignKey: []byte("supersecretsignkey")},
+		Events: EventConfig{Heartbeat: 50 * time.Millisecond},
+	}, MemoryRepositories(store))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.ListenEvents(ctx)
+
+	// The streams are closed by their cleanups before the server.
+	server := httptest.NewServer(s.Router)
+	t.Cleanup(server.Close)
+
+	resp, _ := serveTestRequest(t, s, http.MethodGet, "/api/events?lastEventId=abc", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	id, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Go Developer"})
+	store.InsertApplication(controller.Application{UserId: 2, WorkTypeId: 1, StatusId: 2})
+
+	// A stream resumes after the last event id and skips other users.
+	stream := openEventStream(t, s, server, 1, "0")
+	event := readEvent(t, stream)
+	assert.Equal(t, "1", event["id"])
+	assert.Equal(t, controller.EventApplicationCreated, event["event"])
+
+	var payload struct {
+		Application controller.Application `json:"application"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(event["data"]), &payload))
+	assert.Equal(t, id, payload.Application.Id)
+	assert.Equal(t, "Go Developer", payload.Application.JobTitle)
+
+	// A new stream starts with the next event.
+	fresh := openEventStream(t, s, server, 1, "")
+
+	store.DeleteApplication(id)
+	event = readEvent(t, stream)
+	assert.Equal(t, "3", event["id"])
+	assert.Equal(t, controller.EventApplicationDeleted, event["event"])
+
+	event = readEvent(t, fresh)
+	assert.Equal(t, "3", event["id"])
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/export"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+var exportColumns = []export.Column{
+	{Key: "id", Title: "Id"},
+	{Key: "jobTitle", Title: "Job title"},
+	{Key: "companyName", Title: "Company"},
+	{Key: "workType", Title: "Work type"},
+	{Key: "status", Title: "Status"},
+	{Key: "submissionDate", Title: "Submission date"},
+	{Key: "wantedSalary", Title: "Wanted salary"},
+	{Key: "acceptedSalary", Title: "Accepted salary"},
+	{Key: "startDate", Title: "Start date"},
+	{Key: "commentary", Title: "Commentary"},
+	{Key: "jobPostingUrl", Title: "Job posting url"},
+	{Key: "statusChangedAt", Title: "Status changed at"},
+	{Key: "stale", Title: "Stale"},
+}
+
+// exportDate formats a date of an application. Unset dates are exported as
+// empty cells.
+func exportDate(date time.Time) interface{} {
+	if date.IsZero() {
+		return nil
+	}
+
+	return date.Format(dateLayout)
+}
+
+// exportRow returns the values of the exportColumns of the application. The
+// ids of the work type and the status are replaced by their names.
+func exportRow(application controller.Application, workTypes map[int]string, statuses map[int]string) []interface{} {
+	return []interface{}{
+		application.Id,
+		application.JobTitle,
+		application.CompanyName,
+		workTypes[application.WorkTypeId],
+		statuses[application.StatusId],
+		exportDate(application.SubmissionDate),
+		application.WantedSalary,
+		application.AcceptedSalary,
+		exportDate(application.StartDate),
+		application.Commentary,
+		application.JobPostingUrl,
+		application.StatusChangedAt.UTC().Format(time.RFC3339),
+		application.Stale,
+	}
+}
+
+// handleExportApplications streams all applications of the user in the
+// requested format, CSV by default. Errors, which occur after the first row
+// was written, abort the response, because the status was sent already.
+func (s ApplicationService) handleExportApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+
+	if format != export.FormatCSV && format != export.FormatJSON && format != export.FormatXLSX {
+		ApiResponse(w, "format must be csv, json or xlsx", http.StatusBadRequest)
+		return
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+
+	workTypes, err := s.TypesController.GetWorkTypes()
+	if err != nil {
+		ApiResponse(w, "Could not export applications", http.StatusInternalServerError)
+		return
+	}
+
+	statuses, err := s.TypesController.GetUsableStatuses(userId)
+	if err != nil {
+		ApiResponse(w, "Could not export applications", http.StatusInternalServerError)
+		return
+	}
+
+	workTypeNames := map[int]string{}
+	for _, workType := range workTypes {
+		workTypeNames[workType.Id] = workType.Name
+	}
+
+	statusNames := map[int]string{}
+	for _, status := range statuses {
+		statusNames[status.Id] = status.Name
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="applications.`+format+`"`)
+
+	writer, err := export.NewWriter(format, w, exportColumns)
+	if err == nil {
+		err = s.ApplicationController.StreamApplications(userId, func(application controller.Application) error {
+			return writer.WriteRow(exportRow(application, workTypeNames, statusNames))
+		})
+	}
+
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		log.Printf("Could not export the applications of user %d: %v", userId, err)
+		panic(http.ErrAbortHandler)
+	}
+}
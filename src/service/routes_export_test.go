@@ -0,0 +1,92 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/controller"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteExportApplications(t *testing.T) {
+	s, store := newTestService()
+
+	store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer", CompanyName: "ACME, Inc.",
+		SubmissionDate: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		StartDate:      time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+		WantedSalary:   60000,
+	})
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 3, StatusId: 2, JobTitle: "Rust Developer", SubmissionDate: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC)})
+	store.InsertApplication(controller.Application{UserId: 2, WorkTypeId: 1, StatusId: 2, JobTitle: "Other"})
+
+	resp, _ := serveTestRequest(t, s, http.MethodGet, "/api/applications/export?format=pdf", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications/export", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="applications.csv"`, resp.Header().Get("Content-Disposition"))
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, "Job title", records[0][1])
+	assert.Equal(t, []string{"Go Developer", "ACME, Inc.", "Remote", "Accepted", "2022-06-01", "60000", "0", "2022-08-01"}, records[1][1:9])
+	assert.Equal(t, []string{"Rust Developer", "", "Hybrid", "Pending", "2022-06-02"}, records[2][1:6])
+	assert.Equal(t, "", records[2][8])
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications/export?format=json", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+
+	var rows []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), &rows))
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "Accepted", rows[0]["status"])
+	assert.Equal(t, float64(60000), rows[0]["wantedSalary"])
+	assert.Nil(t, rows[1]["startDate"])
+	assert.Equal(t, false, rows[1]["stale"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications/export?format=xlsx", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	_, err = zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	assert.Nil(t, err)
+}
+
+func TestRouteExportApplicationsCanBeImported(t *testing.T) {
+	s, store := newTestService()
+
+	// The application stays in a global status after the user defined own
+	// statuses.
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, strings.NewReader(`{"name": "Applied"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "-Go Developer-", CompanyName: "=Acme",
+		SubmissionDate: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		Commentary:     "+49 170 1234567",
+	})
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications/export", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	exported := resp.Body.String()
+	assert.Contains(t, exported, ",'=Acme,Remote,Pending,")
+
+	resp, res := serveTestImport(t, s, "/api/applications/import", 2, exported, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(1), res["import"].(map[string]interface{})["created"])
+
+	applications, _ := store.GetApplications(2)
+	assert.Equal(t, 1, len(applications))
+	assert.Equal(t, "-Go Developer-", applications[0].JobTitle)
+	assert.Equal(t, "=Acme", applications[0].CompanyName)
+	assert.Equal(t, "+49 170 1234567", applications[0].Commentary)
+	assert.Equal(t, 2, applications[0].StatusId)
+}
@@ -0,0 +1,537 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/export"
+	"flhansen/application-manager/application-service/src/importer"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// maxImportSize limits the size of an imported file in bytes.
+	maxImportSize = 5 << 20
+
+	// maxImportRows limits the number of applications imported at once.
+	maxImportRows = 5000
+
+	// maxArchiveSize limits the uncompressed size of the files read from an
+	// uploaded archive in bytes.
+	maxArchiveSize = 4 * maxImportSize
+
+	// The work type and the status of applications imported from a job board
+	// export, if the request does not set them.
+	defaultImportWorkType = "OnSite"
+	defaultImportStatus   = "Pending"
+)
+
+// The states of the rows of an import. Valid rows are only reported by dry
+// runs, they are created otherwise. Invalid rows are skipped by imports,
+// which do not reject them.
+const (
+	importValid     = "valid"
+	importCreated   = "created"
+	importDuplicate = "duplicate"
+	importInvalid   = "invalid"
+	importSkipped   = "skipped"
+)
+
+// importRecord is an application read from an import. Its work type and
+// status are referenced by name. Errors holds the problems found, while the
+// record was read. File is set for records read from an archive.
+type importRecord struct {
+	File        string
+	Row         int
+	Application controller.Application
+	WorkType    string
+	Status      string
+	Errors      []string
+}
+
+// importRow reports the outcome of a row. Duplicates reference the existing
+// application or the earlier row of the same import. DuplicateOfFile is set,
+// if the earlier row was read from a file of an archive.
+type importRow struct {
+	File            string   `json:"file,omitempty"`
+	Row             int      `json:"row"`
+	Status          string   `json:"status"`
+	Errors          []string `json:"errors,omitempty"`
+	ApplicationId   *int     `json:"applicationId,omitempty"`
+	DuplicateOf     *int     `json:"duplicateOf,omitempty"`
+	DuplicateOfFile string   `json:"duplicateOfFile,omitempty"`
+	DuplicateOfRow  *int     `json:"duplicateOfRow,omitempty"`
+}
+
+type importReport struct {
+	DryRun     bool        `json:"dryRun"`
+	Created    int         `json:"created"`
+	Duplicates int         `json:"duplicates"`
+	Invalid    int         `json:"invalid"`
+	Skipped    int         `json:"skipped"`
+	Rows       []importRow `json:"rows"`
+}
+
+// duplicateKey identifies an application by its company, job title and
+// submission date. Companies are compared by their normalised names.
+func duplicateKey(application controller.Application) string {
+	return strings.Join([]string{
+		controller.NormalizeCompanyName(application.CompanyName),
+		strings.ToLower(strings.TrimSpace(application.JobTitle)),
+		application.SubmissionDate.Format(dateLayout),
+	}, "\x00")
+}
+
+// importOptions control how the records of an import are handled.
+// SkipInvalid imports the valid records, even if other records are invalid,
+// which suits job board exports with entries, that are no applications.
+type importOptions struct {
+	DryRun      bool
+	SkipInvalid bool
+}
+
+// importRecords validates the records, resolves their work types and
+// statuses and detects duplicates of existing applications and of earlier
+// records. Unless it is a dry run or a record is invalid and invalid records
+// are not skipped, the other records are inserted in a single transaction.
+// Duplicates are skipped.
+func (s ApplicationService) importRecords(userId int, records []importRecord, options importOptions) (importReport, error) {
+	report := importReport{DryRun: options.DryRun, Rows: []importRow{}}
+
+	workTypes, err := s.TypesController.GetWorkTypes()
+	if err != nil {
+		return report, err
+	}
+
+	statuses, err := s.TypesController.GetUsableStatuses(userId)
+	if err != nil {
+		return report, err
+	}
+
+	existing := map[string]int{}
+	err = s.ApplicationController.StreamApplications(userId, func(application controller.Application) error {
+		existing[duplicateKey(application)] = application.Id
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	imported := map[string]importRecord{}
+	var applications []controller.Application
+	var created []int
+
+	for _, record := range records {
+		row := importRow{File: record.File, Row: record.Row, Errors: record.Errors}
+		application := record.Application
+		application.UserId = userId
+
+		if record.WorkType == "" {
+			row.Errors = append(row.Errors, "the work type is missing")
+		} else if application.WorkTypeId = workTypeByName(workTypes, record.WorkType); application.WorkTypeId == 0 {
+			row.Errors = append(row.Errors, fmt.Sprintf("the work type %s does not exist", record.WorkType))
+		}
+
+		if record.Status == "" {
+			row.Errors = append(row.Errors, "the status is missing")
+		} else if application.StatusId = statusByName(statuses, record.Status); application.StatusId == 0 {
+			row.Errors = append(row.Errors, fmt.Sprintf("the status %s does not exist", record.Status))
+		}
+
+		row.Errors = append(row.Errors, validateImportedApplication(application)...)
+
+		key := duplicateKey(application)
+		switch {
+		case len(row.Errors) > 0 && options.SkipInvalid:
+			row.Status = importSkipped
+			report.Skipped++
+		case len(row.Errors) > 0:
+			row.Status = importInvalid
+			report.Invalid++
+		case existing[key] != 0:
+			id := existing[key]
+			row.Status = importDuplicate
+			row.DuplicateOf = &id
+			report.Duplicates++
+		case imported[key].Row != 0:
+			earlier := imported[key]
+			row.Status = importDuplicate
+			row.DuplicateOfFile = earlier.File
+			row.DuplicateOfRow = &earlier.Row
+			report.Duplicates++
+		default:
+			row.Status = importValid
+			imported[key] = record
+			applications = append(applications, application)
+			created = append(created, len(report.Rows))
+		}
+
+		report.Rows = append(report.Rows, row)
+	}
+
+	if options.DryRun || report.Invalid > 0 || len(applications) == 0 {
+		return report, nil
+	}
+
+	ids, err := s.ApplicationController.InsertApplications(applications)
+	if err != nil {
+		return report, err
+	}
+
+	for i, index := range created {
+		id := ids[i]
+		report.Rows[index].Status = importCreated
+		report.Rows[index].ApplicationId = &id
+	}
+
+	report.Created = len(ids)
+	return report, nil
+}
+
+func workTypeByName(workTypes []controller.WorkType, name string) int {
+	for _, workType := range workTypes {
+		if strings.EqualFold(workType.Name, strings.TrimSpace(name)) {
+			return workType.Id
+		}
+	}
+
+	return 0
+}
+
+// statusByName returns the first status with the name. The usable statuses
+// list the statuses of the user first, so they win over global statuses with
+// the same name.
+func statusByName(statuses []controller.ApplicationStatus, name string) int {
+	for _, status := range statuses {
+		if strings.EqualFold(status.Name, strings.TrimSpace(name)) {
+			return status.Id
+		}
+	}
+
+	return 0
+}
+
+// validateImportedApplication checks the values, which are restricted by the
+// application table.
+func validateImportedApplication(application controller.Application) []string {
+	var problems []string
+
+	if strings.TrimSpace(application.JobTitle) == "" {
+		problems = append(problems, "the job title is missing")
+	}
+
+	if len(application.JobTitle) > 255 {
+		problems = append(problems, "the job title must not be longer than 255 characters")
+	}
+
+	if len(application.CompanyName) > 255 {
+		problems = append(problems, "the company name must not be longer than 255 characters")
+	}
+
+	if len(application.Commentary) > 500 {
+		problems = append(problems, "the commentary must not be longer than 500 characters")
+	}
+
+	if !isJobPostingUrlValid(application.JobPostingUrl) {
+		problems = append(problems, "the job posting url must be an absolute http or https url of up to 2000 characters")
+	}
+
+	if application.WantedSalary < 0 || application.AcceptedSalary < 0 {
+		problems = append(problems, "salaries must not be negative")
+	}
+
+	return problems
+}
+
+// importFields are the fields of an application, which can be read from a
+// CSV file, by their keys in the column mapping.
+var importFields = []string{"jobTitle", "companyName", "workType", "status", "submissionDate", "wantedSalary", "acceptedSalary", "startDate", "commentary", "jobPostingUrl"}
+
+// importLayout converts a date format like DD.MM.YYYY to a time layout.
+func importLayout(format string) (string, error) {
+	if format == "" {
+		return dateLayout, nil
+	}
+
+	layout := strings.NewReplacer("YYYY", "2006", "MM", "01", "DD", "02").Replace(strings.ToUpper(format))
+	if strings.ContainsAny(layout, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		return "", fmt.Errorf("the date format %s is not supported, use e.g. YYYY-MM-DD or DD.MM.YYYY", format)
+	}
+
+	return layout, nil
+}
+
+// mapImportColumns returns the index of the column of every mapped field.
+// Fields, which are not mapped, are read from the column named like their
+// key or their title in the export, so exported files can be imported again.
+func mapImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	find := func(names ...string) int {
+		for i, column := range header {
+			for _, name := range names {
+				if strings.EqualFold(strings.TrimSpace(column), name) {
+					return i
+				}
+			}
+		}
+
+		return -1
+	}
+
+	columns := map[string]int{}
+	for field, column := range mapping {
+		if !containsString(importFields, field) {
+			return nil, fmt.Errorf("the field %s cannot be imported", field)
+		}
+
+		if columns[field] = find(column); columns[field] < 0 {
+			return nil, fmt.Errorf("the column %s does not exist", column)
+		}
+	}
+
+	for _, column := range exportColumns {
+		if _, ok := columns[column.Key]; !ok && containsString(importFields, column.Key) {
+			if i := find(column.Key, column.Title); i >= 0 {
+				columns[column.Key] = i
+			}
+		}
+	}
+
+	if _, ok := columns["jobTitle"]; !ok {
+		return nil, errors.New("the column of the job title is missing")
+	}
+
+	return columns, nil
+}
+
+// readCSVRecords reads the applications from a CSV file with a header. The
+// row numbers are the line numbers of the records. Texts, which the export
+// escaped to not be evaluated as formulas, are unescaped.
+func readCSVRecords(reader io.Reader, mapping map[string]string, layout string) ([]importRecord, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.New("the file must start with a header")
+	}
+
+	columns, err := mapImportColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []importRecord
+	for {
+		values, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("the file is not a valid CSV file: %v", err)
+		}
+
+		if len(records) == maxImportRows {
+			return nil, fmt.Errorf("at most %d applications can be imported at once", maxImportRows)
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		record := importRecord{Row: line}
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(values) {
+				return strings.TrimSpace(export.UnescapeFormula(values[i]))
+			}
+
+			return ""
+		}
+
+		record.Application.JobTitle = value("jobTitle")
+		record.Application.CompanyName = value("companyName")
+		record.Application.Commentary = value("commentary")
+		record.Application.JobPostingUrl = value("jobPostingUrl")
+		record.WorkType = value("workType")
+		record.Status = value("status")
+
+		dates := []struct {
+			field  string
+			target *time.Time
+		}{{"submissionDate", &record.Application.SubmissionDate}, {"startDate", &record.Application.StartDate}}
+
+		for _, date := range dates {
+			if text := value(date.field); text != "" {
+				parsed, err := time.Parse(layout, text)
+				if err != nil {
+					record.Errors = append(record.Errors, fmt.Sprintf("%s is not a date like %s", text, time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC).Format(layout)))
+				}
+
+				*date.target = parsed
+			}
+		}
+
+		salaries := []struct {
+			field  string
+			target *float32
+		}{{"wantedSalary", &record.Application.WantedSalary}, {"acceptedSalary", &record.Application.AcceptedSalary}}
+
+		for _, salary := range salaries {
+			if text := value(salary.field); text != "" {
+				parsed, err := strconv.ParseFloat(text, 32)
+				if err != nil {
+					record.Errors = append(record.Errors, fmt.Sprintf("%s is not a number", text))
+				}
+
+				*salary.target = float32(parsed)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// readSourceRecords reads the applications from the export of a job board,
+// which is uploaded as a ZIP archive or as a single file. The exports do not
+// contain work types and statuses, so every record gets the given ones.
+func readSourceRecords(parser importer.Parser, file io.Reader, workType string, status string) ([]importRecord, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.New("the file could not be read")
+	}
+
+	entries, err := importer.Read(parser, content, maxArchiveSize)
+	if errors.Is(err, importer.ErrTooLarge) {
+		return nil, fmt.Errorf("the files of the archive must not be larger than %d bytes", maxArchiveSize)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) > maxImportRows {
+		return nil, fmt.Errorf("at most %d applications can be imported at once", maxImportRows)
+	}
+
+	records := make([]importRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = importRecord{
+			File:        entry.File,
+			Row:         entry.Row,
+			Application: entry.Application,
+			WorkType:    workType,
+			Status:      status,
+			Errors:      entry.Errors,
+		}
+	}
+
+	return records, nil
+}
+
+// handleImportApplications imports the applications from the file part of a
+// multipart request. With dryRun=true the rows are only validated.
+//
+// By default the file is a CSV file. The mapping part maps the fields to the
+// columns of the file as JSON, the dateFormat part sets the format of the
+// dates. Otherwise the source part names the job board, whose export is
+// uploaded, and the workType and status parts set the work type and the
+// status of the applications. Entries of an export, which cannot be
+// imported, are skipped.
+func (s ApplicationService) handleImportApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	dryRun, err := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+	if err != nil && r.URL.Query().Get("dryRun") != "" {
+		ApiResponse(w, "dryRun must be true or false", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize+1<<20)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		ApiResponse(w, "The file must be uploaded as multipart/form-data", http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		ApiResponse(w, "The file is missing", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if source := r.FormValue("source"); source != "" && source != "csv" {
+		parser, ok := importer.Lookup(source)
+		if !ok {
+			ApiResponse(w, fmt.Sprintf("The source %s is not supported, use csv, %s", source, strings.Join(importer.Sources(), ", ")), http.StatusBadRequest)
+			return
+		}
+
+		workType, status := r.FormValue("workType"), r.FormValue("status")
+		if workType == "" {
+			workType = defaultImportWorkType
+		}
+
+		if status == "" {
+			status = defaultImportStatus
+		}
+
+		records, err := readSourceRecords(parser, file, workType, status)
+		if err != nil {
+			ApiResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.respondImport(w, p, records, importOptions{DryRun: dryRun, SkipInvalid: true})
+		return
+	}
+
+	var mapping map[string]string
+	if value := r.FormValue("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			ApiResponse(w, "The mapping must be a JSON object of fields and columns", http.StatusBadRequest)
+			return
+		}
+	}
+
+	layout, err := importLayout(r.FormValue("dateFormat"))
+	if err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := readCSVRecords(file, mapping, layout)
+	if err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.respondImport(w, p, records, importOptions{DryRun: dryRun})
+}
+
+// respondImport imports the records and writes the report. Imports with
+// invalid records are rejected as a whole, unless they are skipped.
+func (s ApplicationService) respondImport(w http.ResponseWriter, p httprouter.Params, records []importRecord, options importOptions) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	report, err := s.importRecords(userId, records, options)
+	if err != nil {
+		ApiResponse(w, "Could not import applications", http.StatusInternalServerError)
+		return
+	}
+
+	status, message := http.StatusOK, "Applications imported"
+	if options.DryRun {
+		message = "Applications validated"
+	} else if report.Invalid > 0 {
+		status, message = http.StatusBadRequest, "The import contains invalid rows, no application was imported"
+	}
+
+	w.WriteHeader(status)
+	fmt.Fprint(w, NewApiResponseObject(status, message, map[string]interface{}{
+		"import": report,
+	}))
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/auth"
+	"flhansen/application-manager/application-service/src/controller"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveTestImport uploads the CSV file along with the form values.
+func serveTestImport(t *testing.T, s ApplicationService, path string, userId int, content string, values map[string]string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for name, value := range values {
+		writer.WriteField(name, value)
+	}
+
+	part, _ := writer.CreateFormFile("file", "applications.csv")
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	token, err := auth.GenerateToken(userId, "testuser", jwt.SigningMethodHS256, s.Config.Jwt.SignKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Add("Authorization", token)
+	recorder := httptest.NewRecorder()
+	s.Router.ServeHTTP(recorder, req)
+
+	var res map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &res)
+
+	return recorder, res
+}
+
+const testImport = `Position,Employer,Type,State,Applied on,Salary
+Go Developer,ACME,Remote,pending,01.06.2022,60000
+Rust Developer,ACME,Hybrid,Accepted,02.06.2022,
+Go Developer,acme,remote,Pending,01.06.2022,
+Java Developer,Initech,Remote,Pending,03.06.2022,
+`
+
+var testImportForm = map[string]string{
+	"mapping":    `{"jobTitle": "Position", "companyName": "Employer", "workType": "Type", "status": "State", "submissionDate": "Applied on", "wantedSalary": "Salary"}`,
+	"dateFormat": "DD.MM.YYYY",
+}
+
+func TestRouteImportApplications(t *testing.T) {
+	s, store := newTestService()
+	store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Java Developer", CompanyName: "Initech",
+		SubmissionDate: time.Date(2022, 6, 3, 0, 0, 0, 0, time.UTC),
+	})
+
+	resp, res := serveTestImport(t, s, "/api/applications/import?dryRun=true", 1, testImport, testImportForm)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	report := res["import"].(map[string]interface{})
+	assert.Equal(t, true, report["dryRun"])
+	assert.Equal(t, float64(0), report["created"])
+	assert.Equal(t, float64(2), report["duplicates"])
+
+	rows := report["rows"].([]interface{})
+	assert.Equal(t, 4, len(rows))
+	assert.Equal(t, map[string]interface{}{"row": float64(2), "status": "valid"}, rows[0])
+	assert.Equal(t, "valid", rows[1].(map[string]interface{})["status"])
+	assert.Equal(t, map[string]interface{}{"row": float64(4), "status": "duplicate", "duplicateOfRow": float64(2)}, rows[2])
+	assert.Equal(t, map[string]interface{}{"row": float64(5), "status": "duplicate", "duplicateOf": float64(1)}, rows[3])
+
+	applications, _ := store.GetApplications(1)
+	assert.Equal(t, 1, len(applications))
+
+	resp, res = serveTestImport(t, s, "/api/applications/import", 1, testImport, testImportForm)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	report = res["import"].(map[string]interface{})
+	assert.Equal(t, float64(2), report["created"])
+	assert.Equal(t, "created", report["rows"].([]interface{})[0].(map[string]interface{})["status"])
+
+	applications, _ = store.GetApplications(1)
+	assert.Equal(t, 3, len(applications))
+	assert.Equal(t, "Go Developer", applications[1].JobTitle)
+	assert.Equal(t, 1, applications[1].WorkTypeId)
+	assert.Equal(t, 2, applications[1].StatusId)
+	assert.Equal(t, float32(60000), applications[1].WantedSalary)
+	assert.Equal(t, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), applications[1].SubmissionDate)
+	assert.Equal(t, 1, applications[2].StatusId)
+
+	// Importing the same file again only finds duplicates.
+	resp, res = serveTestImport(t, s, "/api/applications/import", 1, testImport, testImportForm)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(4), res["import"].(map[string]interface{})["duplicates"])
+}
+
+func TestRouteImportApplicationsInvalid(t *testing.T) {
+	s, store := newTestService()
+
+	// Without a mapping the columns are found by their names in the export.
+	content := "Job title,Work type,Status,Submission date,Wanted salary\n" +
+		"Go Developer,Remote,Pending,2022-06-01,60000\n" +
+		",Office,Pending,2022-06-31,lots\n"
+
+	resp, res := serveTestImport(t, s, "/api/applications/import", 1, content, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	report := res["import"].(map[string]interface{})
+	assert.Equal(t, float64(1), report["invalid"])
+	assert.Equal(t, float64(0), report["created"])
+
+	row := report["rows"].([]interface{})[1].(map[string]interface{})
+	assert.Equal(t, "invalid", row["status"])
+	assert.Equal(t, []interface{}{
+		"2022-06-31 is not a date like 2022-06-30",
+		"lots is not a number",
+		"the work type Office does not exist",
+		"the job title is missing",
+	}, row["errors"])
+
+	// Nothing is imported, if a row is invalid.
+	applications, _ := store.GetApplications(1)
+	assert.Empty(t, applications)
+
+	resp, _ = serveTestImport(t, s, "/api/applications/import", 1, content, map[string]string{"mapping": `{"salary": "Wanted salary"}`})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestImport(t, s, "/api/applications/import", 1, content, map[string]string{"mapping": `{"jobTitle": "Title"}`})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestImport(t, s, "/api/applications/import", 1, content, map[string]string{"dateFormat": "%Y-%m-%d"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestImport(t, s, "/api/applications/import", 1, "Status\nPending\n", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/applications/42", 1, nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+const testLinkedInExport = `Application Date,Contact Email,Contact Phone Number,Company Name,Job Title,Job Url,Resume Name,Question And Answers
+"6/30/22, 10:15 AM",me@example.com,,ACME,Go Developer,https://www.linkedin.com/jobs/view/1,resume.pdf,
+"7/1/22, 9:00 PM",me@example.com,,Initech,,https://www.linkedin.com/jobs/view/2,,
+"7/2/22, 8:00 AM",me@example.com,,Initech,Java Developer,,,
+`
+
+func TestRouteImportApplicationsFromSource(t *testing.T) {
+	s, store := newTestService()
+	store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Java Developer", CompanyName: "Initech",
+		SubmissionDate: time.Date(2022, 7, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	file, _ := writer.Create("Jobs/Job Applications.csv")
+	file.Write([]byte(testLinkedInExport))
+	writer.Close()
+
+	resp, res := serveTestImport(t, s, "/api/applications/import", 1, archive.String(), map[string]string{"source": "linkedin", "workType": "remote"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	report := res["import"].(map[string]interface{})
+	assert.Equal(t, float64(1), report["created"])
+	assert.Equal(t, float64(1), report["skipped"])
+	assert.Equal(t, float64(1), report["duplicates"])
+
+	rows := report["rows"].([]interface{})
+	assert.Equal(t, "Jobs/Job Applications.csv", rows[0].(map[string]interface{})["file"])
+	assert.Equal(t, map[string]interface{}{
+		"file": "Jobs/Job Applications.csv", "row": float64(3), "status": "skipped", "errors": []interface{}{"the job title is missing"},
+	}, rows[1])
+
+	applications, _ := store.GetApplications(1)
+	assert.Equal(t, 2, len(applications))
+	assert.Equal(t, "Go Developer", applications[1].JobTitle)
+	assert.Equal(t, "ACME", applications[1].CompanyName)
+	assert.Equal(t, "https://www.linkedin.com/jobs/view/1", applications[1].JobPostingUrl)
+	assert.Equal(t, time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC), applications[1].SubmissionDate)
+	assert.Equal(t, 1, applications[1].WorkTypeId)
+	assert.Equal(t, 2, applications[1].StatusId)
+
+	resp, res = serveTestImport(t, s, "/api/applications/import?dryRun=true", 1, `[{"title": "Rust Developer", "company": "Globex"}]`, map[string]string{"source": "json"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "valid", res["import"].(map[string]interface{})["rows"].([]interface{})[0].(map[string]interface{})["status"])
+
+	resp, _ = serveTestImport(t, s, "/api/applications/import", 1, testLinkedInExport, map[string]string{"source": "monster"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestImport(t, s, "/api/applications/import", 1, "Name\nACME\n", map[string]string{"source": "linkedin"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteImportExportedApplicationsWithOwnStatuses(t *testing.T) {
+	s, store := newTestService()
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, strings.NewReader(`{"name": "Interview"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	interviewId := int(res["status"].(map[string]interface{})["id"].(float64))
+
+	store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 1, StatusId: interviewId, JobTitle: "Go Developer", CompanyName: "ACME",
+		SubmissionDate: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+	store.InsertApplication(controller.Application{
+		UserId: 1, WorkTypeId: 3, StatusId: 1, JobTitle: "Rust Developer", CompanyName: "Initech",
+		SubmissionDate: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications/export", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	exported := resp.Body.String()
+
+	// Importing the export again finds the duplicates instead of rejecting
+	// the global status.
+	resp, res = serveTestImport(t, s, "/api/applications/import", 1, exported, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	report := res["import"].(map[string]interface{})
+	assert.Equal(t, float64(0), report["invalid"])
+	assert.Equal(t, float64(2), report["duplicates"])
+
+	store.DeleteApplication(1)
+	store.DeleteApplication(2)
+
+	resp, res = serveTestImport(t, s, "/api/applications/import", 1, exported, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(2), res["import"].(map[string]interface{})["created"])
+
+	applications, _ := store.GetApplications(1)
+	statuses := map[string]int{}
+	for _, application := range applications {
+		statuses[application.JobTitle] = application.StatusId
+	}
+
+	assert.Equal(t, map[string]int{"Go Developer": interviewId, "Rust Developer": 1}, statuses)
+}
+
+func TestRouteImportApplicationsFromSourceWithOwnStatuses(t *testing.T) {
+	s, store := newTestService()
+
+	// The own status of the user wins over the global status of the same name.
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, strings.NewReader(`{"name": "Pending"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	pendingId := int(res["status"].(map[string]interface{})["id"].(float64))
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for _, name := range []string{"first/Job Applications.csv", "second/Job Applications.csv"} {
+		file, _ := writer.Create(name)
+		file.Write([]byte(testLinkedInExport))
+	}
+	writer.Close()
+
+	resp, res = serveTestImport(t, s, "/api/applications/import", 1, archive.String(), map[string]string{"source": "linkedin"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	report := res["import"].(map[string]interface{})
+	assert.Equal(t, float64(2), report["created"])
+	assert.Equal(t, float64(2), report["duplicates"])
+
+	rows := report["rows"].([]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"file": "second/Job Applications.csv", "row": float64(2), "status": "duplicate",
+		"duplicateOfFile": "first/Job Applications.csv", "duplicateOfRow": float64(2),
+	}, rows[3])
+
+	applications, _ := store.GetApplications(1)
+	assert.Equal(t, 2, len(applications))
+	for _, application := range applications {
+		assert.Equal(t, pendingId, application.StatusId)
+	}
+
+	// Applications may be imported into global statuses, too.
+	resp, res = serveTestImport(t, s, "/api/applications/import?dryRun=true", 1, `[{"title": "Rust Developer", "company": "Globex"}]`,
+		map[string]string{"source": "json", "status": "Declined"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "valid", res["import"].(map[string]interface{})["rows"].([]interface{})[0].(map[string]interface{})["status"])
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// ownedInterview fetches the interview referenced by the interviewId
+// parameter and makes sure, it belongs to the application of the requesting
+// user. Otherwise an error response is written and false is returned.
+func (s ApplicationService) ownedInterview(w http.ResponseWriter, p httprouter.Params) (controller.Interview, bool) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return controller.Interview{}, false
+	}
+
+	interviewId, err := strconv.Atoi(p.ByName("interviewId"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the interview id", http.StatusBadRequest)
+		return controller.Interview{}, false
+	}
+
+	interview, err := s.InterviewController.GetInterview(interviewId)
+	if err != nil || interview.ApplicationId != application.Id {
+		ApiResponse(w, "This interview does not exist", http.StatusBadRequest)
+		return controller.Interview{}, false
+	}
+
+	return interview, true
+}
+
+// decodeInterview parses the request body. The outcome of a new interview
+// is pending, if it is not given.
+func decodeInterview(w http.ResponseWriter, r *http.Request) (controller.Interview, bool) {
+	var interview controller.Interview
+	if err := json.NewDecoder(r.Body).Decode(&interview); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return controller.Interview{}, false
+	}
+
+	if interview.Outcome == "" {
+		interview.Outcome = controller.OutcomePending
+	}
+
+	if err := interview.Validate(); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return controller.Interview{}, false
+	}
+
+	return interview, true
+}
+
+func (s ApplicationService) handleGetInterviews(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	interviews, err := s.InterviewController.GetInterviews(application.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch interviews", http.StatusInternalServerError)
+		return
+	}
+
+	if interviews == nil {
+		interviews = []controller.Interview{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched interviews", map[string]interface{}{
+		"interviews": interviews,
+	}))
+}
+
+func (s ApplicationService) handleCreateInterview(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	interview, ok := decodeInterview(w, r)
+	if !ok {
+		return
+	}
+
+	interview.ApplicationId = application.Id
+	id, err := s.InterviewController.InsertInterview(interview)
+	if err != nil {
+		ApiResponse(w, "Could not create interview", http.StatusInternalServerError)
+		return
+	}
+
+	newInterview, _ := s.InterviewController.GetInterview(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Interview created", map[string]interface{}{
+		"interview": newInterview,
+	}))
+}
+
+func (s ApplicationService) handleGetInterview(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	interview, ok := s.ownedInterview(w, p)
+	if !ok {
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched interview", map[string]interface{}{
+		"interview": interview,
+	}))
+}
+
+func (s ApplicationService) handleUpdateInterview(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	interview, ok := s.ownedInterview(w, p)
+	if !ok {
+		return
+	}
+
+	interviewRequest, ok := decodeInterview(w, r)
+	if !ok {
+		return
+	}
+
+	interviewRequest.Id = interview.Id
+	interviewRequest.ApplicationId = interview.ApplicationId
+
+	if err := s.InterviewController.UpdateInterview(interviewRequest); err != nil {
+		if errors.Is(err, controller.ErrNotFound) {
+			ApiResponse(w, "This interview does not exist", http.StatusBadRequest)
+			return
+		}
+
+		ApiResponse(w, "Could not update interview", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Interview updated", http.StatusOK)
+}
+
+func (s ApplicationService) handleDeleteInterview(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	interview, ok := s.ownedInterview(w, p)
+	if !ok {
+		return
+	}
+
+	if err := s.InterviewController.DeleteInterview(interview.Id); err != nil {
+		if errors.Is(err, controller.ErrNotFound) {
+			ApiResponse(w, "This interview does not exist", http.StatusBadRequest)
+			return
+		}
+
+		ApiResponse(w, "Could not delete interview", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Interview deleted", http.StatusOK)
+}
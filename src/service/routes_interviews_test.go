@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bytes"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testInterviewBody = `{"scheduledAt": "2022-07-01T10:00:00Z", "durationMinutes": 45, "type": "video", "interviewers": ["Jane Doe"]}`
+
+func TestRouteInterviews(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	path := fmt.Sprintf("/api/applications/%d/interviews", applicationId)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, path, 1, bytes.NewBufferString(testInterviewBody))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	interview := res["interview"].(map[string]interface{})
+	assert.Equal(t, "pending", interview["outcome"])
+	interviewPath := fmt.Sprintf("%s/%v", path, interview["id"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, interviewPath, 1, bytes.NewBufferString(`{"scheduledAt": "2022-07-01T10:00:00Z", "type": "onsite", "outcome": "passed"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, interviewPath, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "passed", res["interview"].(map[string]interface{})["outcome"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, path, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["interviews"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, interviewPath, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, interviewPath, 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteCreateInterviewInvalidType(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, fmt.Sprintf("/api/applications/%d/interviews", applicationId), 1,
+		bytes.NewBufferString(`{"scheduledAt": "2022-07-01T10:00:00Z", "type": "letter"}`))
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteInterviewsNotOwner(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	path := fmt.Sprintf("/api/applications/%d/interviews", applicationId)
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, path, 2, bytes.NewBufferString(testInterviewBody))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, path, 2, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestRouteInterviewOfAnotherApplication(t *testing.T) {
+	s, store := newTestService()
+	firstId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	secondId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	_, res := serveTestRequest(t, s, http.MethodPost, fmt.Sprintf("/api/applications/%d/interviews", firstId), 1, bytes.NewBufferString(testInterviewBody))
+	interviewId := res["interview"].(map[string]interface{})["id"]
+
+	resp, _ := serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/interviews/%v", secondId, interviewId), 1, nil)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteDeleteApplicationNotOwner(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	resp, _ := serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/applications/%d", applicationId), 2, nil)
+
+	_, err := store.GetApplication(applicationId)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/notify"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// notificationPreferences is the representation of the preferences in the
+// API. Events tells for every event, whether it is enabled. Missing fields
+// are kept, when the preferences are updated.
+type notificationPreferences struct {
+	Email  *string         `json:"email"`
+	Events map[string]bool `json:"events"`
+}
+
+func newNotificationPreferences(preferences controller.NotificationPreferences) notificationPreferences {
+	events := map[string]bool{}
+	for _, event := range notify.Events {
+		events[event] = preferences.Enabled(event)
+	}
+
+	return notificationPreferences{Email: &preferences.Email, Events: events}
+}
+
+// getNotificationPreferences returns the preferences of the user. Users, who
+// did not save any, receive all notifications except emails.
+func (s ApplicationService) getNotificationPreferences(userId int) (controller.NotificationPreferences, error) {
+	preferences, err := s.NotificationController.GetNotificationPreferences(userId)
+	if errors.Is(err, controller.ErrNotFound) {
+		return controller.NotificationPreferences{UserId: userId}, nil
+	}
+
+	return preferences, err
+}
+
+// sendNotification delivers the notification to the email address of the
+// user, unless the user disabled its event.
+func (s ApplicationService) sendNotification(ctx context.Context, notification notify.Notification) error {
+	preferences, err := s.getNotificationPreferences(notification.UserId)
+	if err != nil {
+		return err
+	}
+
+	if !preferences.Enabled(notification.Event) {
+		return nil
+	}
+
+	notification.Email = preferences.Email
+	return s.Notifier.Notify(ctx, notification)
+}
+
+// notifyStatusChange notifies the owner of the application, if it entered
+// another status. The notification is delivered in the background, so the
+// request does not wait for the mail server.
+func (s ApplicationService) notifyStatusChange(application controller.Application, fromStatusId int) {
+	if application.StatusId == fromStatusId {
+		return
+	}
+
+	go func() {
+		message := "The status of your application changed"
+		from, fromErr := s.TypesController.GetStatus(fromStatusId)
+		to, toErr := s.TypesController.GetStatus(application.StatusId)
+		if fromErr == nil && toErr == nil {
+			message = fmt.Sprintf("Status changed from %s to %s", from.Name, to.Name)
+		}
+
+		err := s.sendNotification(context.Background(), notify.Notification{
+			Event:       notify.EventStatusChanged,
+			UserId:      application.UserId,
+			Application: application,
+			Message:     message,
+		})
+
+		if err != nil {
+			log.Printf("Could not notify user %d about the status change of application %d: %v", application.UserId, application.Id, err)
+		}
+	}()
+}
+
+func (s ApplicationService) handleGetNotificationPreferences(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	preferences, err := s.getNotificationPreferences(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched notification preferences", map[string]interface{}{
+		"preferences": newNotificationPreferences(preferences),
+	}))
+}
+
+func (s ApplicationService) handleUpdateNotificationPreferences(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	var request notificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	preferences, err := s.getNotificationPreferences(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	// An empty address turns off emails.
+	if request.Email != nil {
+		if *request.Email != "" {
+			address, err := mail.ParseAddress(*request.Email)
+			if err != nil || address.Address != *request.Email || len(address.Address) > 255 {
+				ApiResponse(w, "The email address is invalid", http.StatusBadRequest)
+				return
+			}
+		}
+
+		preferences.Email = *request.Email
+	}
+
+	enabled := map[string]bool{}
+	for _, event := range notify.Events {
+		enabled[event] = preferences.Enabled(event)
+	}
+
+	for event, on := range request.Events {
+		if _, ok := enabled[event]; !ok {
+			ApiResponse(w, fmt.Sprintf("The event %s does not exist", event), http.StatusBadRequest)
+			return
+		}
+
+		enabled[event] = on
+	}
+
+	preferences.DisabledEvents = []string{}
+	for _, event := range notify.Events {
+		if !enabled[event] {
+			preferences.DisabledEvents = append(preferences.DisabledEvents, event)
+		}
+	}
+
+	if err := s.NotificationController.SaveNotificationPreferences(preferences); err != nil {
+		ApiResponse(w, "Could not update notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Notification preferences updated", map[string]interface{}{
+		"preferences": newNotificationPreferences(preferences),
+	}))
+}
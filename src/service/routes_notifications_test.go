@@ -0,0 +1,68 @@
+package service
+
+import (
+	"bytes"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/notify"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteNotificationPreferences(t *testing.T) {
+	s, _ := newTestService()
+
+	resp, res := serveTestRequest(t, s, http.MethodGet, "/api/notifications/preferences", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	preferences := res["preferences"].(map[string]interface{})
+	assert.Equal(t, "", preferences["email"])
+	assert.Equal(t, map[string]interface{}{"reminder": true, "stale": true, "status_changed": true, "digest": true}, preferences["events"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, "/api/notifications/preferences", 1, bytes.NewBufferString(`{"email": "Jane <jane@example.com>"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, "/api/notifications/preferences", 1, bytes.NewBufferString(`{"events": {"birthday": true}}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, "/api/notifications/preferences", 1,
+		bytes.NewBufferString(`{"email": "jane@example.com", "events": {"digest": false}}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodPut, "/api/notifications/preferences", 1, bytes.NewBufferString(`{"events": {"stale": false}}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	preferences = res["preferences"].(map[string]interface{})
+	assert.Equal(t, "jane@example.com", preferences["email"])
+	assert.Equal(t, map[string]interface{}{"reminder": true, "stale": false, "status_changed": true, "digest": false}, preferences["events"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/notifications/preferences", 2, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "", res["preferences"].(map[string]interface{})["email"])
+}
+
+func TestStatusChangeNotification(t *testing.T) {
+	s, store := newTestService()
+	notifier := s.Notifier.(*notify.MemoryNotifier)
+	store.SaveNotificationPreferences(controller.NotificationPreferences{UserId: 1, Email: "jane@example.com"})
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Go Developer"})
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, fmt.Sprintf("/api/applications/%d/transitions", applicationId), 1, bytes.NewBufferString(`{"statusId": 1}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	assert.Eventually(t, func() bool { return len(notifier.Notifications()) == 1 }, time.Second, 10*time.Millisecond)
+	notification := notifier.Notifications()[0]
+	assert.Equal(t, notify.EventStatusChanged, notification.Event)
+	assert.Equal(t, "jane@example.com", notification.Email)
+	assert.Equal(t, "Status changed from Pending to Accepted", notification.Message)
+
+	// Users, who opted out, are not notified.
+	store.SaveNotificationPreferences(controller.NotificationPreferences{UserId: 1, Email: "jane@example.com", DisabledEvents: []string{notify.EventStatusChanged}})
+	body := fmt.Sprintf(`{"Id": %d, "WorkTypeId": 1, "StatusId": 2, "JobTitle": "Go Developer"}`, applicationId)
+	resp, _ = serveTestRequest(t, s, http.MethodPut, "/api/applications", 1, bytes.NewBufferString(body))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, len(notifier.Notifications()))
+}
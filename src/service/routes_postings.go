@@ -0,0 +1,221 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/posting"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// draftRequest is the body of a request to draft an application from the
+// job posting at the url.
+type draftRequest struct {
+	Url string `json:"url"`
+}
+
+// truncateText shortens the text to at most max bytes without splitting a
+// character, so it fits into its column.
+func truncateText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+
+	return text[:cut]
+}
+
+// formatAmount formats an amount without trailing zeros.
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// postingCommentary summarises the details of the posting, which have no
+// field in the application.
+func postingCommentary(details posting.Posting) string {
+	var lines []string
+	if details.Location != "" {
+		lines = append(lines, "Location: "+details.Location)
+	}
+
+	if details.Remote {
+		lines = append(lines, "Remote: yes")
+	}
+
+	if salary := details.Salary; salary != nil {
+		line := "Salary: " + formatAmount(salary.Min)
+		if salary.Max != salary.Min {
+			line += "-" + formatAmount(salary.Max)
+		}
+
+		if salary.Currency != "" {
+			line += " " + salary.Currency
+		}
+
+		if salary.Unit != "" {
+			line += " per " + strings.ToLower(salary.Unit)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return truncateText(strings.Join(lines, "\n"), 500)
+}
+
+// draftApplication pre-fills an application of the user with the details of
+// the posting. Remote jobs use the Remote work type, all others the default
+// work type of imported applications, and the status is the default status
+// of imported applications. A status of the user with the same name wins
+// over the global one.
+func (s ApplicationService) draftApplication(userId int, jobPostingUrl string, details posting.Posting) (controller.Application, error) {
+	workTypes, err := s.TypesController.GetWorkTypes()
+	if err != nil {
+		return controller.Application{}, err
+	}
+
+	statuses, err := s.TypesController.GetUsableStatuses(userId)
+	if err != nil {
+		return controller.Application{}, err
+	}
+
+	workType := defaultImportWorkType
+	if details.Remote {
+		workType = "Remote"
+	}
+
+	return controller.Application{
+		UserId:        userId,
+		JobTitle:      truncateText(details.Title, 255),
+		CompanyName:   truncateText(details.Company, 255),
+		WorkTypeId:    workTypeByName(workTypes, workType),
+		StatusId:      statusByName(statuses, defaultImportStatus),
+		Commentary:    postingCommentary(details),
+		JobPostingUrl: jobPostingUrl,
+	}, nil
+}
+
+// handleDraftApplication fetches the job posting at the url of the request
+// and responds with an application, which is pre-filled with the details of
+// the posting, but not created yet. The page is kept as a snapshot, so the
+// posting can be read after it was taken down. Snapshots count towards the
+// storage quota of the user.
+func (s ApplicationService) handleDraftApplication(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+
+	var request draftRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Url == "" {
+		ApiResponse(w, "The request must contain the url of the job posting", http.StatusBadRequest)
+		return
+	}
+
+	if !isJobPostingUrlValid(request.Url) {
+		ApiResponse(w, "The job posting url must be an absolute http or https url of up to 2000 characters", http.StatusBadRequest)
+		return
+	}
+
+	page, err := s.PostingFetcher.Fetch(r.Context(), request.Url)
+	if err != nil {
+		switch {
+		case errors.Is(err, posting.ErrForbiddenAddress):
+			ApiResponse(w, "The job posting must be hosted on a public address", http.StatusBadRequest)
+		case errors.Is(err, posting.ErrNotHTML):
+			ApiResponse(w, "The job posting url does not lead to an HTML page", http.StatusBadGateway)
+		case errors.Is(err, posting.ErrTooLarge):
+			ApiResponse(w, "The job posting is too large", http.StatusBadGateway)
+		default:
+			log.Printf("Could not fetch the job posting %s: %v", request.Url, err)
+			ApiResponse(w, "Could not fetch the job posting", http.StatusBadGateway)
+		}
+		return
+	}
+
+	usage, err := s.storageUsage(userId)
+	if err != nil {
+		ApiResponse(w, "Could not store the job posting", http.StatusInternalServerError)
+		return
+	}
+
+	if usage+int64(len(page.Content)) > s.Config.Documents.userQuota() {
+		ApiResponse(w, "The job posting exceeds your storage quota", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	snapshot := controller.PostingSnapshot{
+		UserId:      userId,
+		Url:         request.Url,
+		ContentType: truncateText(page.ContentType, 255),
+		Size:        int64(len(page.Content)),
+	}
+
+	if snapshot.BlobKey, err = newBlobKey("postings", userId); err != nil {
+		ApiResponse(w, "Could not store the job posting", http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.Blobs.Put(r.Context(), snapshot.BlobKey, bytes.NewReader(page.Content), snapshot.Size, snapshot.ContentType); err != nil {
+		ApiResponse(w, "Could not store the job posting", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := s.PostingController.InsertPostingSnapshot(snapshot)
+	if err != nil {
+		s.deleteBlobs(r.Context(), snapshot.BlobKey)
+		ApiResponse(w, "Could not store the job posting", http.StatusInternalServerError)
+		return
+	}
+
+	details := posting.Extract(page.Content)
+	application, err := s.draftApplication(userId, request.Url, details)
+	if err != nil {
+		ApiResponse(w, "Could not draft the application", http.StatusInternalServerError)
+		return
+	}
+
+	snapshot, _ = s.PostingController.GetPostingSnapshot(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Application drafted", map[string]interface{}{
+		"application": application,
+		"posting":     details,
+		"snapshot":    snapshot,
+	}))
+}
+
+// handleDownloadPosting downloads the latest snapshot of the job posting of
+// the application. The snapshot is sent as an attachment, so the scripts of
+// the posting do not run in the context of the service.
+func (s ApplicationService) handleDownloadPosting(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	if application.JobPostingUrl == "" {
+		ApiResponse(w, "This application has no job posting", http.StatusNotFound)
+		return
+	}
+
+	snapshots, err := s.PostingController.GetPostingSnapshots(application.UserId, application.JobPostingUrl)
+	if err != nil {
+		ApiResponse(w, "Could not fetch the job posting", http.StatusInternalServerError)
+		return
+	}
+
+	if len(snapshots) == 0 {
+		ApiResponse(w, "The job posting of this application was not fetched", http.StatusNotFound)
+		return
+	}
+
+	snapshot := snapshots[0]
+	s.serveBlob(w, r, snapshot.BlobKey, "posting.html", snapshot.ContentType, snapshot.Size)
+}
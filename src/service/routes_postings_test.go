@@ -0,0 +1,181 @@
+package service
+
+import (
+	"bytes"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/posting"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPostingPage = `<html><head>
+<meta property="og:title" content="Go Developer at ACME">
+<script type="application/ld+json">{
+	"@type": "JobPosting",
+	"title": "Go Developer",
+	"hiringOrganization": {"@type": "Organization", "name": "ACME"},
+	"jobLocation": {"@type": "Place", "address": {"addressLocality": "Berlin", "addressCountry": "DE"}},
+	"jobLocationType": "TELECOMMUTE",
+	"baseSalary": {"currency": "EUR", "value": {"minValue": 60000, "maxValue": 80000, "unitText": "YEAR"}}
+}</script>
+</head></html>`
+
+// newTestPostingService creates a service backed by an in-memory store,
+// which fetches job postings from private networks, so they can be served by
+// a local test server.
+func newTestPostingService(config DocumentConfig) (ApplicationService, *controller.MemoryStore) {
+	store := controller.NewMemoryStore()
+	repositories := MemoryRepositories(store)
+	repositories.Fetcher = posting.NewHTTPFetcher(true)
+
+	return NewServiceWithRepositories(ApplicationServiceConfig{
+		Jwt:       JwtConfig{SignKey: []byte("supersecretsignkey")},
+		Documents: config,
+	}, repositories), store
+}
+
+// newTestPostingServer serves the test posting and an OpenGraph only posting.
+func newTestPostingServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jobs/1":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, testPostingPage)
+		case "/jobs/2":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<meta property="og:title" content="Rust Developer at Initech">`)
+		case "/feed":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, "{}")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestRouteDraftApplication(t *testing.T) {
+	server := newTestPostingServer()
+	defer server.Close()
+
+	s, store := newTestPostingService(DocumentConfig{})
+
+	url := server.URL + "/jobs/1"
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/applications/from-url", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"url": %q}`, url)))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	application := res["application"].(map[string]interface{})
+	assert.Equal(t, "Go Developer", application["JobTitle"])
+	assert.Equal(t, "ACME", application["CompanyName"])
+	assert.Equal(t, url, application["jobPostingUrl"])
+	assert.Equal(t, float64(1), application["WorkTypeId"])
+	assert.Equal(t, float64(2), application["StatusId"])
+	assert.Equal(t, "Location: Berlin, DE\nRemote: yes\nSalary: 60000-80000 EUR per year", application["Commentary"])
+	assert.Equal(t, "Berlin, DE", res["posting"].(map[string]interface{})["location"])
+
+	// The draft is not created, but the snapshot is kept.
+	applications, _ := store.GetApplications(1)
+	assert.Empty(t, applications)
+
+	snapshots, _ := store.GetPostingSnapshots(1, url)
+	assert.Equal(t, 1, len(snapshots))
+	assert.Equal(t, float64(snapshots[0].Id), res["snapshot"].(map[string]interface{})["id"])
+
+	resp, res = serveTestRequest(t, s, http.MethodPost, "/api/applications/from-url", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"url": %q}`, server.URL+"/jobs/2")))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	application = res["application"].(map[string]interface{})
+	assert.Equal(t, "Rust Developer", application["JobTitle"])
+	assert.Equal(t, "Initech", application["CompanyName"])
+	assert.Equal(t, float64(2), application["WorkTypeId"])
+	assert.Equal(t, "", application["Commentary"])
+
+	// Users with own statuses still get the global default status.
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, bytes.NewBufferString(`{"name": "Applied"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodPost, "/api/applications/from-url", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"url": %q}`, url)))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(2), res["application"].(map[string]interface{})["StatusId"])
+}
+
+func TestRouteDraftApplicationErrors(t *testing.T) {
+	server := newTestPostingServer()
+	defer server.Close()
+
+	s, store := newTestPostingService(DocumentConfig{})
+
+	for _, test := range []struct {
+		body string
+		code int
+	}{
+		{`{}`, http.StatusBadRequest},
+		{`{"url": "jobs.example.com/1"}`, http.StatusBadRequest},
+		{fmt.Sprintf(`{"url": %q}`, server.URL+"/feed"), http.StatusBadGateway},
+		{fmt.Sprintf(`{"url": %q}`, server.URL+"/missing"), http.StatusBadGateway},
+		{fmt.Sprintf(`{"url": %q}`, server.URL+"/jobs/1"), http.StatusOK},
+	} {
+		resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/applications/from-url", 1, bytes.NewBufferString(test.body))
+		assert.Equal(t, test.code, resp.Code, test.body)
+	}
+
+	usage, _ := store.GetPostingSnapshotUsage(1)
+	assert.Equal(t, int64(len(testPostingPage)), usage)
+
+	// Postings on private networks are refused by default.
+	s, _ = newTestService()
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/applications/from-url", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"url": %q}`, server.URL+"/jobs/1")))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteDraftApplicationQuota(t *testing.T) {
+	server := newTestPostingServer()
+	defer server.Close()
+
+	// Snapshots count towards the storage quota.
+	s, store := newTestPostingService(DocumentConfig{UserQuota: int64(len(testPostingPage)) + 1})
+	body := fmt.Sprintf(`{"url": %q}`, server.URL+"/jobs/1")
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/applications/from-url", 1, bytes.NewBufferString(body))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/applications/from-url", 1, bytes.NewBufferString(body))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	assert.Contains(t, res["message"], "quota")
+
+	snapshots, _ := store.GetPostingSnapshots(1, server.URL+"/jobs/1")
+	assert.Equal(t, 1, len(snapshots))
+}
+
+func TestRouteDownloadPosting(t *testing.T) {
+	server := newTestPostingServer()
+	defer server.Close()
+
+	s, store := newTestPostingService(DocumentConfig{})
+
+	url := server.URL + "/jobs/1"
+	serveTestRequest(t, s, http.MethodPost, "/api/applications/from-url", 1, bytes.NewBufferString(fmt.Sprintf(`{"url": %q}`, url)))
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, JobPostingUrl: url})
+	otherId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	// The snapshot survives after the posting was taken down.
+	server.Close()
+
+	resp, _ := serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/posting", applicationId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, testPostingPage, resp.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Header().Get("Content-Disposition"), "attachment")
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/posting", otherId), 1, nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/posting", applicationId), 2, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
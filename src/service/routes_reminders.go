@@ -0,0 +1,111 @@
+package service
+
+import (
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// reminderRequest creates a reminder at a fixed time or a number of days
+// from now, e.g. to follow up if there is no reply within 7 days.
+type reminderRequest struct {
+	RemindAt time.Time `json:"remindAt"`
+	InDays   int       `json:"inDays"`
+	Message  string    `json:"message"`
+}
+
+func (s ApplicationService) handleGetReminders(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	reminders, err := s.ReminderController.GetReminders(application.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch reminders", http.StatusInternalServerError)
+		return
+	}
+
+	if reminders == nil {
+		reminders = []controller.Reminder{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched reminders", map[string]interface{}{
+		"reminders": reminders,
+	}))
+}
+
+func (s ApplicationService) handleCreateReminder(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	var request reminderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if request.RemindAt.IsZero() == (request.InDays == 0) {
+		ApiResponse(w, "Either remindAt or inDays must be given", http.StatusBadRequest)
+		return
+	}
+
+	if request.InDays < 0 {
+		ApiResponse(w, "inDays must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	if len(request.Message) > 500 {
+		ApiResponse(w, "The message must not be longer than 500 characters", http.StatusBadRequest)
+		return
+	}
+
+	reminder := controller.Reminder{ApplicationId: application.Id, RemindAt: request.RemindAt, Message: request.Message}
+	if request.InDays > 0 {
+		reminder.RemindAt = time.Now().AddDate(0, 0, request.InDays)
+	}
+
+	id, err := s.ReminderController.InsertReminder(reminder)
+	if err != nil {
+		ApiResponse(w, "Could not create reminder", http.StatusInternalServerError)
+		return
+	}
+
+	newReminder, _ := s.ReminderController.GetReminder(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Reminder created", map[string]interface{}{
+		"reminder": newReminder,
+	}))
+}
+
+func (s ApplicationService) handleDeleteReminder(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	reminderId, err := strconv.Atoi(p.ByName("reminderId"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the reminder id", http.StatusBadRequest)
+		return
+	}
+
+	reminder, err := s.ReminderController.GetReminder(reminderId)
+	if err != nil || reminder.ApplicationId != application.Id {
+		ApiResponse(w, "This reminder does not exist", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.ReminderController.DeleteReminder(reminder.Id); err != nil {
+		ApiResponse(w, "Could not delete reminder", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Reminder deleted", http.StatusOK)
+}
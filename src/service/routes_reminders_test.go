@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/notify"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteReminders(t *testing.T) {
+	s, store := newTestService()
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	path := fmt.Sprintf("/api/applications/%d/reminders", applicationId)
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, path, 1, bytes.NewBufferString(`{"message": "Follow up"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, path, 2, bytes.NewBufferString(`{"inDays": 7}`))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, path, 1, bytes.NewBufferString(`{"inDays": 7, "message": "Follow up"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	reminder := res["reminder"].(map[string]interface{})
+	remindAt, _ := time.Parse(time.RFC3339Nano, reminder["remindAt"].(string))
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), remindAt, time.Minute)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, path, 1, bytes.NewBufferString(`{"remindAt": "2022-07-01T09:00:00Z"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, path, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 2, len(res["reminders"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("%s/%v", path, reminder["id"]), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("%s/%v", path, reminder["id"]), 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestFireReminders(t *testing.T) {
+	store := controller.NewMemoryStore()
+	repositories := MemoryRepositories(store)
+	notifier := repositories.Notifier.(*notify.MemoryNotifier)
+	s := NewServiceWithRepositories(ApplicationServiceConfig{Scheduler: SchedulerConfig{BatchSize: 2}}, repositories)
+
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer"})
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		store.InsertReminder(controller.Reminder{ApplicationId: applicationId, RemindAt: now, Message: "Follow up"})
+	}
+	store.InsertReminder(controller.Reminder{ApplicationId: applicationId, RemindAt: now.Add(time.Hour)})
+
+	fired, err := s.FireReminders(context.Background(), now)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, fired)
+
+	notifications := notifier.Notifications()
+	assert.Equal(t, 5, len(notifications))
+	assert.Equal(t, notify.EventReminder, notifications[0].Event)
+	assert.Equal(t, 1, notifications[0].UserId)
+	assert.Equal(t, "Go Developer", notifications[0].Application.JobTitle)
+
+	fired, _ = s.FireReminders(context.Background(), now)
+	assert.Equal(t, 0, fired)
+}
+
+func TestRunScheduler(t *testing.T) {
+	store := controller.NewMemoryStore()
+	repositories := MemoryRepositories(store)
+	notifier := repositories.Notifier.(*notify.MemoryNotifier)
+	s := NewServiceWithRepositories(ApplicationServiceConfig{Scheduler: SchedulerConfig{Interval: 10 * time.Millisecond}}, repositories)
+
+	applicationId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	store.InsertReminder(controller.Reminder{ApplicationId: applicationId, RemindAt: time.Now().Add(30 * time.Millisecond)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.RunScheduler(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(notifier.Notifications()) == 1 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+	assert.Equal(t, 1, len(notifier.Notifications()))
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func validateStatus(status controller.ApplicationStatus) error {
+	if strings.TrimSpace(status.Name) == "" || len(status.Name) > 255 {
+		return errors.New("the name must contain 1 to 255 characters")
+	}
+
+	if status.Color != "" && !colorPattern.MatchString(status.Color) {
+		return errors.New("the color must look like #1a2b3c")
+	}
+
+	if status.StaleAfterDays != nil && (*status.StaleAfterDays <= 0 || status.Terminal) {
+		return errors.New("only non-terminal statuses can have a positive stale threshold")
+	}
+
+	if status.StaleStatusId != nil && (status.StaleAfterDays == nil || *status.StaleStatusId == status.Id) {
+		return errors.New("the stale status must be another status and requires a stale threshold")
+	}
+
+	return nil
+}
+
+// isStaleStatusUsable checks, that the status, to which stale applications
+// are moved, exists and can be used by the user.
+func (s ApplicationService) isStaleStatusUsable(status controller.ApplicationStatus, userId int) bool {
+	if status.StaleStatusId == nil {
+		return true
+	}
+
+	staleStatus, err := s.TypesController.GetStatus(*status.StaleStatusId)
+	return err == nil && (staleStatus.UserId == nil || *staleStatus.UserId == userId)
+}
+
+// isStatusUsable checks, that the status is either a global default or
+// defined by the user. Unknown statuses are left to the repository.
+func (s ApplicationService) isStatusUsable(statusId int, userId int) bool {
+	status, err := s.TypesController.GetStatus(statusId)
+	if err != nil {
+		return true
+	}
+
+	return status.UserId == nil || *status.UserId == userId
+}
+
+// ownedStatus fetches the status referenced by the id parameter and makes
+// sure, it belongs to the requesting user. Otherwise an error response is
+// written and false is returned.
+func (s ApplicationService) ownedStatus(w http.ResponseWriter, p httprouter.Params) (controller.ApplicationStatus, bool) {
+	statusId, err := strconv.Atoi(p.ByName("id"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the status id", http.StatusBadRequest)
+		return controller.ApplicationStatus{}, false
+	}
+
+	status, err := s.TypesController.GetStatus(statusId)
+	if err != nil {
+		ApiResponse(w, "This status does not exist", http.StatusBadRequest)
+		return controller.ApplicationStatus{}, false
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if status.UserId == nil || *status.UserId != userId {
+		ApiResponse(w, "You are not allowed to modify this status", http.StatusUnauthorized)
+		return controller.ApplicationStatus{}, false
+	}
+
+	return status, true
+}
+
+func (s ApplicationService) handleGetUserStatuses(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	statuses, err := s.TypesController.GetUserStatuses(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch application statuses", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched application statuses", map[string]interface{}{
+		"statuses": statuses,
+	}))
+}
+
+func (s ApplicationService) handleCreateStatus(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	var status controller.ApplicationStatus
+	if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateStatus(status); err != nil {
+		ApiResponse(w, "Invalid status: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	status.UserId = &userId
+
+	if !s.isStaleStatusUsable(status, userId) {
+		ApiResponse(w, "The stale status does not exist", http.StatusBadRequest)
+		return
+	}
+
+	id, err := s.TypesController.InsertStatus(status)
+	if err != nil {
+		ApiResponse(w, "Could not create status", http.StatusInternalServerError)
+		return
+	}
+
+	newStatus, _ := s.TypesController.GetStatus(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Status created", map[string]interface{}{
+		"status": newStatus,
+	}))
+}
+
+func (s ApplicationService) handleUpdateStatus(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	status, ok := s.ownedStatus(w, p)
+	if !ok {
+		return
+	}
+
+	var statusRequest controller.ApplicationStatus
+	if err := json.NewDecoder(r.Body).Decode(&statusRequest); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	statusRequest.Id = status.Id
+	if err := validateStatus(statusRequest); err != nil {
+		ApiResponse(w, "Invalid status: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !s.isStaleStatusUsable(statusRequest, *status.UserId) {
+		ApiResponse(w, "The stale status does not exist", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.TypesController.UpdateStatus(statusRequest); err != nil {
+		ApiResponse(w, "Could not update status", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Status updated", http.StatusOK)
+}
+
+func (s ApplicationService) handleDeleteStatus(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	status, ok := s.ownedStatus(w, p)
+	if !ok {
+		return
+	}
+
+	if err := s.TypesController.DeleteStatus(status.Id); err != nil {
+		if errors.Is(err, controller.ErrInUse) {
+			ApiResponse(w, "The status is still used by applications", http.StatusConflict)
+			return
+		}
+
+		ApiResponse(w, "Could not delete status", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Status deleted", http.StatusOK)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteCreateStatus(t *testing.T) {
+	s, _ := newTestService()
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, bytes.NewBufferString(`{"name": "Phone Screen", "position": 2, "color": "#336699"}`))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "Phone Screen", res["status"].(map[string]interface{})["name"])
+	assert.Equal(t, 1.0, res["status"].(map[string]interface{})["userId"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/statuses", 1, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["statuses"].([]interface{})))
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/statuses", 2, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 3, len(res["statuses"].([]interface{})))
+}
+
+func TestRouteCreateStatusInvalid(t *testing.T) {
+	s, _ := newTestService()
+
+	for _, body := range []string{`{"name": ""}`, `{"name": "Offer", "color": "red"}`, `{ name`} {
+		resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, bytes.NewBufferString(body))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, body)
+	}
+}
+
+func TestRouteUpdateStatus(t *testing.T) {
+	s, store := newTestService()
+	userId := 1
+	id, _ := store.InsertStatus(controller.ApplicationStatus{Name: "Applied", UserId: &userId})
+
+	resp, _ := serveTestRequest(t, s, http.MethodPut, fmt.Sprintf("/api/statuses/%d", id), 1, bytes.NewBufferString(`{"name": "Submitted", "terminal": true}`))
+	status, _ := store.GetStatus(id)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "Submitted", status.Name)
+	assert.True(t, status.Terminal)
+}
+
+func TestRouteUpdateStatusNotOwner(t *testing.T) {
+	s, store := newTestService()
+	userId := 2
+	id, _ := store.InsertStatus(controller.ApplicationStatus{Name: "Applied", UserId: &userId})
+
+	resp, _ := serveTestRequest(t, s, http.MethodPut, fmt.Sprintf("/api/statuses/%d", id), 1, bytes.NewBufferString(`{"name": "Submitted"}`))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	// The global defaults belong to nobody.
+	resp, _ = serveTestRequest(t, s, http.MethodPut, "/api/statuses/1", 1, bytes.NewBufferString(`{"name": "Submitted"}`))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestRouteDeleteStatusInUse(t *testing.T) {
+	s, store := newTestService()
+	userId := 1
+	id, _ := store.InsertStatus(controller.ApplicationStatus{Name: "Applied", UserId: &userId})
+	store.InsertApplication(controller.Application{UserId: userId, WorkTypeId: 1, StatusId: id})
+
+	resp, _ := serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/statuses/%d", id), 1, nil)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+func TestRouteCreateApplicationForeignStatus(t *testing.T) {
+	s, store := newTestService()
+	userId := 2
+	id, _ := store.InsertStatus(controller.ApplicationStatus{Name: "Applied", UserId: &userId})
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/applications", 1, bytes.NewBufferString(fmt.Sprintf(`{"WorkTypeId": 1, "StatusId": %d}`, id)))
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteStatusStaleThreshold(t *testing.T) {
+	s, store := newTestService()
+	otherUserId := 2
+	foreignId, _ := store.InsertStatus(controller.ApplicationStatus{Name: "Foreign", UserId: &otherUserId})
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, bytes.NewBufferString(`{"name": "Done", "terminal": true, "staleAfterDays": 10}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, bytes.NewBufferString(`{"name": "Waiting", "staleStatusId": 3}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"name": "Waiting", "staleAfterDays": 10, "staleStatusId": %d}`, foreignId)))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/statuses", 1, bytes.NewBufferString(`{"name": "Waiting", "staleAfterDays": 10, "staleStatusId": 3}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(10), res["status"].(map[string]interface{})["staleAfterDays"])
+}
+
+func TestRouteGetStaleApplications(t *testing.T) {
+	s, store := newTestService()
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, SubmissionDate: time.Now().AddDate(0, 0, -40)})
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, SubmissionDate: time.Now().AddDate(0, 0, -3)})
+	store.MarkStaleApplications(time.Now())
+
+	resp, res := serveTestRequest(t, s, http.MethodGet, "/api/applications?stale=true", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(1), res["total"])
+
+	application := res["applications"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, true, application["stale"])
+	assert.Equal(t, float64(40), application["daysInStatus"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications?stale=maybe", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
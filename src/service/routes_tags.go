@@ -0,0 +1,249 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxBulkSize limits the number of applications tagged by a single request.
+const maxBulkSize = 500
+
+func validateTag(tag controller.Tag) error {
+	name := strings.TrimSpace(tag.Name)
+	if name == "" || len(tag.Name) > 64 {
+		return fmt.Errorf("The name of a tag must contain 1 to 64 characters")
+	}
+
+	// Tags are filtered by comma separated names.
+	if strings.Contains(name, ",") {
+		return fmt.Errorf("The name of a tag must not contain commas")
+	}
+
+	if tag.Color != "" && !colorPattern.MatchString(tag.Color) {
+		return fmt.Errorf("The color must be a hex color like #1a2b3c")
+	}
+
+	return nil
+}
+
+// ownedTag fetches the tag referenced by the id parameter and makes sure, it
+// belongs to the requesting user. Otherwise an error response is written and
+// false is returned.
+func (s ApplicationService) ownedTag(w http.ResponseWriter, p httprouter.Params) (controller.Tag, bool) {
+	tagId, err := strconv.Atoi(p.ByName("id"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the tag id", http.StatusBadRequest)
+		return controller.Tag{}, false
+	}
+
+	tag, err := s.TagController.GetTag(tagId)
+	if err != nil {
+		ApiResponse(w, "This tag does not exist", http.StatusBadRequest)
+		return controller.Tag{}, false
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if tag.UserId != userId {
+		ApiResponse(w, "You are not allowed to access this tag", http.StatusUnauthorized)
+		return controller.Tag{}, false
+	}
+
+	return tag, true
+}
+
+func (s ApplicationService) handleGetTags(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	tags, err := s.TagController.GetTags(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch tags", http.StatusInternalServerError)
+		return
+	}
+
+	if tags == nil {
+		tags = []controller.Tag{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched tags", map[string]interface{}{
+		"tags": tags,
+	}))
+}
+
+func (s ApplicationService) handleCreateTag(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	var tag controller.Tag
+	if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateTag(tag); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag.Name = strings.TrimSpace(tag.Name)
+	tag.UserId, _ = strconv.Atoi(p.ByName("userId"))
+
+	id, err := s.TagController.InsertTag(tag)
+	if err != nil {
+		if errors.Is(err, controller.ErrAlreadyExists) {
+			ApiResponse(w, "A tag with this name already exists", http.StatusConflict)
+			return
+		}
+
+		ApiResponse(w, "Could not create tag", http.StatusInternalServerError)
+		return
+	}
+
+	newTag, _ := s.TagController.GetTag(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Tag created", map[string]interface{}{
+		"tag": newTag,
+	}))
+}
+
+func (s ApplicationService) handleUpdateTag(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	tag, ok := s.ownedTag(w, p)
+	if !ok {
+		return
+	}
+
+	var tagRequest controller.Tag
+	if err := json.NewDecoder(r.Body).Decode(&tagRequest); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateTag(tagRequest); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tagRequest.Id = tag.Id
+	tagRequest.UserId = tag.UserId
+	tagRequest.Name = strings.TrimSpace(tagRequest.Name)
+
+	if err := s.TagController.UpdateTag(tagRequest); err != nil {
+		if errors.Is(err, controller.ErrAlreadyExists) {
+			ApiResponse(w, "A tag with this name already exists", http.StatusConflict)
+			return
+		}
+
+		ApiResponse(w, "Could not update tag", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Tag updated", http.StatusOK)
+}
+
+func (s ApplicationService) handleDeleteTag(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	tag, ok := s.ownedTag(w, p)
+	if !ok {
+		return
+	}
+
+	if err := s.TagController.DeleteTag(tag.Id); err != nil {
+		ApiResponse(w, "Could not delete tag", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Tag deleted", http.StatusOK)
+}
+
+// readBulkApplications reads the ids of the applications of a bulk request
+// and makes sure, that all of them belong to the requesting user.
+func (s ApplicationService) readBulkApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) ([]int, bool) {
+	var request struct {
+		ApplicationIds []int `json:"applicationIds"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if len(request.ApplicationIds) == 0 || len(request.ApplicationIds) > maxBulkSize {
+		ApiResponse(w, fmt.Sprintf("applicationIds must contain 1 to %d ids", maxBulkSize), http.StatusBadRequest)
+		return nil, false
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	for _, applicationId := range request.ApplicationIds {
+		application, err := s.ApplicationController.GetApplication(applicationId)
+		if err != nil {
+			ApiResponse(w, fmt.Sprintf("The application %d does not exist", applicationId), http.StatusBadRequest)
+			return nil, false
+		}
+
+		if application.UserId != userId {
+			ApiResponse(w, "You are not allowed to tag applications of another user", http.StatusUnauthorized)
+			return nil, false
+		}
+	}
+
+	return request.ApplicationIds, true
+}
+
+func (s ApplicationService) handleTagApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	tag, ok := s.ownedTag(w, p)
+	if !ok {
+		return
+	}
+
+	applicationIds, ok := s.readBulkApplications(w, r, p)
+	if !ok {
+		return
+	}
+
+	if err := s.TagController.TagApplications(tag.Id, applicationIds); err != nil {
+		ApiResponse(w, "Could not tag applications", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Applications tagged", http.StatusOK)
+}
+
+func (s ApplicationService) handleUntagApplications(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	tag, ok := s.ownedTag(w, p)
+	if !ok {
+		return
+	}
+
+	applicationIds, ok := s.readBulkApplications(w, r, p)
+	if !ok {
+		return
+	}
+
+	if err := s.TagController.UntagApplications(tag.Id, applicationIds); err != nil {
+		ApiResponse(w, "Could not untag applications", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Applications untagged", http.StatusOK)
+}
+
+func (s ApplicationService) handleGetApplicationTags(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	tags, err := s.TagController.GetApplicationTags(application.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch tags", http.StatusInternalServerError)
+		return
+	}
+
+	if tags == nil {
+		tags = []controller.Tag{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched tags", map[string]interface{}{
+		"tags": tags,
+	}))
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteTags(t *testing.T) {
+	s, store := newTestService()
+	ownId, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	otherId, _ := store.InsertApplication(controller.Application{UserId: 2, WorkTypeId: 1, StatusId: 1})
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/tags", 1, bytes.NewBufferString(`{"name": "Go", "color": "#00add8"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	tagId := res["tag"].(map[string]interface{})["id"]
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/tags", 1, bytes.NewBufferString(`{"name": "go"}`))
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/tags", 1, bytes.NewBufferString(`{"name": "a,b"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, fmt.Sprintf("/api/tags/%v", tagId), 1, bytes.NewBufferString(`{"name": "Golang", "color": "blue"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, fmt.Sprintf("/api/tags/%v", tagId), 2, bytes.NewBufferString(`{"name": "Golang"}`))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, fmt.Sprintf("/api/tags/%v/applications", tagId), 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"applicationIds": [%d, %d]}`, ownId, otherId)))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, fmt.Sprintf("/api/tags/%v/applications", tagId), 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"applicationIds": [%d]}`, ownId)))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/tags", ownId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["tags"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/tags/%v/applications", tagId), 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"applicationIds": [%d]}`, ownId)))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/tags", ownId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 0, len(res["tags"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, fmt.Sprintf("/api/tags/%v", tagId), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestRouteGetApplicationsByTags(t *testing.T) {
+	s, store := newTestService()
+	goId, _ := store.InsertTag(controller.Tag{UserId: 1, Name: "Go"})
+	berlinId, _ := store.InsertTag(controller.Tag{UserId: 1, Name: "Berlin"})
+
+	for i := 0; i < 3; i++ {
+		store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 1})
+	}
+
+	store.TagApplications(goId, []int{1, 2})
+	store.TagApplications(berlinId, []int{2})
+
+	resp, res := serveTestRequest(t, s, http.MethodGet, "/api/applications?tags=go,berlin", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(2), res["total"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/applications?tags=go,berlin&tagMatch=all", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(1), res["total"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications?tags=go&tagMatch=some", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flhansen/application-manager/application-service/src/auth"
 	"flhansen/application-manager/application-service/src/controller"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,27 +19,63 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestRouteGetApplications(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+// failingTypesRepository simulates a types repository, which lost its
+// database connection.
+type failingTypesRepository struct {
+	controller.TypesRepository
+}
+
+func (failingTypesRepository) GetWorkTypes() ([]controller.WorkType, error) {
+	return nil, errors.New("connection lost")
+}
+
+func (failingTypesRepository) GetStatuses() ([]controller.ApplicationStatus, error) {
+	return nil, errors.New("connection lost")
+}
+
+// newTestService creates a service backed by an in-memory store.
+func newTestService() (ApplicationService, *controller.MemoryStore) {
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
+	}, MemoryRepositories(store))
+
+	return s, store
+}
+
+// serveTestRequest sends a request authenticated as the given user directly
+// to the router of the service and decodes the JSON response.
+func serveTestRequest(t *testing.T, s ApplicationService, method string, path string, userId int, body io.Reader) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, body)
 
+	token, err := auth.GenerateToken(userId, "testuser", jwt.SigningMethodHS256, s.Config.Jwt.SignKey)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	s.ApplicationController.CreateScheme()
+	req.Header.Add("Authorization", token)
+	recorder := httptest.NewRecorder()
+	s.Router.ServeHTTP(recorder, req)
+
+	var res map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &res)
+
+	return recorder, res
+}
+
+func TestRouteGetApplications(t *testing.T) {
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
+		Host: "localhost",
+		Port: 8000,
+		Jwt: JwtConfig{
+			SignKey: []byte("supersecretsignkey"),
+		},
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
@@ -76,26 +116,14 @@ func TestRouteGetApplications(t *testing.T) {
 }
 
 func TestRouteGetApplicationInvalidId(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	s.ApplicationController.CreateScheme()
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
@@ -137,26 +165,14 @@ func TestRouteGetApplicationInvalidId(t *testing.T) {
 }
 
 func TestRouteGetApplicationNotFound(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	s.ApplicationController.CreateScheme()
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
@@ -198,26 +214,15 @@ func TestRouteGetApplicationNotFound(t *testing.T) {
 }
 
 func TestRouteGetApplicationNotAuthorized(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
-	s.ApplicationController.CreateScheme()
 	s.ApplicationController.InsertApplication(controller.Application{
 		UserId:     2,
 		WorkTypeId: 1,
@@ -264,26 +269,15 @@ func TestRouteGetApplicationNotAuthorized(t *testing.T) {
 }
 
 func TestRouteGetApplication(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
-	s.ApplicationController.CreateScheme()
 	s.ApplicationController.InsertApplication(controller.Application{
 		UserId:     1,
 		WorkTypeId: 1,
@@ -331,26 +325,14 @@ func TestRouteGetApplication(t *testing.T) {
 }
 
 func TestRouteCreateApplicationRequestBodyError(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	s.ApplicationController.CreateScheme()
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
@@ -395,26 +377,14 @@ func TestRouteCreateApplicationRequestBodyError(t *testing.T) {
 }
 
 func TestRouteCreateApplicationInsertionError(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	s.ApplicationController.CreateScheme()
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
@@ -465,26 +435,14 @@ func TestRouteCreateApplicationInsertionError(t *testing.T) {
 }
 
 func TestRouteCreateApplication(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	s.ApplicationController.CreateScheme()
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
@@ -538,29 +496,18 @@ func TestRouteCreateApplication(t *testing.T) {
 }
 
 func TestRouteDeleteApplication(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.ApplicationController.CreateScheme()
 	id, err := s.ApplicationController.InsertApplication(controller.Application{
 		UserId:     1,
 		WorkTypeId: 1,
@@ -612,30 +559,19 @@ func TestRouteDeleteApplication(t *testing.T) {
 }
 
 func TestRouteDeleteApplicationRequestError(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.ApplicationController.CreateScheme()
-	_, err = s.ApplicationController.InsertApplication(controller.Application{
+	_, err := s.ApplicationController.InsertApplication(controller.Application{
 		UserId:     1,
 		WorkTypeId: 1,
 		StatusId:   1,
@@ -684,29 +620,18 @@ func TestRouteDeleteApplicationRequestError(t *testing.T) {
 }
 
 func TestRouteUpdateApplication(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.ApplicationController.CreateScheme()
 	id, err := s.ApplicationController.InsertApplication(controller.Application{
 		UserId:     1,
 		WorkTypeId: 1,
@@ -765,30 +690,19 @@ func TestRouteUpdateApplication(t *testing.T) {
 }
 
 func TestRouteUpdateApplicationInvalidRequestBody(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.ApplicationController.CreateScheme()
-	_, err = s.ApplicationController.InsertApplication(controller.Application{
+	_, err := s.ApplicationController.InsertApplication(controller.Application{
 		UserId:     1,
 		WorkTypeId: 1,
 		StatusId:   1,
@@ -839,29 +753,18 @@ func TestRouteUpdateApplicationInvalidRequestBody(t *testing.T) {
 }
 
 func TestRouteUpdateApplicationDoesNotExist(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.ApplicationController.CreateScheme()
 	id, err := s.ApplicationController.InsertApplication(controller.Application{
 		UserId:     1,
 		WorkTypeId: 1,
@@ -921,29 +824,18 @@ func TestRouteUpdateApplicationDoesNotExist(t *testing.T) {
 }
 
 func TestRouteUpdateApplicationUnauthorized(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.ApplicationController.CreateScheme()
 	id, err := s.ApplicationController.InsertApplication(controller.Application{
 		UserId:     2,
 		WorkTypeId: 1,
@@ -1001,35 +893,41 @@ func TestRouteUpdateApplicationUnauthorized(t *testing.T) {
 	}
 }
 
+func TestRouteApplicationJobPostingUrl(t *testing.T) {
+	s, store := newTestService()
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+		bytes.NewBufferString(`{"WorkTypeId": 1, "StatusId": 2, "jobPostingUrl": "https://jobs.example.com/1"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "https://jobs.example.com/1", res["application"].(map[string]interface{})["jobPostingUrl"])
+
+	for _, jobPostingUrl := range []string{"jobs.example.com/1", "ftp://jobs.example.com/1", "https://" + strings.Repeat("a", 2000)} {
+		resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/applications", 1,
+			bytes.NewBufferString(fmt.Sprintf(`{"WorkTypeId": 1, "StatusId": 2, "jobPostingUrl": %q}`, jobPostingUrl)))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		resp, _ = serveTestRequest(t, s, http.MethodPut, "/api/applications", 1,
+			bytes.NewBufferString(fmt.Sprintf(`{"Id": 1, "WorkTypeId": 1, "StatusId": 2, "jobPostingUrl": %q}`, jobPostingUrl)))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	}
+
+	application, _ := store.GetApplication(1)
+	assert.Equal(t, "https://jobs.example.com/1", application.JobPostingUrl)
+}
+
 func TestRouteGetWorkTypes(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.TypesController.CreateScheme()
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
 	done := make(chan error)
 	go func() {
 		done <- srv.ListenAndServe()
@@ -1059,34 +957,18 @@ func TestRouteGetWorkTypes(t *testing.T) {
 }
 
 func TestRouteGetWorkTypesError(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, Repositories{Applications: store, Types: failingTypesRepository{}})
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.TypesController.CreateScheme()
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
 	done := make(chan error)
 	go func() {
 		done <- srv.ListenAndServe()
@@ -1094,10 +976,6 @@ func TestRouteGetWorkTypesError(t *testing.T) {
 
 	select {
 	case <-time.After(200 * time.Millisecond):
-		if _, err := s.TypesController.Database.Exec(s.TypesController.Context, "DROP TABLE work_type CASCADE"); err != nil {
-			t.Fatal(err)
-		}
-
 		resp, err := http.Get(fmt.Sprintf("http://%s:%d/api/types/worktypes", s.Config.Host, s.Config.Port))
 		if err != nil {
 			t.Fatal(err)
@@ -1118,34 +996,18 @@ func TestRouteGetWorkTypesError(t *testing.T) {
 }
 
 func TestRouteGetStatuses(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, MemoryRepositories(store))
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.TypesController.CreateScheme()
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
 	done := make(chan error)
 	go func() {
 		done <- srv.ListenAndServe()
@@ -1175,34 +1037,18 @@ func TestRouteGetStatuses(t *testing.T) {
 }
 
 func TestRouteGetStatusesError(t *testing.T) {
-	s, err := NewService(ApplicationServiceConfig{
+	store := controller.NewMemoryStore()
+	s := NewServiceWithRepositories(ApplicationServiceConfig{
 		Host: "localhost",
 		Port: 8000,
 		Jwt: JwtConfig{
 			SignKey: []byte("supersecretsignkey"),
 		},
-		Database: controller.DbConfig{
-			Host:     "localhost",
-			Port:     5432,
-			Username: "test",
-			Password: "test",
-			Database: "test",
-		},
-	})
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	}, Repositories{Applications: store, Types: failingTypesRepository{}})
 
 	srv := &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: s.Router}
 	defer srv.Shutdown(context.Background())
 
-	s.TypesController.CreateScheme()
-
-	if err != nil {
-		t.Fatal(err)
-	}
-
 	done := make(chan error)
 	go func() {
 		done <- srv.ListenAndServe()
@@ -1210,10 +1056,6 @@ func TestRouteGetStatusesError(t *testing.T) {
 
 	select {
 	case <-time.After(200 * time.Millisecond):
-		if _, err := s.TypesController.Database.Exec(s.TypesController.Context, "DROP TABLE application_status CASCADE"); err != nil {
-			t.Fatal(err)
-		}
-
 		resp, err := http.Get(fmt.Sprintf("http://%s:%d/api/types/statuses", s.Config.Host, s.Config.Port))
 		if err != nil {
 			t.Fatal(err)
@@ -1232,3 +1074,80 @@ func TestRouteGetStatusesError(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRouteGetApplicationsPaginated(t *testing.T) {
+	s, store := newTestService()
+	for _, salary := range []float32{3000, 5000, 4000} {
+		store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, WantedSalary: salary})
+	}
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 1, WantedSalary: 9000})
+
+	resp, res := serveTestRequest(t, s, http.MethodGet, "/api/applications?limit=2&sort=-wantedSalary&statusId=2", 1, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 3.0, res["total"])
+	assert.Equal(t, 2, len(res["applications"].([]interface{})))
+	assert.NotNil(t, res["nextCursor"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/applications?limit=2&sort=-wantedSalary&statusId=2&cursor="+res["nextCursor"].(string), 1, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["applications"].([]interface{})))
+	assert.Nil(t, res["nextCursor"])
+}
+
+func TestRouteGetApplicationsInvalidQuery(t *testing.T) {
+	s, _ := newTestService()
+
+	for _, query := range []string{"limit=0", "sort=password", "statusId=foo", "submissionDateFrom=yesterday", "cursor=foo"} {
+		resp, res := serveTestRequest(t, s, http.MethodGet, "/api/applications?"+query, 1, nil)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+		assert.NotNil(t, res["message"])
+	}
+}
+
+func TestRouteSearchApplications(t *testing.T) {
+	s, store := newTestService()
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Go Developer"})
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 1, JobTitle: "Accountant"})
+
+	resp, res := serveTestRequest(t, s, http.MethodGet, "/api/applications/search?q=developer", 1, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	results := res["results"].([]interface{})
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "Go <mark>Developer</mark>", results[0].(map[string]interface{})["highlights"].(map[string]interface{})["jobTitle"])
+
+	// The search must not shadow the other applications routes.
+	resp, _ = serveTestRequest(t, s, http.MethodGet, "/api/applications/1", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestRouteSearchApplicationsEmptyQuery(t *testing.T) {
+	s, _ := newTestService()
+
+	resp, _ := serveTestRequest(t, s, http.MethodGet, "/api/applications/search?q=", 1, nil)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRouteGetStatusHistory(t *testing.T) {
+	s, store := newTestService()
+	id, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+
+	application, _ := store.GetApplication(id)
+	application.StatusId = 3
+	requestBuffer := new(bytes.Buffer)
+	json.NewEncoder(requestBuffer).Encode(application)
+	serveTestRequest(t, s, http.MethodPut, "/api/applications", 1, requestBuffer)
+
+	resp, res := serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/history", id), 1, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 2, len(res["history"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d/history", id), 2, nil)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
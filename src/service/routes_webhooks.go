@@ -0,0 +1,235 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/webhook"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+type webhookRequest struct {
+	Url    string   `json:"url"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// validateWebhook checks the url and the events of a webhook and removes
+// duplicate events.
+func validateWebhook(hook *controller.Webhook) error {
+	target, err := url.Parse(hook.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" || len(hook.Url) > 2000 {
+		return errors.New("the url of a webhook must be an absolute http or https url of up to 2000 characters")
+	}
+
+	if len(hook.Events) == 0 {
+		return errors.New("a webhook must subscribe to at least one event")
+	}
+
+	var events []string
+	for _, event := range hook.Events {
+		if !containsString(controller.ApplicationEvents, event) {
+			return fmt.Errorf("the event %s does not exist", event)
+		}
+
+		if !containsString(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	hook.Events = events
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, other := range values {
+		if other == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ownedWebhook fetches the webhook referenced by the id parameter and makes
+// sure, it belongs to the requesting user. Otherwise an error response is
+// written and false is returned.
+func (s ApplicationService) ownedWebhook(w http.ResponseWriter, p httprouter.Params) (controller.Webhook, bool) {
+	webhookId, err := strconv.Atoi(p.ByName("id"))
+	if err != nil {
+		ApiResponse(w, "Error while parsing the webhook id", http.StatusBadRequest)
+		return controller.Webhook{}, false
+	}
+
+	hook, err := s.WebhookController.GetWebhook(webhookId)
+	if err != nil {
+		ApiResponse(w, "This webhook does not exist", http.StatusBadRequest)
+		return controller.Webhook{}, false
+	}
+
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	if hook.UserId != userId {
+		ApiResponse(w, "You are not allowed to access this webhook", http.StatusUnauthorized)
+		return controller.Webhook{}, false
+	}
+
+	return hook, true
+}
+
+// handleGetWebhooks returns the webhooks of the user without their secrets.
+// The secret is only returned, when a webhook is created.
+func (s ApplicationService) handleGetWebhooks(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	hooks, err := s.WebhookController.GetWebhooks(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	if hooks == nil {
+		hooks = []controller.Webhook{}
+	}
+
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched webhooks", map[string]interface{}{
+		"webhooks": hooks,
+	}))
+}
+
+func (s ApplicationService) handleCreateWebhook(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	var request webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	hook := controller.Webhook{Url: request.Url, Events: request.Events, Active: request.Active == nil || *request.Active}
+	if err := validateWebhook(&hook); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	if hook.Secret, err = webhook.NewSecret(); err != nil {
+		ApiResponse(w, "Could not create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	hook.UserId, _ = strconv.Atoi(p.ByName("userId"))
+
+	id, err := s.WebhookController.InsertWebhook(hook)
+	if err != nil {
+		ApiResponse(w, "Could not create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	newHook, _ := s.WebhookController.GetWebhook(id)
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Webhook created", map[string]interface{}{
+		"webhook": newHook,
+	}))
+}
+
+func (s ApplicationService) handleGetWebhook(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	hook, ok := s.ownedWebhook(w, p)
+	if !ok {
+		return
+	}
+
+	hook.Secret = ""
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched webhook", map[string]interface{}{
+		"webhook": hook,
+	}))
+}
+
+// handleUpdateWebhook changes the url, the events and the activation of a
+// webhook. Deliveries of inactive webhooks are kept pending, until the
+// webhook is activated again.
+func (s ApplicationService) handleUpdateWebhook(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	hook, ok := s.ownedWebhook(w, p)
+	if !ok {
+		return
+	}
+
+	var request webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	hook.Url = request.Url
+	hook.Events = request.Events
+	if request.Active != nil {
+		hook.Active = *request.Active
+	}
+
+	if err := validateWebhook(&hook); err != nil {
+		ApiResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.WebhookController.UpdateWebhook(hook); err != nil {
+		ApiResponse(w, "Could not update webhook", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Webhook updated", http.StatusOK)
+}
+
+func (s ApplicationService) handleDeleteWebhook(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	hook, ok := s.ownedWebhook(w, p)
+	if !ok {
+		return
+	}
+
+	if err := s.WebhookController.DeleteWebhook(hook.Id); err != nil {
+		ApiResponse(w, "Could not delete webhook", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Webhook deleted", http.StatusOK)
+}
+
+// handleGetWebhookDeliveries returns the delivery log of a webhook, newest
+// first. The number of deliveries is limited by the limit parameter.
+func (s ApplicationService) handleGetWebhookDeliveries(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	hook, ok := s.ownedWebhook(w, p)
+	if !ok {
+		return
+	}
+
+	limit := defaultDeliveryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxDeliveryLimit {
+			ApiResponse(w, fmt.Sprintf("The limit must be between 1 and %d", maxDeliveryLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := s.WebhookController.GetWebhookDeliveries(hook.Id, limit)
+	if err != nil {
+		ApiResponse(w, "Could not fetch the deliveries of the webhook", http.StatusInternalServerError)
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []controller.WebhookDelivery{}
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched deliveries", map[string]interface{}{
+		"deliveries": deliveries,
+	}))
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/webhook"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteWebhooks(t *testing.T) {
+	s, _ := newTestService()
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, "/api/webhooks", 1, bytes.NewBufferString(`{"url": "ftp://example.com", "events": ["application.created"]}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/webhooks", 1, bytes.NewBufferString(`{"url": "https://example.com/hook", "events": []}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/webhooks", 1, bytes.NewBufferString(`{"url": "https://example.com/hook", "events": ["application.archived"]}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/webhooks", 1,
+		bytes.NewBufferString(`{"url": "https://example.com/hook", "events": ["application.created", "application.created"]}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	hook := res["webhook"].(map[string]interface{})
+	assert.Equal(t, 64, len(hook["secret"].(string)))
+	assert.Equal(t, []interface{}{"application.created"}, hook["events"])
+	assert.Equal(t, true, hook["active"])
+	path := fmt.Sprintf("/api/webhooks/%v", hook["id"])
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, "/api/webhooks", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	hooks := res["webhooks"].([]interface{})
+	assert.Equal(t, 1, len(hooks))
+	assert.Nil(t, hooks[0].(map[string]interface{})["secret"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, path, 2, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, path, 1,
+		bytes.NewBufferString(`{"url": "https://example.com/other", "events": ["application.deleted"], "active": false}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, path, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	hook = res["webhook"].(map[string]interface{})
+	assert.Equal(t, "https://example.com/other", hook["url"])
+	assert.Equal(t, false, hook["active"])
+	assert.Nil(t, hook["secret"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, path+"/deliveries?limit=0", 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, path+"/deliveries", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, []interface{}{}, res["deliveries"])
+
+	resp, _ = serveTestRequest(t, s, http.MethodDelete, path, 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodGet, path, 1, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []webhook.Body
+	var signatures []bool
+	fail := true
+
+	var secret string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		content, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		signatures = append(signatures, webhook.Verify(secret, timestamp, content, r.Header.Get(webhook.HeaderSignature)))
+
+		var body webhook.Body
+		json.Unmarshal(content, &body)
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+
+	repositories := MemoryRepositories(controller.NewMemoryStore())
+	repositories.WebhookSender = webhook.NewSender(true)
+	s := NewServiceWithRepositories(ApplicationServiceConfig{Jwt: JwtConfig{SignKey: []byte("supersecretsignkey")}}, repositories)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, "/api/webhooks", 1,
+		bytes.NewBufferString(fmt.Sprintf(`{"url": %q, "events": ["application.created", "application.status_changed"]}`, server.URL)))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	hook := res["webhook"].(map[string]interface{})
+	secret = hook["secret"].(string)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, "/api/applications", 1, bytes.NewBufferString(`{"JobTitle": "Go Developer", "WorkTypeId": 1, "StatusId": 2}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	now := time.Now()
+	attempted, err := s.DeliverWebhooks(context.Background(), now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempted)
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/webhooks/%v/deliveries", hook["id"]), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	delivery := res["deliveries"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "pending", delivery["status"])
+	assert.Equal(t, float64(1), delivery["attempts"])
+	assert.Equal(t, float64(500), delivery["responseStatus"])
+
+	// The delivery is retried after the backoff.
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+
+	attempted, _ = s.DeliverWebhooks(context.Background(), now)
+	assert.Equal(t, 0, attempted)
+
+	attempted, _ = s.DeliverWebhooks(context.Background(), now.Add(webhook.Backoff(1)))
+	assert.Equal(t, 1, attempted)
+
+	mu.Lock()
+	assert.Equal(t, 1, len(bodies))
+	assert.Equal(t, "application.created", bodies[0].Event)
+	assert.Equal(t, []bool{true}, signatures)
+	mu.Unlock()
+
+	resp, res = serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/webhooks/%v/deliveries", hook["id"]), 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	delivery = res["deliveries"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "succeeded", delivery["status"])
+	assert.Equal(t, float64(2), delivery["attempts"])
+	assert.Nil(t, delivery["nextAttemptAt"])
+}
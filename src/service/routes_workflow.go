@@ -0,0 +1,138 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type transitionRequest struct {
+	StatusId int  `json:"statusId"`
+	Reopen   bool `json:"reopen"`
+}
+
+// checkTransition validates a status transition against the workflow of the
+// user. Otherwise an error response is written and false is returned.
+func (s ApplicationService) checkTransition(w http.ResponseWriter, userId int, fromStatusId int, toStatusId int, reopen bool) bool {
+	workflow, err := s.TypesController.GetWorkflow(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch the workflow", http.StatusInternalServerError)
+		return false
+	}
+
+	if err := workflow.CheckTransition(fromStatusId, toStatusId, reopen); err != nil {
+		if errors.Is(err, controller.ErrReopenRequired) {
+			ApiResponse(w, fmt.Sprintf("The transition from status %d to %d requires to reopen the application", fromStatusId, toStatusId), http.StatusConflict)
+			return false
+		}
+
+		ApiResponse(w, fmt.Sprintf("The transition from status %d to %d is not allowed by your workflow", fromStatusId, toStatusId), http.StatusConflict)
+		return false
+	}
+
+	return true
+}
+
+// writeUpdateError writes the response to a failed update of an application,
+// which was checked against the workflow. A conflict is reported, if the
+// status changed in the meantime.
+func writeUpdateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, controller.ErrStatusChanged) {
+		ApiResponse(w, "The status of the application was changed in the meantime", http.StatusConflict)
+		return
+	}
+
+	ApiResponse(w, "Could not update application", http.StatusInternalServerError)
+}
+
+func (s ApplicationService) handleTransitionApplication(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	application, ok := s.ownedApplication(w, p)
+	if !ok {
+		return
+	}
+
+	var request transitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	if !s.isStatusUsable(request.StatusId, application.UserId) {
+		ApiResponse(w, "You cannot use a status of another user", http.StatusBadRequest)
+		return
+	}
+
+	if !s.checkTransition(w, application.UserId, application.StatusId, request.StatusId, request.Reopen) {
+		return
+	}
+
+	fromStatusId := application.StatusId
+	application.StatusId = request.StatusId
+	if err := s.ApplicationController.UpdateApplicationFromStatus(application, fromStatusId); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+
+	s.notifyStatusChange(application, fromStatusId)
+
+	// The application is returned like by handleGetApplication.
+	application, err := s.ApplicationController.GetApplication(application.Id)
+	if err != nil {
+		ApiResponse(w, "Could not fetch application", http.StatusInternalServerError)
+		return
+	}
+
+	applications := []controller.Application{application}
+	if err := s.withDetails(applications); err != nil {
+		ApiResponse(w, "Could not fetch application", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Application status changed", map[string]interface{}{
+		"application": applications[0],
+	}))
+}
+
+func (s ApplicationService) handleGetWorkflow(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	userId, _ := strconv.Atoi(p.ByName("userId"))
+	workflow, err := s.TypesController.GetWorkflow(userId)
+	if err != nil {
+		ApiResponse(w, "Could not fetch the workflow", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, NewApiResponseObject(http.StatusOK, "Fetched workflow", map[string]interface{}{
+		"workflow": workflow,
+	}))
+}
+
+func (s ApplicationService) handleSetWorkflow(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	var workflow controller.Workflow
+	if err := json.NewDecoder(r.Body).Decode(&workflow); err != nil {
+		ApiResponse(w, "Could not parse request body", http.StatusBadRequest)
+		return
+	}
+
+	workflow.UserId, _ = strconv.Atoi(p.ByName("userId"))
+
+	for _, transition := range workflow.Transitions {
+		for _, statusId := range []int{transition.FromStatusId, transition.ToStatusId} {
+			if _, err := s.TypesController.GetStatus(statusId); err != nil || !s.isStatusUsable(statusId, workflow.UserId) {
+				ApiResponse(w, fmt.Sprintf("The status %d does not exist", statusId), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	if err := s.TypesController.SetWorkflow(workflow); err != nil {
+		ApiResponse(w, "Could not update the workflow", http.StatusInternalServerError)
+		return
+	}
+
+	ApiResponse(w, "Workflow updated", http.StatusOK)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/controller"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newWorkflowTestService creates a service, whose user 1 may only move
+// applications from Pending to Declined and has to reopen them to go back.
+func newWorkflowTestService() (ApplicationService, *controller.MemoryStore, int) {
+	s, store := newTestService()
+	store.SetWorkflow(controller.Workflow{UserId: 1, Transitions: []controller.StatusTransition{
+		{FromStatusId: 2, ToStatusId: 3},
+		{FromStatusId: 3, ToStatusId: 2, RequiresReopen: true},
+	}})
+
+	id, _ := store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2})
+	return s, store, id
+}
+
+func TestRouteTransitionApplication(t *testing.T) {
+	s, store, id := newWorkflowTestService()
+	path := fmt.Sprintf("/api/applications/%d/transitions", id)
+
+	resp, res := serveTestRequest(t, s, http.MethodPost, path, 1, bytes.NewBufferString(`{"statusId": 3}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The application is returned like by the application endpoint.
+	_, fetched := serveTestRequest(t, s, http.MethodGet, fmt.Sprintf("/api/applications/%d", id), 1, nil)
+	assert.Equal(t, fetched["application"], res["application"])
+	assert.Equal(t, float64(3), res["application"].(map[string]interface{})["StatusId"])
+	assert.Equal(t, float64(0), res["application"].(map[string]interface{})["daysInStatus"])
+
+	resp, res = serveTestRequest(t, s, http.MethodPost, path, 1, bytes.NewBufferString(`{"statusId": 2}`))
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, res["message"], "reopen")
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, path, 1, bytes.NewBufferString(`{"statusId": 2, "reopen": true}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, _ = serveTestRequest(t, s, http.MethodPost, path, 1, bytes.NewBufferString(`{"statusId": 1}`))
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	history, _ := store.GetStatusHistory(id)
+	assert.Equal(t, 3, len(history))
+}
+
+func TestRouteTransitionApplicationNotOwner(t *testing.T) {
+	s, _, id := newWorkflowTestService()
+
+	resp, _ := serveTestRequest(t, s, http.MethodPost, fmt.Sprintf("/api/applications/%d/transitions", id), 2, bytes.NewBufferString(`{"statusId": 3}`))
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestRouteUpdateApplicationIllegalTransition(t *testing.T) {
+	s, store, id := newWorkflowTestService()
+
+	application, _ := store.GetApplication(id)
+	application.StatusId = 1
+
+	requestBuffer := new(bytes.Buffer)
+	json.NewEncoder(requestBuffer).Encode(application)
+	resp, _ := serveTestRequest(t, s, http.MethodPut, "/api/applications", 1, requestBuffer)
+
+	application, _ = store.GetApplication(id)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Equal(t, 2, application.StatusId)
+}
+
+func TestRouteSetWorkflow(t *testing.T) {
+	s, _ := newTestService()
+
+	resp, _ := serveTestRequest(t, s, http.MethodPut, "/api/workflow", 1, bytes.NewBufferString(`{"transitions": [{"fromStatusId": 2, "toStatusId": 1}]}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, res := serveTestRequest(t, s, http.MethodGet, "/api/workflow", 1, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 1, len(res["workflow"].(map[string]interface{})["transitions"].([]interface{})))
+
+	resp, _ = serveTestRequest(t, s, http.MethodPut, "/api/workflow", 1, bytes.NewBufferString(`{"transitions": [{"fromStatusId": 2, "toStatusId": 42}]}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/notify"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// SchedulerConfig configures the background jobs of the service. Zero values
+// select the defaults.
+type SchedulerConfig struct {
+	// Interval is the time between two runs of the jobs.
+	Interval time.Duration
+
+	// BatchSize limits the number of reminders fired, digests sent and
+	// webhook deliveries attempted in one batch.
+	BatchSize int
+}
+
+const (
+	defaultSchedulerInterval = time.Minute
+	defaultSchedulerBatch    = 100
+
+	// digestPeriod is the time between two digests of a user.
+	digestPeriod = 7 * 24 * time.Hour
+)
+
+func (c SchedulerConfig) interval() time.Duration {
+	if c.Interval <= 0 {
+		return defaultSchedulerInterval
+	}
+
+	return c.Interval
+}
+
+func (c SchedulerConfig) batchSize() int {
+	if c.BatchSize <= 0 {
+		return defaultSchedulerBatch
+	}
+
+	return c.BatchSize
+}
+
+// RunScheduler runs the background jobs once per interval until the context
+// is cancelled. Several replicas may run the scheduler concurrently.
+func (s ApplicationService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.Config.Scheduler.interval())
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.FireReminders(ctx, time.Now()); err != nil {
+			log.Printf("Could not fire reminders: %v", err)
+		}
+
+		if _, err := s.MarkStaleApplications(ctx, time.Now()); err != nil {
+			log.Printf("Could not mark stale applications: %v", err)
+		}
+
+		if _, err := s.SendDigests(ctx, time.Now()); err != nil {
+			log.Printf("Could not send digests: %v", err)
+		}
+
+		if _, err := s.DeliverWebhooks(ctx, time.Now()); err != nil {
+			log.Printf("Could not deliver webhooks: %v", err)
+		}
+
+		if _, err := s.EventController.PruneEvents(time.Now().Add(-s.Config.Events.retention())); err != nil {
+			log.Printf("Could not prune events: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FireReminders sends a notification for every reminder, which is due at the
+// given time, and returns the number of fired reminders. A reminder is fired
+// again by a later run, if its notification could not be delivered.
+func (s ApplicationService) FireReminders(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+
+	for {
+		fired, err := s.ReminderController.FireDueReminders(now, s.Config.Scheduler.batchSize(), func(reminder controller.Reminder) error {
+			application, err := s.ApplicationController.GetApplication(reminder.ApplicationId)
+			if err != nil {
+				return err
+			}
+
+			return s.sendNotification(ctx, notify.Notification{
+				Event:       notify.EventReminder,
+				UserId:      application.UserId,
+				Application: application,
+				Message:     reminder.Message,
+			})
+		})
+
+		total += fired
+		if err != nil || fired < s.Config.Scheduler.batchSize() || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// MarkStaleApplications marks the applications, which have reached the stale
+// threshold of their status, and notifies their owners. It returns the number
+// of marked applications. Applications are marked once, so their owners are
+// notified once, even if the notification fails.
+func (s ApplicationService) MarkStaleApplications(ctx context.Context, now time.Time) (int, error) {
+	applications, err := s.ApplicationController.MarkStaleApplications(now)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, application := range applications {
+		message := fmt.Sprintf("No progress for %d days", daysBetween(application.StatusChangedAt, now))
+		if !application.Stale {
+			message = "Moved to another status after no progress"
+			if status, err := s.TypesController.GetStatus(application.StatusId); err == nil {
+				message = fmt.Sprintf("Moved to %s after no progress", status.Name)
+			}
+		}
+
+		err := s.sendNotification(ctx, notify.Notification{
+			Event:       notify.EventStale,
+			UserId:      application.UserId,
+			Application: application,
+			Message:     message,
+		})
+
+		if err != nil {
+			log.Printf("Could not notify user %d about stale application %d: %v", application.UserId, application.Id, err)
+		}
+	}
+
+	return len(applications), nil
+}
+
+// SendDigests sends the weekly digest to the users with an email address,
+// whose last digest is due at the given time, and returns the number of sent
+// digests. Users, who disabled the digest, are skipped until their next
+// digest is due.
+func (s ApplicationService) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+
+	for {
+		sent, err := s.NotificationController.SendDueDigests(now, digestPeriod, s.Config.Scheduler.batchSize(), func(preferences controller.NotificationPreferences) error {
+			if !preferences.Enabled(notify.EventDigest) {
+				return nil
+			}
+
+			digest, err := s.buildDigest(preferences.UserId, preferences.DigestSentAt, now)
+			if err != nil {
+				return err
+			}
+
+			return s.Notifier.Notify(ctx, notify.Notification{
+				Event:   notify.EventDigest,
+				UserId:  preferences.UserId,
+				Email:   preferences.Email,
+				Message: fmt.Sprintf("%d submitted, %d changed, %d stale", len(digest.Submitted), len(digest.Changed), len(digest.Stale)),
+				Digest:  &digest,
+			})
+		})
+
+		total += sent
+		if err != nil || sent < s.Config.Scheduler.batchSize() || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// DeliverWebhooks fans the events in the outbox out to the subscribed
+// webhooks and attempts the deliveries, which are due at the given time. It
+// returns the number of attempted deliveries.
+func (s ApplicationService) DeliverWebhooks(ctx context.Context, now time.Time) (int, error) {
+	for {
+		dispatched, err := s.WebhookController.DispatchEvents(now, s.Config.Scheduler.batchSize())
+		if err != nil {
+			return 0, err
+		}
+
+		if dispatched < s.Config.Scheduler.batchSize() || ctx.Err() != nil {
+			break
+		}
+	}
+
+	total := 0
+	for {
+		attempted, err := s.WebhookController.DeliverDueWebhooks(now, s.Config.Scheduler.batchSize(), func(hook controller.Webhook, delivery controller.WebhookDelivery) controller.WebhookDelivery {
+			return s.WebhookSender.Deliver(ctx, hook, delivery, now)
+		})
+
+		total += attempted
+		if err != nil || attempted < s.Config.Scheduler.batchSize() || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// buildDigest summarizes the applications of the user between since and
+// until.
+func (s ApplicationService) buildDigest(userId int, since time.Time, until time.Time) (notify.Digest, error) {
+	page, err := s.ApplicationController.QueryApplications(userId, controller.ApplicationQuery{
+		Sort: []controller.SortField{{Field: "submissionDate"}, {Field: "id"}},
+	})
+	if err != nil {
+		return notify.Digest{}, err
+	}
+
+	digest := notify.Digest{Since: since, Until: until}
+	counts := map[int]int{}
+	var statuses []controller.ApplicationStatus
+
+	for _, application := range page.Applications {
+		if counts[application.StatusId] == 0 {
+			status, err := s.TypesController.GetStatus(application.StatusId)
+			if err != nil {
+				return notify.Digest{}, err
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		counts[application.StatusId]++
+
+		if !application.SubmissionDate.Before(truncateDay(since)) {
+			digest.Submitted = append(digest.Submitted, application)
+		} else if application.StatusChangedAt.After(since) {
+			digest.Changed = append(digest.Changed, application)
+		}
+
+		if application.Stale {
+			digest.Stale = append(digest.Stale, application)
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Position != statuses[j].Position {
+			return statuses[i].Position < statuses[j].Position
+		}
+
+		return statuses[i].Id < statuses[j].Id
+	})
+
+	for _, status := range statuses {
+		digest.Statuses = append(digest.Statuses, notify.StatusCount{Status: status.Name, Count: counts[status.Id]})
+	}
+
+	return digest, nil
+}
+
+// truncateDay drops the time of day. Submission dates are stored without it.
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/notify"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkStaleApplications(t *testing.T) {
+	store := controller.NewMemoryStore()
+	repositories := MemoryRepositories(store)
+	notifier := repositories.Notifier.(*notify.MemoryNotifier)
+	s := NewServiceWithRepositories(ApplicationServiceConfig{}, repositories)
+
+	now := time.Now()
+	ghostedId, _ := store.InsertStatus(controller.ApplicationStatus{Name: "Ghosted", Terminal: true})
+	days := 10
+	waitingId, _ := store.InsertStatus(controller.ApplicationStatus{Name: "Waiting", StaleAfterDays: &days, StaleStatusId: &ghostedId})
+
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, SubmissionDate: now.AddDate(0, 0, -40)})
+	store.InsertApplication(controller.Application{UserId: 2, WorkTypeId: 1, StatusId: waitingId, SubmissionDate: now.AddDate(0, 0, -12)})
+
+	marked, err := s.MarkStaleApplications(context.Background(), now)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, marked)
+
+	notifications := notifier.Notifications()
+	assert.Equal(t, 2, len(notifications))
+	assert.Equal(t, notify.EventStale, notifications[0].Event)
+	assert.Equal(t, 1, notifications[0].UserId)
+	assert.Contains(t, notifications[0].Message, "40 days")
+	assert.Equal(t, "Moved to Ghosted after no progress", notifications[1].Message)
+}
+
+func TestSendDigests(t *testing.T) {
+	store := controller.NewMemoryStore()
+	repositories := MemoryRepositories(store)
+	notifier := repositories.Notifier.(*notify.MemoryNotifier)
+	s := NewServiceWithRepositories(ApplicationServiceConfig{}, repositories)
+
+	now := time.Now()
+	store.SaveNotificationPreferences(controller.NotificationPreferences{UserId: 1, Email: "jane@example.com"})
+	store.SaveNotificationPreferences(controller.NotificationPreferences{UserId: 2, Email: "john@example.com", DisabledEvents: []string{notify.EventDigest}})
+
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Go Developer", SubmissionDate: now.AddDate(0, 0, 3)})
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 2, JobTitle: "Rust Developer", SubmissionDate: now.AddDate(0, 0, -60)})
+	store.InsertApplication(controller.Application{UserId: 1, WorkTypeId: 1, StatusId: 3, JobTitle: "Java Developer", SubmissionDate: now.AddDate(0, 0, -20)})
+	store.InsertApplication(controller.Application{UserId: 2, WorkTypeId: 1, StatusId: 2})
+	store.MarkStaleApplications(now)
+
+	sent, err := s.SendDigests(context.Background(), now)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+
+	later := now.Add(digestPeriod + time.Minute)
+	sent, err = s.SendDigests(context.Background(), later)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, sent)
+
+	notifications := notifier.Notifications()
+	assert.Equal(t, 1, len(notifications))
+	assert.Equal(t, notify.EventDigest, notifications[0].Event)
+	assert.Equal(t, "jane@example.com", notifications[0].Email)
+
+	digest := notifications[0].Digest
+	assert.Equal(t, []notify.StatusCount{{Status: "Pending", Count: 2}, {Status: "Declined", Count: 1}}, digest.Statuses)
+	assert.Equal(t, 1, len(digest.Submitted))
+	assert.Equal(t, "Go Developer", digest.Submitted[0].JobTitle)
+	assert.Equal(t, 1, len(digest.Stale))
+	assert.Equal(t, "Rust Developer", digest.Stale[0].JobTitle)
+
+	sent, _ = s.SendDigests(context.Background(), later)
+	assert.Equal(t, 0, sent)
+}
@@ -1,8 +1,14 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/migrations"
+	"flhansen/application-manager/application-service/src/notify"
+	"flhansen/application-manager/application-service/src/posting"
+	"flhansen/application-manager/application-service/src/storage"
+	"flhansen/application-manager/application-service/src/webhook"
 	"fmt"
 	"net/http"
 
@@ -13,18 +19,140 @@ type JwtConfig struct {
 	SignKey interface{}
 }
 
+// StorageMemory selects the in-memory repositories instead of PostgreSQL.
+const StorageMemory = "memory"
+
 type ApplicationServiceConfig struct {
 	Host     string
 	Port     int
 	Jwt      JwtConfig
 	Database controller.DbConfig
+	Storage  string
+
+	// AutoMigrate applies pending database migrations on startup.
+	AutoMigrate bool
+
+	Documents DocumentConfig
+	Scheduler SchedulerConfig
+
+	// Email configures the SMTP server for notifications. Without a host,
+	// notifications are written to the log.
+	Email notify.SMTPConfig
+
+	Events EventConfig
+}
+
+// DocumentConfig configures the storage of files attached to applications.
+// Limits of zero select the defaults.
+type DocumentConfig struct {
+	Blobs       storage.BlobStoreConfig
+	MaxFileSize int64
+	UserQuota   int64
+}
+
+const (
+	defaultMaxFileSize = 10 << 20
+	defaultUserQuota   = 100 << 20
+)
+
+func (c DocumentConfig) maxFileSize() int64 {
+	if c.MaxFileSize <= 0 {
+		return defaultMaxFileSize
+	}
+
+	return c.MaxFileSize
+}
+
+func (c DocumentConfig) userQuota() int64 {
+	if c.UserQuota <= 0 {
+		return defaultUserQuota
+	}
+
+	return c.UserQuota
+}
+
+// Repositories bundles the storage used by the service.
+type Repositories struct {
+	Applications  controller.ApplicationRepository
+	Types         controller.TypesRepository
+	Interviews    controller.InterviewRepository
+	Contacts      controller.ContactRepository
+	Companies     controller.CompanyRepository
+	Attachments   controller.AttachmentRepository
+	Documents     controller.DocumentRepository
+	Tags          controller.TagRepository
+	CustomFields  controller.CustomFieldRepository
+	Reminders     controller.ReminderRepository
+	Notifications controller.NotificationRepository
+	Webhooks      controller.WebhookRepository
+	Events        controller.EventRepository
+	Calendars     controller.CalendarRepository
+	Accounts      controller.AccountRepository
+	Postings      controller.PostingRepository
+	Blobs         storage.BlobStore
+	Notifier      notify.Notifier
+	Fetcher       posting.Fetcher
+	WebhookSender webhook.Sender
+}
+
+// MemoryRepositories uses the in-memory store for all repositories. The
+// contents of files and the notifications are kept in memory as well, job
+// postings are still fetched and webhooks delivered over HTTP.
+func MemoryRepositories(store *controller.MemoryStore) Repositories {
+	return Repositories{
+		Applications:  store,
+		Types:         store,
+		Interviews:    store,
+		Contacts:      store,
+		Companies:     store,
+		Attachments:   store,
+		Documents:     store,
+		Tags:          store,
+		CustomFields:  store,
+		Reminders:     store,
+		Notifications: store,
+		Webhooks:      store,
+		Events:        store,
+		Calendars:     store,
+		Accounts:      store,
+		Postings:      store,
+		Blobs:         storage.NewMemoryBlobStore(),
+		Notifier:      notify.NewMemoryNotifier(),
+		Fetcher:       posting.NewHTTPFetcher(false),
+		WebhookSender: webhook.NewSender(false),
+	}
 }
 
 type ApplicationService struct {
-	Config                ApplicationServiceConfig
-	Router                *httprouter.Router
-	ApplicationController *controller.ApplicationController
-	TypesController       *controller.TypesController
+	Config                 ApplicationServiceConfig
+	Router                 *httprouter.Router
+	ApplicationController  controller.ApplicationRepository
+	TypesController        controller.TypesRepository
+	InterviewController    controller.InterviewRepository
+	ContactController      controller.ContactRepository
+	CompanyController      controller.CompanyRepository
+	AttachmentController   controller.AttachmentRepository
+	DocumentController     controller.DocumentRepository
+	TagController          controller.TagRepository
+	CustomFieldController  controller.CustomFieldRepository
+	ReminderController     controller.ReminderRepository
+	NotificationController controller.NotificationRepository
+	WebhookController      controller.WebhookRepository
+	EventController        controller.EventRepository
+	CalendarController     controller.CalendarRepository
+	AccountController      controller.AccountRepository
+	PostingController      controller.PostingRepository
+	Blobs                  storage.BlobStore
+	Notifier               notify.Notifier
+	WebhookSender          webhook.Sender
+
+	// PostingFetcher fetches the job postings, from which applications are
+	// drafted.
+	PostingFetcher posting.Fetcher
+
+	// events wakes up the event streams. It is shared by the copies of the
+	// service made by the handlers.
+	events *eventBroker
 }
 
 func NewApiResponse(status int, message string) string {
@@ -61,36 +189,257 @@ func ApiResponse(w http.ResponseWriter, message string, code int) {
 	fmt.Fprint(w, NewApiResponse(code, message))
 }
 
+// withStaticSegments dispatches requests, whose path parameter equals one of
+// the static segments, to the handle of that segment. httprouter does not
+// allow static segments next to a wildcard, e.g. /api/applications/search
+// next to /api/applications/:id.
+func withStaticSegments(param string, static map[string]httprouter.Handle, fallback httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		if handle, ok := static[p.ByName(param)]; ok {
+			handle(w, r, p)
+			return
+		}
+
+		fallback(w, r, p)
+	}
+}
+
+// handleNotFound responds to requests of paths, which only exist for some
+// static segments of withStaticSegments.
+func handleNotFound(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	ApiResponse(w, "Not found", http.StatusNotFound)
+}
+
+// newNotifier sends the notifications as emails, if an SMTP server is
+// configured, and writes them to the log otherwise.
+func newNotifier(config notify.SMTPConfig) notify.Notifier {
+	if config.Host == "" {
+		return notify.NewLogNotifier()
+	}
+
+	return notify.NewEmailNotifier(config)
+}
+
+// NewService creates the service using the storage selected in the
+// configuration. By default the applications are stored in PostgreSQL.
 func NewService(config ApplicationServiceConfig) (ApplicationService, error) {
-	ac, _ := controller.NewApplicationController(config.Database)
+	blobs, err := storage.NewBlobStore(config.Documents.Blobs)
+	if err != nil {
+		return ApplicationService{}, err
+	}
+
+	if config.Storage == StorageMemory {
+		repositories := MemoryRepositories(controller.NewMemoryStore())
+		repositories.Blobs = blobs
+		repositories.Notifier = newNotifier(config.Email)
+		return NewServiceWithRepositories(config, repositories), nil
+	}
+
+	ac, err := controller.NewApplicationController(config.Database)
+	if err != nil {
+		return ApplicationService{}, err
+	}
+
+	if config.AutoMigrate {
+		migrator, err := migrations.NewMigrator(ac.Database)
+		if err != nil {
+			return ApplicationService{}, err
+		}
+
+		if _, err := migrator.Up(); err != nil {
+			return ApplicationService{}, err
+		}
+	}
+
 	tc, err := controller.NewTypesController(config.Database)
 	if err != nil {
 		return ApplicationService{}, err
 	}
 
+	// The other controllers share the connection pool.
+	return NewServiceWithRepositories(config, Repositories{
+		Applications:  &ac,
+		Types:         &tc,
+		Interviews:    &controller.InterviewController{Database: ac.Database, Context: ac.Context},
+		Contacts:      &controller.ContactController{Database: ac.Database, Context: ac.Context},
+		Companies:     &controller.CompanyController{Database: ac.Database, Context: ac.Context},
+		Attachments:   &controller.AttachmentController{Database: ac.Database, Context: ac.Context},
+		Documents:     &controller.DocumentController{Database: ac.Database, Context: ac.Context},
+		Tags:          &controller.TagController{Database: ac.Database, Context: ac.Context},
+		CustomFields:  &controller.CustomFieldController{Database: ac.Database, Context: ac.Context},
+		Reminders:     &controller.ReminderController{Database: ac.Database, Context: ac.Context},
+		Notifications: &controller.NotificationController{Database: ac.Database, Context: ac.Context},
+		Webhooks:      &controller.WebhookController{Database: ac.Database, Context: ac.Context},
+		Events:        &controller.EventController{Database: ac.Database, Context: ac.Context},
+		Calendars:     &controller.CalendarController{Database: ac.Database, Context: ac.Context},
+		Accounts:      &controller.AccountController{Database: ac.Database, Context: ac.Context},
+		Postings:      &controller.PostingController{Database: ac.Database, Context: ac.Context},
+		Blobs:         blobs,
+		Notifier:      newNotifier(config.Email),
+		Fetcher:       posting.NewHTTPFetcher(false),
+		WebhookSender: webhook.NewSender(false),
+	}), nil
+}
+
+// NewServiceWithRepositories creates the service on top of the given
+// repositories.
+func NewServiceWithRepositories(config ApplicationServiceConfig, repositories Repositories) ApplicationService {
 	s := ApplicationService{
-		Config:                config,
-		Router:                httprouter.New(),
-		ApplicationController: &ac,
-		TypesController:       &tc,
+		Config:                 config,
+		Router:                 httprouter.New(),
+		ApplicationController:  repositories.Applications,
+		TypesController:        repositories.Types,
+		InterviewController:    repositories.Interviews,
+		ContactController:      repositories.Contacts,
+		CompanyController:      repositories.Companies,
+		AttachmentController:   repositories.Attachments,
+		DocumentController:     repositories.Documents,
+		TagController:          repositories.Tags,
+		CustomFieldController:  repositories.CustomFields,
+		ReminderController:     repositories.Reminders,
+		NotificationController: repositories.Notifications,
+		WebhookController:      repositories.Webhooks,
+		EventController:        repositories.Events,
+		CalendarController:     repositories.Calendars,
+		AccountController:      repositories.Accounts,
+		PostingController:      repositories.Postings,
+		Blobs:                  repositories.Blobs,
+		Notifier:               repositories.Notifier,
+		WebhookSender:          repositories.WebhookSender,
+		PostingFetcher:         repositories.Fetcher,
+		events:                 newEventBroker(),
 	}
 
 	mw := AuthMiddleware{SignKey: s.Config.Jwt.SignKey}
 
 	// Endpoint: Applications
 	s.Router.GET("/api/applications", mw.Authenticated(s.handleGetApplications))
-	s.Router.GET("/api/applications/:id", mw.Authenticated(s.handleGetApplication))
+	s.Router.GET("/api/applications/:id", mw.Authenticated(withStaticSegments("id", map[string]httprouter.Handle{
+		"search": s.handleSearchApplications,
+		"export": s.handleExportApplications,
+	}, s.handleGetApplication)))
+	s.Router.GET("/api/applications/:id/history", mw.Authenticated(s.handleGetStatusHistory))
+	s.Router.GET("/api/applications/:id/posting", mw.Authenticated(s.handleDownloadPosting))
 	s.Router.POST("/api/applications", mw.Authenticated(s.handleCreateApplication))
+	s.Router.POST("/api/applications/:id", mw.Authenticated(withStaticSegments("id", map[string]httprouter.Handle{
+		"import":   s.handleImportApplications,
+		"from-url": s.handleDraftApplication,
+	}, handleNotFound)))
+	s.Router.POST("/api/applications/:id/transitions", mw.Authenticated(s.handleTransitionApplication))
 	s.Router.DELETE("/api/applications/:id", mw.Authenticated(s.handleDeleteApplication))
 	s.Router.PUT("/api/applications", mw.Authenticated(s.handleUpdateApplication))
 
+	// Endpoint: Interviews
+	s.Router.GET("/api/applications/:id/interviews", mw.Authenticated(s.handleGetInterviews))
+	s.Router.POST("/api/applications/:id/interviews", mw.Authenticated(s.handleCreateInterview))
+	s.Router.GET("/api/applications/:id/interviews/:interviewId", mw.Authenticated(s.handleGetInterview))
+	s.Router.PUT("/api/applications/:id/interviews/:interviewId", mw.Authenticated(s.handleUpdateInterview))
+	s.Router.DELETE("/api/applications/:id/interviews/:interviewId", mw.Authenticated(s.handleDeleteInterview))
+
+	// Endpoint: Reminders
+	s.Router.GET("/api/applications/:id/reminders", mw.Authenticated(s.handleGetReminders))
+	s.Router.POST("/api/applications/:id/reminders", mw.Authenticated(s.handleCreateReminder))
+	s.Router.DELETE("/api/applications/:id/reminders/:reminderId", mw.Authenticated(s.handleDeleteReminder))
+
+	// Endpoint: Documents attached to applications
+	s.Router.GET("/api/applications/:id/documents", mw.Authenticated(s.handleGetAttachments))
+	s.Router.POST("/api/applications/:id/documents", mw.Authenticated(s.handleUploadAttachment))
+	s.Router.GET("/api/applications/:id/documents/:documentId", mw.Authenticated(s.handleDownloadAttachment))
+	s.Router.DELETE("/api/applications/:id/documents/:documentId", mw.Authenticated(s.handleDeleteAttachment))
+
+	// Endpoint: Document library
+	s.Router.GET("/api/documents", mw.Authenticated(s.handleGetDocuments))
+	s.Router.POST("/api/documents", mw.Authenticated(s.handleCreateDocument))
+	s.Router.GET("/api/documents/:id", mw.Authenticated(s.handleGetDocument))
+	s.Router.DELETE("/api/documents/:id", mw.Authenticated(s.handleDeleteDocument))
+	s.Router.GET("/api/documents/:id/versions", mw.Authenticated(s.handleGetDocumentVersions))
+	s.Router.POST("/api/documents/:id/versions", mw.Authenticated(s.handleCreateDocumentVersion))
+	s.Router.GET("/api/documents/:id/versions/:version", mw.Authenticated(s.handleDownloadDocumentVersion))
+	s.Router.GET("/api/documents/:id/versions/:version/applications", mw.Authenticated(s.handleGetVersionApplications))
+
+	// Endpoint: Contacts
+	s.Router.GET("/api/contacts", mw.Authenticated(s.handleGetContacts))
+	s.Router.POST("/api/contacts", mw.Authenticated(s.handleCreateContact))
+	s.Router.GET("/api/contacts/:id", mw.Authenticated(s.handleGetContact))
+	s.Router.PUT("/api/contacts/:id", mw.Authenticated(s.handleUpdateContact))
+	s.Router.DELETE("/api/contacts/:id", mw.Authenticated(s.handleDeleteContact))
+	s.Router.GET("/api/contacts/:id/applications", mw.Authenticated(s.handleGetContactApplications))
+	s.Router.GET("/api/applications/:id/contacts", mw.Authenticated(s.handleGetApplicationContacts))
+	s.Router.PUT("/api/applications/:id/contacts/:contactId", mw.Authenticated(s.handleLinkContact))
+	s.Router.DELETE("/api/applications/:id/contacts/:contactId", mw.Authenticated(s.handleUnlinkContact))
+
+	// Endpoint: Companies
+	s.Router.GET("/api/companies", mw.Authenticated(s.handleGetCompanies))
+	s.Router.POST("/api/companies", mw.Authenticated(s.handleCreateCompany))
+	s.Router.GET("/api/companies/:id", mw.Authenticated(s.handleGetCompany))
+	s.Router.PUT("/api/companies/:id", mw.Authenticated(s.handleUpdateCompany))
+	s.Router.DELETE("/api/companies/:id", mw.Authenticated(s.handleDeleteCompany))
+
+	// Endpoint: Tags
+	s.Router.GET("/api/tags", mw.Authenticated(s.handleGetTags))
+	s.Router.POST("/api/tags", mw.Authenticated(s.handleCreateTag))
+	s.Router.PUT("/api/tags/:id", mw.Authenticated(s.handleUpdateTag))
+	s.Router.DELETE("/api/tags/:id", mw.Authenticated(s.handleDeleteTag))
+	s.Router.POST("/api/tags/:id/applications", mw.Authenticated(s.handleTagApplications))
+	s.Router.DELETE("/api/tags/:id/applications", mw.Authenticated(s.handleUntagApplications))
+	s.Router.GET("/api/applications/:id/tags", mw.Authenticated(s.handleGetApplicationTags))
+
+	// Endpoint: Custom fields
+	s.Router.GET("/api/customfields", mw.Authenticated(s.handleGetCustomFields))
+	s.Router.POST("/api/customfields", mw.Authenticated(s.handleCreateCustomField))
+	s.Router.GET("/api/customfields/:id", mw.Authenticated(s.handleGetCustomField))
+	s.Router.PUT("/api/customfields/:id", mw.Authenticated(s.handleUpdateCustomField))
+	s.Router.DELETE("/api/customfields/:id", mw.Authenticated(s.handleDeleteCustomField))
+
+	// Endpoint: Notification preferences
+	s.Router.GET("/api/notifications/preferences", mw.Authenticated(s.handleGetNotificationPreferences))
+	s.Router.PUT("/api/notifications/preferences", mw.Authenticated(s.handleUpdateNotificationPreferences))
+
+	// Endpoint: Webhooks
+	s.Router.GET("/api/webhooks", mw.Authenticated(s.handleGetWebhooks))
+	s.Router.POST("/api/webhooks", mw.Authenticated(s.handleCreateWebhook))
+	s.Router.GET("/api/webhooks/:id", mw.Authenticated(s.handleGetWebhook))
+	s.Router.PUT("/api/webhooks/:id", mw.Authenticated(s.handleUpdateWebhook))
+	s.Router.DELETE("/api/webhooks/:id", mw.Authenticated(s.handleDeleteWebhook))
+	s.Router.GET("/api/webhooks/:id/deliveries", mw.Authenticated(s.handleGetWebhookDeliveries))
+
+	// Endpoint: Event stream
+	s.Router.GET("/api/events", mw.Authenticated(s.handleEventStream))
+
+	// Endpoint: Calendar feed, authenticated by the token in the url
+	s.Router.GET("/api/calendar/:token", withStaticSegments("token", map[string]httprouter.Handle{
+		"token": mw.Authenticated(s.handleGetCalendarToken),
+	}, s.handleGetCalendarFeed))
+	s.Router.POST("/api/calendar/token", mw.Authenticated(s.handleRotateCalendarToken))
+	s.Router.DELETE("/api/calendar/token", mw.Authenticated(s.handleDeleteCalendarToken))
+
+	// Endpoint: Data of the user
+	s.Router.GET("/api/me/export", mw.Authenticated(s.handleExportUserData))
+	s.Router.DELETE("/api/me", mw.Authenticated(s.handleEraseUserData))
+
 	// Endpoint: Types
 	s.Router.GET("/api/types/worktypes", s.handleGetWorkTypes)
 	s.Router.GET("/api/types/statuses", s.handleGetStatuses)
 
-	return s, nil
+	// Endpoint: Statuses of the user
+	s.Router.GET("/api/statuses", mw.Authenticated(s.handleGetUserStatuses))
+	s.Router.POST("/api/statuses", mw.Authenticated(s.handleCreateStatus))
+	s.Router.PUT("/api/statuses/:id", mw.Authenticated(s.handleUpdateStatus))
+	s.Router.DELETE("/api/statuses/:id", mw.Authenticated(s.handleDeleteStatus))
+	s.Router.GET("/api/workflow", mw.Authenticated(s.handleGetWorkflow))
+	s.Router.PUT("/api/workflow", mw.Authenticated(s.handleSetWorkflow))
+
+	return s
 }
 
+// Start runs the scheduler and the listener of the event stream in the
+// background and serves the API. Both are stopped, when the server stops.
 func (s *ApplicationService) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go s.RunScheduler(ctx)
+	go s.ListenEvents(ctx)
+
 	return http.ListenAndServe(fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), s.Router)
 }
@@ -100,6 +100,21 @@ func TestNewService(t *testing.T) {
 	assert.NotNil(t, s)
 }
 
+func TestNewServiceMemoryStorage(t *testing.T) {
+	s, err := NewService(ApplicationServiceConfig{
+		Host: "localhost",
+		Port: 8080,
+		Jwt: JwtConfig{
+			SignKey: "supersecretsignkey",
+		},
+		Storage: StorageMemory,
+	})
+
+	assert.Nil(t, err)
+	assert.IsType(t, &controller.MemoryStore{}, s.ApplicationController)
+	assert.IsType(t, &controller.MemoryStore{}, s.TypesController)
+}
+
 func TestServiceStart(t *testing.T) {
 	s, err := NewService(ApplicationServiceConfig{
 		Host: "localhost",
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileBlobStore(t *testing.T) {
+	store := NewFileBlobStore(t.TempDir())
+	ctx := context.Background()
+
+	assert.Nil(t, store.Put(ctx, "1/2/cv.pdf", strings.NewReader("content"), 7, "application/pdf"))
+
+	blob, err := store.Get(ctx, "1/2/cv.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := io.ReadAll(blob)
+	blob.Close()
+	assert.Equal(t, "content", string(data))
+
+	assert.Nil(t, store.Delete(ctx, "1/2/cv.pdf"))
+	assert.Nil(t, store.Delete(ctx, "1/2/cv.pdf"))
+
+	_, err = store.Get(ctx, "1/2/cv.pdf")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestFileBlobStoreInvalidKey(t *testing.T) {
+	store := NewFileBlobStore(t.TempDir())
+
+	err := store.Put(context.Background(), "../outside", strings.NewReader("content"), 7, "text/plain")
+
+	assert.NotNil(t, err)
+}
+
+func TestNewBlobStore(t *testing.T) {
+	store, err := NewBlobStore(BlobStoreConfig{})
+	assert.Nil(t, err)
+	assert.IsType(t, &FileBlobStore{}, store)
+
+	store, err = NewBlobStore(BlobStoreConfig{Type: TypeS3, Endpoint: "http://localhost:9000", Bucket: "documents"})
+	assert.Nil(t, err)
+	assert.IsType(t, &S3BlobStore{}, store)
+
+	_, err = NewBlobStore(BlobStoreConfig{Type: TypeS3})
+	assert.NotNil(t, err)
+
+	_, err = NewBlobStore(BlobStoreConfig{Type: "ftp"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal stand-in for an S3-compatible object storage. It
+// verifies the signature of every request by signing it again.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	signer  *S3BlobStore
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	expected := r.Clone(context.Background())
+	f.signer.sign(expected, r.Header.Get("X-Amz-Content-Sha256"))
+	if r.Header.Get("Authorization") != expected.Header.Get("Authorization") {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.EscapedPath()] = string(body)
+	case http.MethodGet:
+		object, ok := f.objects[r.URL.EscapedPath()]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+
+		io.WriteString(w, object)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newTestS3BlobStore(t *testing.T, secretAccessKey string) (*S3BlobStore, *fakeS3) {
+	now := func() time.Time { return time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC) }
+
+	fake := &fakeS3{objects: map[string]string{}, signer: NewS3BlobStore("", "bucket", "eu-central-1", "key", "secret")}
+	fake.signer.now = now
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store := NewS3BlobStore(server.URL, "bucket", "eu-central-1", "key", secretAccessKey)
+	store.now = now
+
+	return store, fake
+}
+
+func TestS3BlobStore(t *testing.T) {
+	store, fake := newTestS3BlobStore(t, "secret")
+	ctx := context.Background()
+
+	content := "%PDF-1.4 curriculum vitae"
+	assert.Nil(t, store.Put(ctx, "1/2/cv v1.pdf", strings.NewReader(content), int64(len(content)), "application/pdf"))
+	assert.Contains(t, fake.objects, "/bucket/1/2/cv%20v1.pdf")
+
+	blob, err := store.Get(ctx, "1/2/cv v1.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := io.ReadAll(blob)
+	blob.Close()
+	assert.Equal(t, content, string(data))
+
+	assert.Nil(t, store.Delete(ctx, "1/2/cv v1.pdf"))
+	assert.Nil(t, store.Delete(ctx, "1/2/cv v1.pdf"))
+
+	_, err = store.Get(ctx, "1/2/cv v1.pdf")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestS3BlobStoreWrongCredentials(t *testing.T) {
+	store, _ := newTestS3BlobStore(t, "wrong")
+
+	err := store.Put(context.Background(), "key", strings.NewReader("data"), 4, "text/plain")
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestURIEncode(t *testing.T) {
+	assert.Equal(t, "a/b%20c/%C3%A4~_-.", uriEncode("a/b c/ä~_-.", false))
+	assert.Equal(t, "a%2Fb", uriEncode("a/b", true))
+}
+
+func TestS3BlobStoreTimeout(t *testing.T) {
+	stalled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stalled
+	}))
+	defer server.Close()
+	defer close(stalled)
+
+	store := NewS3BlobStore(server.URL, "bucket", "eu-central-1", "key", "secret")
+	assert.NotSame(t, http.DefaultClient, store.Client)
+	assert.Equal(t, s3Timeout, store.Client.Timeout)
+
+	store.Client.Timeout = 50 * time.Millisecond
+	_, err := store.Get(context.Background(), "key")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,64 @@
+// Package storage stores the contents of uploaded files. The metadata of the
+// files is kept in the repositories of the controller package.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrBlobNotFound is returned, if a blob with the given key does not exist.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// The types of blob stores.
+const (
+	TypeLocal = "local"
+	TypeS3    = "s3"
+)
+
+// BlobStore stores binary objects under a key. Keys consist of path segments
+// separated by slashes.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type BlobStoreConfig struct {
+	// Type is either local (default) or s3.
+	Type string
+
+	// Directory is the root of the local blob store.
+	Directory string
+
+	// Endpoint, e.g. https://s3.eu-central-1.amazonaws.com, and bucket of an
+	// S3-compatible blob store.
+	Endpoint        string
+	Bucket          string
+	Region          string
+	AccessKeyId     string
+	SecretAccessKey string
+}
+
+// NewBlobStore creates the blob store selected in the configuration.
+func NewBlobStore(config BlobStoreConfig) (BlobStore, error) {
+	switch config.Type {
+	case "", TypeLocal:
+		directory := config.Directory
+		if directory == "" {
+			directory = "documents"
+		}
+
+		return NewFileBlobStore(directory), nil
+	case TypeS3:
+		if config.Endpoint == "" || config.Bucket == "" {
+			return nil, fmt.Errorf("an S3 blob store needs an endpoint and a bucket")
+		}
+
+		return NewS3BlobStore(config.Endpoint, config.Bucket, config.Region, config.AccessKeyId, config.SecretAccessKey), nil
+	default:
+		return nil, fmt.Errorf("unknown blob store type %s", config.Type)
+	}
+}
@@ -0,0 +1,205 @@
+// Package webhook delivers the events of applications to the webhooks of
+// their owners.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flhansen/application-manager/application-service/src/controller"
+	"flhansen/application-manager/application-service/src/netguard"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The headers of a delivery. The signature is the hex encoded HMAC-SHA256 of
+// the timestamp, a dot and the body, keyed with the secret of the webhook,
+// e.g. sha256=5d41402abc4b2a76b9719d911017c592.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// ErrForbiddenAddress is returned, if the url of a webhook leads to a
+// loopback, private or link-local address.
+var ErrForbiddenAddress = errors.New("the webhook is not hosted on a public address")
+
+const (
+	// MaxAttempts is the number of attempts, after which a delivery fails.
+	MaxAttempts = 8
+
+	// initialBackoff is the delay before the second attempt. It doubles
+	// with every further attempt.
+	initialBackoff = 30 * time.Second
+
+	// maxBackoff limits the delay between two attempts.
+	maxBackoff = 6 * time.Hour
+
+	// maxErrorLength limits the error message recorded in the delivery
+	// log.
+	maxErrorLength = 500
+)
+
+// NewSecret creates a random secret for signing the deliveries of a webhook.
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// Sign computes the signature of a delivery.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a delivery in constant time. Receivers
+// should also reject old timestamps to prevent replays.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Backoff returns the delay after the given number of failed attempts.
+func Backoff(attempts int) time.Duration {
+	backoff := initialBackoff
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+
+	return backoff
+}
+
+// Body is the JSON body of a delivery.
+type Body struct {
+	Id        int64           `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Sender posts deliveries to webhooks. The urls are provided by the users, so
+// unless private networks are allowed, the sender refuses to connect to
+// addresses, which are not public.
+type Sender struct {
+	Client *http.Client
+}
+
+// NewSender creates a sender, which gives up on a webhook after ten seconds
+// and does not follow redirects. Private networks should only be allowed for
+// tests.
+func NewSender(allowPrivateNetworks bool) Sender {
+	dialer := netguard.Dialer(allowPrivateNetworks, ErrForbiddenAddress)
+
+	return Sender{Client: &http.Client{
+		Timeout: 10 * time.Second,
+		// Proxies are not used, because they would bypass the check of the
+		// addresses.
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 5 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Deliver attempts the delivery and returns it with the outcome of the
+// attempt. Deliveries, which are not answered with a 2xx status, are retried
+// with exponential backoff until MaxAttempts is reached.
+func (s Sender) Deliver(ctx context.Context, hook controller.Webhook, delivery controller.WebhookDelivery, now time.Time) controller.WebhookDelivery {
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = nil
+	delivery.Error = ""
+
+	status, err := s.post(ctx, hook, delivery, now)
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = controller.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= MaxAttempts:
+		delivery.Status = controller.DeliveryFailed
+		delivery.NextAttemptAt = nil
+	default:
+		nextAttemptAt := now.Add(Backoff(delivery.Attempts))
+		delivery.Status = controller.DeliveryPending
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+
+	if err != nil {
+		delivery.Error = err.Error()
+		if len(delivery.Error) > maxErrorLength {
+			delivery.Error = delivery.Error[:maxErrorLength]
+		}
+	}
+
+	return delivery
+}
+
+// post sends the delivery and returns the response status, if there was a
+// response.
+func (s Sender) post(ctx context.Context, hook controller.Webhook, delivery controller.WebhookDelivery, now time.Time) (int, error) {
+	body, err := json.Marshal(Body{
+		Id:        delivery.EventId,
+		Event:     delivery.Event,
+		CreatedAt: delivery.EventCreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "application-manager-webhooks")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Reading the response allows to reuse the connection.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
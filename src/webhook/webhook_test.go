@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"flhansen/application-manager/application-service/src/controller"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", 1656633600, []byte(`{"id":1}`))
+
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+	assert.True(t, Verify("secret", 1656633600, []byte(`{"id":1}`), signature))
+	assert.False(t, Verify("other", 1656633600, []byte(`{"id":1}`), signature))
+	assert.False(t, Verify("secret", 1656633601, []byte(`{"id":1}`), signature))
+	assert.False(t, Verify("secret", 1656633600, []byte(`{"id":2}`), signature))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 2*time.Minute, Backoff(3))
+	assert.Equal(t, 6*time.Hour, Backoff(20))
+}
+
+func TestNewSecret(t *testing.T) {
+	secret, err := NewSecret()
+	assert.Nil(t, err)
+	assert.Equal(t, 64, len(secret))
+
+	other, _ := NewSecret()
+	assert.NotEqual(t, secret, other)
+}
+
+func TestDeliver(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	hook := controller.Webhook{Id: 1, Url: server.URL, Secret: "secret"}
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	delivery := NewSender(true).Deliver(context.Background(), hook, controller.WebhookDelivery{
+		Id:             3,
+		EventId:        2,
+		Event:          controller.EventApplicationCreated,
+		Payload:        json.RawMessage(`{"application":{"Id":5}}`),
+		EventCreatedAt: now,
+		Status:         controller.DeliveryPending,
+	}, now)
+
+	assert.Equal(t, controller.DeliverySucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.Equal(t, http.StatusOK, *delivery.ResponseStatus)
+	assert.Equal(t, "", delivery.Error)
+
+	assert.Equal(t, controller.EventApplicationCreated, received.Header.Get(HeaderEvent))
+	assert.Equal(t, "3", received.Header.Get(HeaderDelivery))
+	timestamp, _ := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+	assert.Equal(t, now.Unix(), timestamp)
+	assert.True(t, Verify("secret", timestamp, body, received.Header.Get(HeaderSignature)))
+
+	var decoded Body
+	assert.Nil(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, int64(2), decoded.Id)
+	assert.JSONEq(t, `{"application":{"Id":5}}`, string(decoded.Data))
+}
+
+func TestDeliverRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	hook := controller.Webhook{Id: 1, Url: server.URL, Secret: "secret"}
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	delivery := controller.WebhookDelivery{Id: 1, Status: controller.DeliveryPending, Payload: json.RawMessage(`{}`)}
+
+	delivery = NewSender(true).Deliver(context.Background(), hook, delivery, now)
+	assert.Equal(t, controller.DeliveryPending, delivery.Status)
+	assert.Equal(t, now.Add(30*time.Second), *delivery.NextAttemptAt)
+	assert.Equal(t, http.StatusServiceUnavailable, *delivery.ResponseStatus)
+	assert.Equal(t, "unexpected response status 503", delivery.Error)
+
+	delivery.Attempts = MaxAttempts - 1
+	delivery = NewSender(true).Deliver(context.Background(), hook, delivery, now)
+	assert.Equal(t, controller.DeliveryFailed, delivery.Status)
+	assert.Nil(t, delivery.NextAttemptAt)
+
+	// Connection errors are retried as well.
+	server.Close()
+	delivery = NewSender(true).Deliver(context.Background(), hook, controller.WebhookDelivery{Id: 1, Payload: json.RawMessage(`{}`)}, now)
+	assert.Equal(t, controller.DeliveryPending, delivery.Status)
+	assert.Nil(t, delivery.ResponseStatus)
+	assert.NotEqual(t, "", delivery.Error)
+}
+
+func TestDeliverDoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer server.Close()
+
+	delivery := NewSender(true).Deliver(context.Background(), controller.Webhook{Url: server.URL},
+		controller.WebhookDelivery{Id: 1, Payload: json.RawMessage(`{}`)}, time.Now())
+
+	assert.Equal(t, controller.DeliveryPending, delivery.Status)
+	assert.Equal(t, http.StatusFound, *delivery.ResponseStatus)
+}
+
+func TestDeliverRefusesPrivateNetworks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	delivery := NewSender(false).Deliver(context.Background(), controller.Webhook{Url: server.URL},
+		controller.WebhookDelivery{Id: 1, Payload: json.RawMessage(`{}`)}, time.Now())
+
+	assert.Equal(t, controller.DeliveryPending, delivery.Status)
+	assert.Nil(t, delivery.ResponseStatus)
+	assert.Contains(t, delivery.Error, ErrForbiddenAddress.Error())
+}